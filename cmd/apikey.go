@@ -0,0 +1,116 @@
+package main
+
+import (
+	"app/internal/app"
+	"app/internal/auth"
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+const apikeyUsage = "apikey create --name N [--scopes s1,s2] | apikey list | apikey revoke <id>"
+
+func runAPIKey(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "create":
+		fs := flag.NewFlagSet("apikey create", flag.ContinueOnError)
+		name := fs.String("name", "", "who the key is for")
+		scopes := fs.String("scopes", "orders:read", "comma-separated scopes")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 || *name == "" {
+			return errUsage
+		}
+		return apikeyCreate(ctx, *name, splitScopes(*scopes))
+	case "list":
+		if len(args) != 1 {
+			return errUsage
+		}
+		return apikeyList(ctx)
+	case "revoke":
+		if len(args) != 2 {
+			return errUsage
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad id %q", args[1])
+		}
+		return apikeyRevoke(ctx, id)
+	default:
+		return errUsage
+	}
+}
+
+func splitScopes(s string) []string {
+	var out []string
+	for _, scope := range strings.Split(s, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			out = append(out, scope)
+		}
+	}
+	return out
+}
+
+// apikeyCreate печатает ключ один раз: в БД хранится только его хэш.
+func apikeyCreate(ctx context.Context, name string, scopes []string) error {
+	key, hash, err := auth.GenerateKey()
+	if err != nil {
+		return err
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		repo, err := a.DIContainer().APIKeyRepository(ctx)
+		if err != nil {
+			return err
+		}
+		k, err := repo.Create(ctx, name, hash, scopes)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "created key %d (%s) with scopes %v; it is shown only once\n", k.ID, k.Name, k.Scopes)
+		fmt.Println(key)
+		return nil
+	})
+}
+
+func apikeyList(ctx context.Context) error {
+	return withTool(ctx, func(a *app.App) error {
+		repo, err := a.DIContainer().APIKeyRepository(ctx)
+		if err != nil {
+			return err
+		}
+		keys, err := repo.List(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED\tREVOKED")
+		for _, k := range keys {
+			revoked := "-"
+			if k.RevokedAt != nil {
+				revoked = k.RevokedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", k.ID, k.Name, strings.Join(k.Scopes, ","),
+				k.CreatedAt.Format(time.RFC3339), revoked)
+		}
+		return w.Flush()
+	})
+}
+
+func apikeyRevoke(ctx context.Context, id int64) error {
+	return withTool(ctx, func(a *app.App) error {
+		repo, err := a.DIContainer().APIKeyRepository(ctx)
+		if err != nil {
+			return err
+		}
+		return repo.Revoke(ctx, id)
+	})
+}
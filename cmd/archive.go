@@ -0,0 +1,53 @@
+package main
+
+import (
+	"app/internal/app"
+	"context"
+	"errors"
+	"flag"
+	"log"
+)
+
+const archiveUsage = "archive run [--dry-run]"
+
+// runArchive архивирует старые месяцы один раз, не дожидаясь фонового
+// job'а. С --dry-run файлы выгружаются и проверяются, но строки остаются в БД.
+func runArchive(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "run" {
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet("archive run", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "export and verify files without deleting rows")
+	if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		pool, err := a.DIContainer().PgxPool(ctx)
+		if err != nil {
+			return err
+		}
+		archiver, err := a.DIContainer().Archiver(ctx, *dryRun)
+		if err != nil {
+			return err
+		}
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Release()
+
+		rep, err := archiver.RunOnce(ctx, conn)
+		if !rep.Leader && err == nil {
+			return errors.New("archive is running on another instance")
+		}
+		for _, m := range rep.Months {
+			log.Printf("[archive] %s: %d orders -> %s deleted=%t", m.Month.Format("2006-01"), m.Orders, m.Key, m.Deleted)
+		}
+		if len(rep.Months) == 0 && err == nil {
+			log.Printf("[archive] nothing to archive before %s", archiver.Cutoff().Format("2006-01"))
+		}
+		return err
+	})
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"app/internal/app"
+	"context"
+	"encoding/json"
+	"os"
+)
+
+const cacheUsage = "cache stats"
+
+func runCache(ctx context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "stats" {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		stats, err := a.DIContainer().CacheStats(ctx)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(os.Stdout).Encode(stats)
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"app/internal/config"
+	"context"
+	"fmt"
+	"os"
+)
+
+const configUsage = "config print | schema | validate"
+
+func runConfig(ctx context.Context, args []string) error {
+	_ = ctx
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "schema":
+		_, err := fmt.Fprint(os.Stdout, config.SchemaMarkdown())
+		return err
+
+	case "print":
+		if err := config.Init(); err != nil {
+			return err
+		}
+		b, err := config.AppConfig.Masked().YAML()
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(b)
+		return err
+
+	case "validate":
+		if err := config.Init(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(os.Stdout, "config is valid")
+		return err
+
+	default:
+		return errUsage
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	kaf "app/internal/adapter/kafka"
+	"app/internal/app"
+	"context"
+	"flag"
+	"log"
+	"time"
+)
+
+const dlqUsage = "dlq replay [--group G] [--topic T] [--limit N] [--idle D] [--dry-run]"
+
+func runDLQ(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "replay" {
+		return errUsage
+	}
+
+	fs := flag.NewFlagSet("dlq replay", flag.ContinueOnError)
+	group := fs.String("group", "orders-dlq-replay", "consumer group for reading the DLQ")
+	topic := fs.String("topic", "", "target topic (default: original topic from the envelope)")
+	limit := fs.Int("limit", 0, "max messages to replay (0 = all)")
+	idle := fs.Duration("idle", 5*time.Second, "stop after no new messages for this long")
+	dryRun := fs.Bool("dry-run", false, "decode messages without replaying or committing them")
+	if err := fs.Parse(args[1:]); err != nil {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		reader, err := a.DIContainer().DLQReader(*group)
+		if err != nil {
+			return err
+		}
+		writer, err := a.DIContainer().ReplayWriter()
+		if err != nil {
+			return err
+		}
+
+		stats, err := kaf.ReplayDLQ(ctx, reader, writer, kaf.ReplayOptions{
+			Topic:  *topic,
+			Limit:  *limit,
+			Idle:   *idle,
+			DryRun: *dryRun,
+		})
+		log.Printf("[dlq] replayed=%d skipped=%d dry_run=%t", stats.Replayed, stats.Skipped, *dryRun)
+		return err
+	})
+}
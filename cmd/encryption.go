@@ -0,0 +1,38 @@
+package main
+
+import (
+	"app/internal/app"
+	"app/internal/config"
+	"app/internal/fieldcrypt"
+	"context"
+	"errors"
+	"log"
+)
+
+const encryptionUsage = "encryption reencrypt"
+
+// runEncryption перешифровывает все доставки на активный KEK сразу, не
+// дожидаясь фонового job'а: например, перед удалением старого ключа.
+func runEncryption(ctx context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "reencrypt" {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		c, err := a.DIContainer().Cipher()
+		if err != nil {
+			return err
+		}
+		if c == nil {
+			return errors.New("encryption is disabled")
+		}
+		r, err := a.DIContainer().PrimaryOrderRepository(ctx)
+		if err != nil {
+			return err
+		}
+
+		n, err := fieldcrypt.Reencrypt(ctx, r, config.AppConfig.Encryption.ReencryptBatch)
+		log.Printf("[fieldcrypt] reencrypted %d deliveries to key %s", n, c.ActiveKeyID())
+		return err
+	})
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"app/internal/app"
+	"app/internal/model"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+)
+
+const eraseUsage = "erase [--customer-id ID] [--email E] [--phone P] [--by WHO]"
+
+// runErase удаляет персональные данные субъекта, как POST /admin/erasures,
+// и печатает отчёт с затронутыми заказами.
+func runErase(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("erase", flag.ContinueOnError)
+	var req model.ErasureRequest
+	fs.StringVar(&req.CustomerID, "customer-id", "", "customer_id of the orders")
+	fs.StringVar(&req.Email, "email", "", "delivery email")
+	fs.StringVar(&req.Phone, "phone", "", "delivery phone")
+	fs.StringVar(&req.RequestedBy, "by", "cli:"+os.Getenv("USER"), "who requested the erasure, for the audit log")
+	if err := fs.Parse(args); err != nil || fs.NArg() != 0 {
+		return errUsage
+	}
+	if req.CustomerID == "" && req.Email == "" && req.Phone == "" {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		eraser, err := a.DIContainer().Eraser(ctx)
+		if err != nil {
+			return err
+		}
+		rep, err := eraser.Erase(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	})
+}
@@ -3,47 +3,122 @@ package main
 import (
 	"app/internal/app"
 	"app/internal/closer"
+	"app/internal/config"
 	"context"
 	"errors"
+	"flag"
+	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
 
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"serve", serveUsage, runServe},
+	{"migrate", migrateUsage, runMigrate},
+	{"dlq", dlqUsage, runDLQ},
+	{"order", orderUsage, runOrder},
+	{"cache", cacheUsage, runCache},
+	{"config", configUsage, runConfig},
+	{"partitions", partitionsUsage, runPartitions},
+	{"archive", archiveUsage, runArchive},
+	{"apikey", apikeyUsage, runAPIKey},
+	{"encryption", encryptionUsage, runEncryption},
+	{"erase", eraseUsage, runErase},
+}
+
+var errUsage = errors.New("bad usage")
+
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	application, err := app.NewApp(ctx)
-	if err != nil {
-		log.Fatalf("init app: %v", err)
+	global := flag.NewFlagSet("app", flag.ContinueOnError)
+	global.Usage = printUsage
+	configFile := global.String("config", "", "config file (.yaml, .yml or .toml); default: CONFIG_FILE")
+	overrides := setFlag{}
+	global.Var(overrides, "set", "override a setting, key=value (repeatable)")
+	if err := global.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
 	}
+	config.SetSources(config.Sources{
+		File:       *configFile,
+		Overrides:  overrides,
+		Validators: app.ConfigValidators(),
+	})
 
-	worker, err := application.DIContainer().Worker(ctx)
-	if err != nil {
-		log.Fatalf("init kafka worker: %v", err)
+	name, args := "serve", global.Args()
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
 	}
 
-	errCh := make(chan error, 2)
+	for _, c := range commands {
+		if c.name != name {
+			continue
+		}
+		if err := c.run(ctx, args); err != nil {
+			if errors.Is(err, errUsage) {
+				fmt.Fprintf(os.Stderr, "usage: %s\n", c.usage)
+				os.Exit(2)
+			}
+			log.Fatalf("%s: %v", name, err)
+		}
+		return
+	}
 
-	go func() { errCh <- worker.Run(ctx) }()
-	go func() { errCh <- application.Run(ctx) }()
+	printUsage()
+	if name != "help" && name != "-h" && name != "--help" {
+		os.Exit(2)
+	}
+}
 
-	select {
-	case <-ctx.Done():
-	case err := <-errCh:
-		if err != nil && !errors.Is(err, context.Canceled) {
-			log.Printf("stopped with error: %v", err)
-		}
-		stop()
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: app [--config FILE] [--set key=value ...] <command> [args]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", c.usage)
 	}
+}
+
+// setFlag собирает повторяющиеся --set key=value.
+type setFlag map[string]string
+
+func (f setFlag) String() string { return "" }
+
+func (f setFlag) Set(v string) error {
+	key, val, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("want key=value, got %q", v)
+	}
+	f[strings.TrimSpace(key)] = val
+	return nil
+}
+
+// withTool поднимает приложение без HTTP, worker'а и экспорта телеметрии:
+// зависимости из DI создаются лениво, только те, что нужны команде.
+func withTool(ctx context.Context, fn func(*app.App) error) error {
+	application, err := app.NewApp(ctx, app.AsTool())
+	if err != nil {
+		return err
+	}
+
+	runErr := fn(application)
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
-
 	if err := closer.CloseAll(shutdownCtx); err != nil {
 		log.Printf("closeAll error: %v", err)
 	}
+
+	return runErr
 }
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"app/internal/app"
+	"app/internal/migrate"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const migrateUsage = "migrate up | down [N] | status | to <version>"
+
+func runMigrate(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		pool, err := a.DIContainer().PgxPool(ctx)
+		if err != nil {
+			return err
+		}
+		return migrateWith(ctx, pool, args)
+	})
+}
+
+func migrateWith(ctx context.Context, pool *pgxpool.Pool, args []string) error {
+	return migrate.WithPool(ctx, pool, func(r *migrate.Runner) error {
+		switch args[0] {
+		case "up":
+			n, err := r.Up(ctx)
+			log.Printf("[migrate] applied %d migrations", n)
+			return err
+
+		case "down":
+			steps := 1
+			if len(args) > 1 {
+				var err error
+				steps, err = strconv.Atoi(args[1])
+				if err != nil || steps <= 0 {
+					return fmt.Errorf("bad steps %q", args[1])
+				}
+			}
+			n, err := r.Down(ctx, steps)
+			log.Printf("[migrate] rolled back %d migrations", n)
+			return err
+
+		case "to":
+			if len(args) < 2 {
+				return errUsage
+			}
+			version, err := strconv.ParseInt(args[1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("bad version %q", args[1])
+			}
+			n, err := r.To(ctx, version)
+			log.Printf("[migrate] %d migrations changed", n)
+			return err
+
+		case "status":
+			st, err := r.Status(ctx)
+			if err != nil {
+				return err
+			}
+			return printMigrateStatus(st)
+
+		default:
+			return errUsage
+		}
+	})
+}
+
+func printMigrateStatus(st []migrate.Status) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+	for _, s := range st {
+		status, at := "pending", ""
+		if s.Applied {
+			status, at = "applied", s.AppliedAt.Format(time.RFC3339)
+		}
+		if s.Dirty {
+			status = "checksum mismatch"
+		}
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", s.Version, s.Name, status, at)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"app/internal/adapter/converter"
+	adapterModel "app/internal/adapter/model"
+	"app/internal/app"
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const orderUsage = "order get <uid> | order find (--email E | --phone P) | order import [--stop-on-error] <file.ndjson | ->"
+
+func runOrder(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "get":
+		if len(args) != 2 {
+			return errUsage
+		}
+		return orderGet(ctx, args[1])
+	case "find":
+		fs := flag.NewFlagSet("order find", flag.ContinueOnError)
+		email := fs.String("email", "", "delivery email")
+		phone := fs.String("phone", "", "delivery phone")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 0 || (*email == "") == (*phone == "") {
+			return errUsage
+		}
+		return orderFind(ctx, *email, *phone)
+	case "import":
+		fs := flag.NewFlagSet("order import", flag.ContinueOnError)
+		stopOnError := fs.Bool("stop-on-error", false, "abort on the first bad line")
+		if err := fs.Parse(args[1:]); err != nil || fs.NArg() != 1 {
+			return errUsage
+		}
+		return orderImport(ctx, fs.Arg(0), *stopOnError)
+	default:
+		return errUsage
+	}
+}
+
+func orderGet(ctx context.Context, uid string) error {
+	return withTool(ctx, func(a *app.App) error {
+		repo, err := a.DIContainer().OrderRepository(ctx)
+		if err != nil {
+			return err
+		}
+
+		order, err := repo.GetOrder(ctx, uid)
+		if err != nil {
+			return err
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(order)
+	})
+}
+
+// orderFind печатает uid заказов с заданным email или телефоном доставки.
+// Зашифрованные строки ищутся по blind index.
+func orderFind(ctx context.Context, email, phone string) error {
+	return withTool(ctx, func(a *app.App) error {
+		repo, err := a.DIContainer().PrimaryOrderRepository(ctx)
+		if err != nil {
+			return err
+		}
+
+		var uids []string
+		if email != "" {
+			uids, err = repo.OrderUIDsByEmail(ctx, email)
+		} else {
+			uids, err = repo.OrderUIDsByPhone(ctx, phone)
+		}
+		if err != nil {
+			return err
+		}
+		for _, uid := range uids {
+			fmt.Println(uid)
+		}
+		return nil
+	})
+}
+
+// orderImport принимает заказы в том же формате, что и сообщения Kafka,
+// по одному JSON на строку, и проводит через сервис заказов, как воркер:
+// запись в БД, кэш и рассылка инвалидаций.
+func orderImport(ctx context.Context, path string, stopOnError bool) error {
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		svc, err := a.DIContainer().OrderService(ctx)
+		if err != nil {
+			return err
+		}
+
+		validate := validator.New()
+		sc := bufio.NewScanner(in)
+		sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+		var line, imported, failed int
+		for sc.Scan() {
+			line++
+			raw := sc.Bytes()
+			if len(raw) == 0 {
+				continue
+			}
+
+			err := func() error {
+				var dto adapterModel.OrderDTO
+				if err := json.Unmarshal(raw, &dto); err != nil {
+					return fmt.Errorf("json: %w", err)
+				}
+				if err := validate.Struct(dto); err != nil {
+					return fmt.Errorf("validation: %w", err)
+				}
+				return svc.ProcessOrder(ctx, converter.OrderDTOToModel(dto))
+			}()
+			if err != nil {
+				failed++
+				log.Printf("[import] line %d: %v", line, err)
+				if stopOnError || ctx.Err() != nil {
+					break
+				}
+				continue
+			}
+			imported++
+		}
+		if err := sc.Err(); err != nil {
+			return err
+		}
+
+		log.Printf("[import] imported=%d failed=%d", imported, failed)
+		if failed > 0 {
+			return fmt.Errorf("%d orders failed to import", failed)
+		}
+		return nil
+	})
+}
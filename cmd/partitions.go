@@ -0,0 +1,41 @@
+package main
+
+import (
+	"app/internal/app"
+	"context"
+	"errors"
+	"log"
+)
+
+const partitionsUsage = "partitions run"
+
+// runPartitions выполняет обслуживание секций один раз, не дожидаясь
+// фонового job'а. Если job сейчас выполняет другая реплика, ничего не делает.
+func runPartitions(ctx context.Context, args []string) error {
+	if len(args) != 1 || args[0] != "run" {
+		return errUsage
+	}
+
+	return withTool(ctx, func(a *app.App) error {
+		m, err := a.DIContainer().PartitionMaintainer(ctx)
+		if err != nil {
+			return err
+		}
+		pool, err := a.DIContainer().PgxPool(ctx)
+		if err != nil {
+			return err
+		}
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return err
+		}
+		defer conn.Release()
+
+		rep, err := m.RunOnce(ctx, conn)
+		if !rep.Leader && err == nil {
+			return errors.New("maintenance is running on another instance")
+		}
+		log.Printf("[partition] created=%v dropped=%v", rep.Created, rep.Dropped)
+		return err
+	})
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"app/internal/app"
+	"app/internal/closer"
+	"app/internal/config"
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"time"
+)
+
+const serveUsage = "serve [--role api|ingester|all] [--http-only | --worker-only]"
+
+func runServe(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	role := fs.String("role", "", "process role: api, ingester or all (default: APP_ROLE)")
+	httpOnly := fs.Bool("http-only", false, "shorthand for --role api")
+	workerOnly := fs.Bool("worker-only", false, "shorthand for --role ingester")
+	if err := fs.Parse(args); err != nil {
+		return errUsage
+	}
+
+	switch {
+	case *httpOnly && *workerOnly:
+		return errUsage
+	case *httpOnly:
+		*role = config.RoleAPI
+	case *workerOnly:
+		*role = config.RoleIngester
+	}
+
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	var opts []app.Option
+	if *role != "" {
+		opts = append(opts, app.WithRole(*role))
+	}
+
+	application, err := app.NewApp(ctx, opts...)
+	if err != nil {
+		return err
+	}
+
+	errCh := make(chan error, 5)
+
+	// HTTP-сервер есть у любой роли: у api это API, у ingester — только пробы.
+	go func() { errCh <- application.Run(ctx) }()
+
+	if application.GRPCEnabled() {
+		go func() { errCh <- application.RunGRPC(ctx) }()
+	}
+
+	// Ошибка запуска компонентов идёт через тот же shutdown, что и сигнал:
+	// уже поднятые серверы и соединения должны закрыться.
+	startErr := func() error {
+		if application.WorkerEnabled() {
+			worker, err := application.DIContainer().Worker(ctx)
+			if err != nil {
+				return err
+			}
+			go func() { errCh <- worker.Run(ctx) }()
+		}
+
+		// Инвалидации нужны только репликам, которые отдают заказы из кэша.
+		if application.HTTPEnabled() {
+			invListener, err := application.DIContainer().InvalidationListener(ctx)
+			if err != nil {
+				return err
+			}
+			if invListener != nil {
+				go func() { errCh <- invListener.Run(ctx) }()
+			}
+
+			if relay := application.DIContainer().FeedRelay(); relay != nil {
+				go func() { errCh <- relay.Run(ctx) }()
+			}
+		}
+		return nil
+	}()
+
+	if startErr == nil {
+		select {
+		case <-ctx.Done():
+		case err := <-errCh:
+			if err != nil && !errors.Is(err, context.Canceled) {
+				log.Printf("stopped with error: %v", err)
+			}
+		}
+	}
+	stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := closer.CloseAll(shutdownCtx); err != nil {
+		log.Printf("closeAll error: %v", err)
+	}
+	return startErr
+}
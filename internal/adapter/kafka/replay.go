@@ -0,0 +1,128 @@
+package kafka
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var ErrBadDLQEnvelope = errors.New("bad dlq envelope")
+
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type ReplayOptions struct {
+	// Topic переопределяет топик назначения; по умолчанию — исходный топик из конверта.
+	Topic string
+	// Limit ограничивает число переотправленных сообщений (0 — без ограничения).
+	Limit int
+	// Idle — сколько ждать новое сообщение, прежде чем считать DLQ вычитанным.
+	Idle time.Duration
+	// DryRun только разбирает сообщения, не отправляя и не коммитя их.
+	DryRun bool
+}
+
+type ReplayStats struct {
+	Replayed int
+	Skipped  int
+}
+
+// ReplayDLQ вычитывает конверты из DLQ и переотправляет исходные сообщения.
+// Нераспознанные конверты пропускаются, но коммитятся, чтобы не блокировать очередь.
+func ReplayDLQ(ctx context.Context, r Reader, w Writer, opts ReplayOptions) (ReplayStats, error) {
+	var stats ReplayStats
+	if opts.Idle <= 0 {
+		opts.Idle = 5 * time.Second
+	}
+
+	for opts.Limit <= 0 || stats.Replayed < opts.Limit {
+		fetchCtx, cancel := context.WithTimeout(ctx, opts.Idle)
+		msg, err := r.FetchMessage(fetchCtx)
+		cancel()
+		if err != nil {
+			if ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+				return stats, nil
+			}
+			return stats, err
+		}
+
+		out, err := decodeDLQMessage(msg, opts.Topic)
+		switch {
+		case err != nil:
+			stats.Skipped++
+		case opts.DryRun:
+			stats.Replayed++
+		default:
+			writeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			err = w.WriteMessages(writeCtx, out)
+			cancel()
+			if err != nil {
+				return stats, fmt.Errorf("replay offset %d: %w", msg.Offset, err)
+			}
+			stats.Replayed++
+		}
+
+		if opts.DryRun {
+			continue
+		}
+		if err := r.CommitMessages(ctx, msg); err != nil {
+			return stats, err
+		}
+	}
+
+	return stats, nil
+}
+
+func decodeDLQMessage(msg kafka.Message, topic string) (kafka.Message, error) {
+	var env dlqEnvelope
+	if err := json.Unmarshal(msg.Value, &env); err != nil {
+		return kafka.Message{}, fmt.Errorf("%w: %v", ErrBadDLQEnvelope, err)
+	}
+
+	value, err := unb64(env.Original.ValueB64)
+	if err != nil || len(value) == 0 {
+		return kafka.Message{}, fmt.Errorf("%w: original value", ErrBadDLQEnvelope)
+	}
+	key, err := unb64(env.Original.KeyB64)
+	if err != nil {
+		return kafka.Message{}, fmt.Errorf("%w: original key", ErrBadDLQEnvelope)
+	}
+
+	if topic == "" {
+		topic = env.Original.Topic
+	}
+	if topic == "" {
+		return kafka.Message{}, fmt.Errorf("%w: no target topic", ErrBadDLQEnvelope)
+	}
+
+	headers := make([]kafka.Header, 0, len(env.Original.Headers)+1)
+	for _, h := range env.Original.Headers {
+		v, err := unb64(h.Value)
+		if err != nil {
+			return kafka.Message{}, fmt.Errorf("%w: header %q", ErrBadDLQEnvelope, h.Key)
+		}
+		headers = append(headers, kafka.Header{Key: h.Key, Value: v})
+	}
+	headers = append(headers, kafka.Header{Key: "x-replayed-from", Value: []byte(msg.Topic + "/" + strconv.FormatInt(msg.Offset, 10))})
+
+	return kafka.Message{
+		Topic:   topic,
+		Key:     key,
+		Value:   value,
+		Headers: headers,
+	}, nil
+}
+
+func unb64(s string) ([]byte, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(s)
+}
@@ -0,0 +1,121 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+type idleReader struct {
+	msgs      []kafka.Message
+	committed []kafka.Message
+}
+
+func (r *idleReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.msgs) > 0 {
+		msg := r.msgs[0]
+		r.msgs = r.msgs[1:]
+		return msg, nil
+	}
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func (r *idleReader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.committed = append(r.committed, msgs...)
+	return nil
+}
+
+type fakeWriter struct {
+	written []kafka.Message
+	err     error
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.written = append(w.written, msgs...)
+	return nil
+}
+
+func dlqMessage(t *testing.T, offset int64, value string) kafka.Message {
+	t.Helper()
+
+	b, err := json.Marshal(dlqEnvelope{
+		Reason:  "boom",
+		Retries: 5,
+		Original: dlqOriginal{
+			Topic:    "orders",
+			KeyB64:   b64([]byte("uid-1")),
+			ValueB64: b64([]byte(value)),
+			Headers:  toDLQHeaders([]kafka.Header{{Key: "traceparent", Value: []byte("tp")}}),
+		},
+	})
+	require.NoError(t, err)
+
+	return kafka.Message{Topic: "orders.dlq", Offset: offset, Value: b}
+}
+
+func TestReplayDLQ_RepublishesOriginals(t *testing.T) {
+	r := &idleReader{msgs: []kafka.Message{
+		dlqMessage(t, 10, `{"order_uid":"uid-1"}`),
+		{Topic: "orders.dlq", Offset: 11, Value: []byte("not json")},
+	}}
+	w := &fakeWriter{}
+
+	stats, err := ReplayDLQ(context.Background(), r, w, ReplayOptions{Idle: 20 * time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, ReplayStats{Replayed: 1, Skipped: 1}, stats)
+
+	require.Len(t, w.written, 1)
+	out := w.written[0]
+	require.Equal(t, "orders", out.Topic)
+	require.Equal(t, []byte("uid-1"), out.Key)
+	require.JSONEq(t, `{"order_uid":"uid-1"}`, string(out.Value))
+	require.Equal(t, kafka.Header{Key: "traceparent", Value: []byte("tp")}, out.Headers[0])
+	require.Equal(t, "x-replayed-from", out.Headers[1].Key)
+	require.Equal(t, "orders.dlq/10", string(out.Headers[1].Value))
+
+	require.Len(t, r.committed, 2)
+}
+
+func TestReplayDLQ_TopicOverrideAndLimit(t *testing.T) {
+	r := &idleReader{msgs: []kafka.Message{
+		dlqMessage(t, 1, `{}`),
+		dlqMessage(t, 2, `{}`),
+	}}
+	w := &fakeWriter{}
+
+	stats, err := ReplayDLQ(context.Background(), r, w, ReplayOptions{Topic: "orders.retry", Limit: 1})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Replayed)
+	require.Len(t, w.written, 1)
+	require.Equal(t, "orders.retry", w.written[0].Topic)
+	require.Len(t, r.committed, 1)
+}
+
+func TestReplayDLQ_DryRun_NoWriteNoCommit(t *testing.T) {
+	r := &idleReader{msgs: []kafka.Message{dlqMessage(t, 1, `{}`)}}
+	w := &fakeWriter{}
+
+	stats, err := ReplayDLQ(context.Background(), r, w, ReplayOptions{DryRun: true, Idle: 20 * time.Millisecond})
+	require.NoError(t, err)
+	require.Equal(t, 1, stats.Replayed)
+	require.Empty(t, w.written)
+	require.Empty(t, r.committed)
+}
+
+func TestReplayDLQ_WriteError_NoCommit(t *testing.T) {
+	r := &idleReader{msgs: []kafka.Message{dlqMessage(t, 1, `{}`)}}
+	w := &fakeWriter{err: errors.New("broker down")}
+
+	_, err := ReplayDLQ(context.Background(), r, w, ReplayOptions{Idle: 20 * time.Millisecond})
+	require.Error(t, err)
+	require.Empty(t, r.committed)
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sync/atomic"
 	"time"
 
 	"app/internal/adapter"
@@ -17,6 +19,11 @@ import (
 	"go.uber.org/zap"
 )
 
+var (
+	ErrWorkerNotRunning = errors.New("kafka worker is not running")
+	ErrWorkerStalled    = errors.New("kafka worker is stalled")
+)
+
 type OrderService interface {
 	ProcessOrder(ctx context.Context, order serviceModel.Order) error
 }
@@ -27,122 +34,184 @@ type Worker struct {
 	validate  *validator.Validate
 	dlqWriter *kafka.Writer
 
-	maxRetries  int
-	baseBackoff time.Duration
-	maxBackoff  time.Duration
+	retry atomic.Pointer[RetryPolicy]
+
+	running       atomic.Bool
+	lastFetch     atomic.Int64
+	handlingSince atomic.Int64
+}
+
+// RetryPolicy — число повторов обработки и экспоненциальный backoff между ними.
+type RetryPolicy struct {
+	MaxRetries  int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+func (p RetryPolicy) backoff(retryAttempt int) time.Duration {
+	d := p.BaseBackoff * time.Duration(1<<uint(retryAttempt-1))
+	if d > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return d
+}
+
+type WorkerOption func(*Worker)
+
+func WithRetries(p RetryPolicy) WorkerOption {
+	return func(w *Worker) { w.SetRetryPolicy(p) }
 }
 
-func NewWorker(c adapter.Consumer, svc OrderService, dlq *kafka.Writer) *Worker {
-	return &Worker{
-		consumer:    c,
-		svc:         svc,
-		validate:    validator.New(),
-		dlqWriter:   dlq,
-		maxRetries:  5,
-		baseBackoff: 200 * time.Millisecond,
-		maxBackoff:  5 * time.Second,
+func NewWorker(c adapter.Consumer, svc OrderService, dlq *kafka.Writer, opts ...WorkerOption) *Worker {
+	w := &Worker{
+		consumer:  c,
+		svc:       svc,
+		validate:  validator.New(),
+		dlqWriter: dlq,
 	}
+	w.SetRetryPolicy(RetryPolicy{
+		MaxRetries:  5,
+		BaseBackoff: 200 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+	})
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
 }
 
 func (w *Worker) Run(ctx context.Context) error {
 	logger.Info(ctx, "kafka worker started")
+	w.running.Store(true)
+	defer w.running.Store(false)
 
 	err := w.consumer.Read(ctx, func(ctx context.Context, msg kafka.Message) error {
-		var dto adapterModel.OrderDTO
-		if err := json.Unmarshal(msg.Value, &dto); err != nil {
-			logger.Warn(ctx, "bad message: json",
-				zap.String("topic", msg.Topic),
-				zap.Int("partition", msg.Partition),
-				zap.Int64("offset", msg.Offset),
-				zap.Error(err),
-			)
+		now := time.Now().UnixNano()
+		w.lastFetch.Store(now)
+		w.handlingSince.Store(now)
+		defer w.handlingSince.Store(0)
 
-			if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, err, 0); dlqErr != nil {
-				logger.Error(ctx, "dlq write failed (json error)", zap.Error(dlqErr))
-				return dlqErr
-			}
-			return nil
-		}
+		return w.handle(ctx, msg)
+	})
 
-		if err := w.validate.Struct(dto); err != nil {
-			logger.Warn(ctx, "bad message: validation",
-				zap.String("topic", msg.Topic),
-				zap.Int("partition", msg.Partition),
-				zap.Int64("offset", msg.Offset),
-				zap.Error(err),
-			)
+	if err != nil && ctx.Err() == nil {
+		logger.Error(ctx, "kafka worker stopped with error", zap.Error(err))
+		return err
+	}
 
-			if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, err, 0); dlqErr != nil {
-				logger.Error(ctx, "dlq write failed (validation error)", zap.Error(dlqErr))
-				return dlqErr
-			}
-			return nil
+	logger.Info(ctx, "kafka worker stopped")
+	return err
+}
+
+// SetRetryPolicy меняет политику повторов на лету; сообщение, которое уже
+// обрабатывается, дорабатывает со старой политикой.
+func (w *Worker) SetRetryPolicy(p RetryPolicy) {
+	w.retry.Store(&p)
+}
+
+// Alive сообщает, что цикл чтения жив: worker запущен и не обрабатывает одно
+// сообщение дольше maxStall. Ожидание новых сообщений застоем не считается.
+func (w *Worker) Alive(maxStall time.Duration) error {
+	if !w.running.Load() {
+		return ErrWorkerNotRunning
+	}
+	if since := w.handlingSince.Load(); since != 0 {
+		if d := time.Since(time.Unix(0, since)); d > maxStall {
+			return fmt.Errorf("%w: handling one message for %s", ErrWorkerStalled, d.Round(time.Second))
 		}
+	}
+	return nil
+}
 
-		order := converter.OrderDTOToModel(dto)
+// LastFetch возвращает время последнего полученного сообщения.
+func (w *Worker) LastFetch() time.Time {
+	ns := w.lastFetch.Load()
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
 
-		var lastErr error
-		for attempt := 1; attempt <= w.maxRetries+1; attempt++ {
-			if attempt > 1 {
-				if err := sleepCtx(ctx, w.backoff(attempt-1)); err != nil {
-					return err
-				}
-			}
+func (w *Worker) handle(ctx context.Context, msg kafka.Message) error {
+	var dto adapterModel.OrderDTO
+	if err := json.Unmarshal(msg.Value, &dto); err != nil {
+		logger.Warn(ctx, "bad message: json",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err),
+		)
 
-			if err := w.svc.ProcessOrder(ctx, order); err == nil {
-				logger.Debug(ctx, "order processed",
-					zap.String("order_uid", order.OrderUUID),
-					zap.String("topic", msg.Topic),
-					zap.Int("partition", msg.Partition),
-					zap.Int64("offset", msg.Offset),
-				)
-				return nil
-			} else {
-				lastErr = err
-				logger.Warn(ctx, "process failed",
-					zap.String("order_uid", order.OrderUUID),
-					zap.Int("attempt", attempt),
-					zap.Error(err),
-				)
-
-				if !isTemporary(err) {
-					break
-				}
-			}
+		if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, err, 0); dlqErr != nil {
+			logger.Error(ctx, "dlq write failed (json error)", zap.Error(dlqErr))
+			return dlqErr
 		}
+		return nil
+	}
 
-		logger.Error(ctx, "sending to DLQ after retries",
-			zap.String("order_uid", order.OrderUUID),
-			zap.Error(lastErr),
+	if err := w.validate.Struct(dto); err != nil {
+		logger.Warn(ctx, "bad message: validation",
+			zap.String("topic", msg.Topic),
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err),
 		)
 
-		if lastErr == nil {
-			lastErr = errors.New("processing failed: unknown error")
+		if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, err, 0); dlqErr != nil {
+			logger.Error(ctx, "dlq write failed (validation error)", zap.Error(dlqErr))
+			return dlqErr
 		}
+		return nil
+	}
 
-		if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, lastErr, w.maxRetries); dlqErr != nil {
-			logger.Error(ctx, "dlq write failed (after retries)", zap.Error(dlqErr))
-			return dlqErr
+	order := converter.OrderDTOToModel(dto)
+
+	var lastErr error
+	policy := *w.retry.Load()
+	for attempt := 1; attempt <= policy.MaxRetries+1; attempt++ {
+		if attempt > 1 {
+			if err := sleepCtx(ctx, policy.backoff(attempt-1)); err != nil {
+				return err
+			}
 		}
 
-		return nil
-	})
+		if err := w.svc.ProcessOrder(ctx, order); err == nil {
+			logger.Debug(ctx, "order processed",
+				zap.String("order_uid", order.OrderUUID),
+				zap.String("topic", msg.Topic),
+				zap.Int("partition", msg.Partition),
+				zap.Int64("offset", msg.Offset),
+			)
+			return nil
+		} else {
+			lastErr = err
+			logger.Warn(ctx, "process failed",
+				zap.String("order_uid", order.OrderUUID),
+				zap.Int("attempt", attempt),
+				zap.Error(err),
+			)
 
-	if err != nil && ctx.Err() == nil {
-		logger.Error(ctx, "kafka worker stopped with error", zap.Error(err))
-		return err
+			if !isTemporary(err) {
+				break
+			}
+		}
 	}
 
-	logger.Info(ctx, "kafka worker stopped")
-	return err
-}
+	logger.Error(ctx, "sending to DLQ after retries",
+		zap.String("order_uid", order.OrderUUID),
+		zap.Error(lastErr),
+	)
 
-func (w *Worker) backoff(retryAttempt int) time.Duration {
-	d := w.baseBackoff * time.Duration(1<<uint(retryAttempt-1))
-	if d > w.maxBackoff {
-		return w.maxBackoff
+	if lastErr == nil {
+		lastErr = errors.New("processing failed: unknown error")
 	}
-	return d
+
+	if dlqErr := sendToDLQ(ctx, w.dlqWriter, msg, lastErr, policy.MaxRetries); dlqErr != nil {
+		logger.Error(ctx, "dlq write failed (after retries)", zap.Error(dlqErr))
+		return dlqErr
+	}
+
+	return nil
 }
 
 func sleepCtx(ctx context.Context, d time.Duration) error {
@@ -0,0 +1,49 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+type blockingConsumer struct {
+	started chan struct{}
+}
+
+func (c *blockingConsumer) Read(ctx context.Context, handle func(ctx context.Context, msg kafka.Message) error) error {
+	close(c.started)
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestWorker_Alive(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	c := &blockingConsumer{started: make(chan struct{})}
+	w := NewWorker(c, nil, nil)
+
+	require.ErrorIs(t, w.Alive(time.Minute), ErrWorkerNotRunning)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = w.Run(ctx)
+		close(done)
+	}()
+	<-c.started
+
+	require.NoError(t, w.Alive(time.Minute))
+
+	w.handlingSince.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+	require.ErrorIs(t, w.Alive(time.Minute), ErrWorkerStalled)
+	w.handlingSince.Store(0)
+
+	cancel()
+	<-done
+	require.ErrorIs(t, w.Alive(time.Minute), ErrWorkerNotRunning)
+}
@@ -0,0 +1,80 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graphql
+
+import (
+	"time"
+)
+
+type Delivery struct {
+	Name    *string `json:"name,omitempty"`
+	Phone   *string `json:"phone,omitempty"`
+	Zip     *string `json:"zip,omitempty"`
+	City    *string `json:"city,omitempty"`
+	Address *string `json:"address,omitempty"`
+	Region  string  `json:"region"`
+	Email   *string `json:"email,omitempty"`
+}
+
+type Item struct {
+	ChrtID      int    `json:"chrtId"`
+	TrackNumber string `json:"trackNumber"`
+	Price       int    `json:"price"`
+	Rid         string `json:"rid"`
+	Name        string `json:"name"`
+	Sale        int    `json:"sale"`
+	Size        string `json:"size"`
+	TotalPrice  int    `json:"totalPrice"`
+	NmID        int    `json:"nmId"`
+	Brand       string `json:"brand"`
+	Status      int    `json:"status"`
+}
+
+type Order struct {
+	OrderUID          string    `json:"orderUid"`
+	TrackNumber       string    `json:"trackNumber"`
+	Entry             string    `json:"entry"`
+	Locale            string    `json:"locale"`
+	InternalSignature *string   `json:"internalSignature,omitempty"`
+	CustomerID        *string   `json:"customerId,omitempty"`
+	DeliveryService   string    `json:"deliveryService"`
+	ShardKey          string    `json:"shardKey"`
+	SmID              int       `json:"smId"`
+	DateCreated       time.Time `json:"dateCreated"`
+	OffShard          string    `json:"offShard"`
+	Delivery          *Delivery `json:"delivery"`
+	Payment           *Payment  `json:"payment"`
+	Items             []*Item   `json:"items"`
+}
+
+type OrderFilter struct {
+	DeliveryService *string `json:"deliveryService,omitempty"`
+	CustomerID      *string `json:"customerId,omitempty"`
+	Region          *string `json:"region,omitempty"`
+	// Включительно.
+	CreatedFrom *time.Time `json:"createdFrom,omitempty"`
+	// Не включительно.
+	CreatedTo *time.Time `json:"createdTo,omitempty"`
+}
+
+type OrderPage struct {
+	Orders []*Order `json:"orders"`
+	// Курсор для after следующей страницы; null — страница пуста, заказов больше нет.
+	EndCursor *string `json:"endCursor,omitempty"`
+}
+
+type Payment struct {
+	Transaction  *string `json:"transaction,omitempty"`
+	Request      *string `json:"request,omitempty"`
+	Currency     string  `json:"currency"`
+	Provider     string  `json:"provider"`
+	Amount       int     `json:"amount"`
+	PaymentDt    int     `json:"paymentDt"`
+	Bank         string  `json:"bank"`
+	DeliveryCost int     `json:"deliveryCost"`
+	GoodsTotal   int     `json:"goodsTotal"`
+	CustomFee    int     `json:"customFee"`
+}
+
+type Query struct {
+}
@@ -1,6 +1,14 @@
 // Code generated by ogen, DO NOT EDIT.
 package v1
 
+type EraseCustomerRes interface {
+	eraseCustomerRes()
+}
+
 type GetOrderRes interface {
 	getOrderRes()
 }
+
+type GetOrdersRes interface {
+	getOrdersRes()
+}
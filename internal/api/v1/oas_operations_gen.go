@@ -6,6 +6,8 @@ package v1
 type OperationName = string
 
 const (
-	GetOrderOperation OperationName = "GetOrder"
-	IndexOperation    OperationName = "Index"
+	EraseCustomerOperation OperationName = "EraseCustomer"
+	GetOrderOperation      OperationName = "GetOrder"
+	GetOrdersOperation     OperationName = "GetOrders"
+	IndexOperation         OperationName = "Index"
 )
@@ -18,6 +18,10 @@ import (
 type GetOrderParams struct {
 	// Order unique identifier (order_uid).
 	OrderUID string
+	// ETag values from a previous response; a match returns 304.
+	IfNoneMatch OptString `json:",omitempty,omitzero"`
+	// HTTP date; ignored when If-None-Match is present.
+	IfModifiedSince OptString `json:",omitempty,omitzero"`
 }
 
 func unpackGetOrderParams(packed middleware.Parameters) (params GetOrderParams) {
@@ -28,10 +32,29 @@ func unpackGetOrderParams(packed middleware.Parameters) (params GetOrderParams)
 		}
 		params.OrderUID = packed[key].(string)
 	}
+	{
+		key := middleware.ParameterKey{
+			Name: "If-None-Match",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.IfNoneMatch = v.(OptString)
+		}
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "If-Modified-Since",
+			In:   "header",
+		}
+		if v, ok := packed[key]; ok {
+			params.IfModifiedSince = v.(OptString)
+		}
+	}
 	return params
 }
 
 func decodeGetOrderParams(args [1]string, argsEscaped bool, r *http.Request) (params GetOrderParams, _ error) {
+	h := uri.NewHeaderDecoder(r.Header)
 	// Decode path: orderUID.
 	if err := func() error {
 		param := args[0]
@@ -77,5 +100,166 @@ func decodeGetOrderParams(args [1]string, argsEscaped bool, r *http.Request) (pa
 			Err:  err,
 		}
 	}
+	// Decode header: If-None-Match.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "If-None-Match",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotIfNoneMatchVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotIfNoneMatchVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.IfNoneMatch.SetTo(paramsDotIfNoneMatchVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "If-None-Match",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	// Decode header: If-Modified-Since.
+	if err := func() error {
+		cfg := uri.HeaderParameterDecodingConfig{
+			Name:    "If-Modified-Since",
+			Explode: false,
+		}
+		if err := h.HasParam(cfg); err == nil {
+			if err := h.DecodeParam(cfg, func(d uri.Decoder) error {
+				var paramsDotIfModifiedSinceVal string
+				if err := func() error {
+					val, err := d.DecodeValue()
+					if err != nil {
+						return err
+					}
+
+					c, err := conv.ToString(val)
+					if err != nil {
+						return err
+					}
+
+					paramsDotIfModifiedSinceVal = c
+					return nil
+				}(); err != nil {
+					return err
+				}
+				params.IfModifiedSince.SetTo(paramsDotIfModifiedSinceVal)
+				return nil
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "If-Modified-Since",
+			In:   "header",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetOrdersParams is parameters of getOrders operation.
+type GetOrdersParams struct {
+	// Order UIDs, up to 100.
+	UID []string `json:",omitempty"`
+}
+
+func unpackGetOrdersParams(packed middleware.Parameters) (params GetOrdersParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "uid",
+			In:   "query",
+		}
+		params.UID = packed[key].([]string)
+	}
+	return params
+}
+
+func decodeGetOrdersParams(args [0]string, argsEscaped bool, r *http.Request) (params GetOrdersParams, _ error) {
+	q := uri.NewQueryDecoder(r.URL.Query())
+	// Decode query: uid.
+	if err := func() error {
+		cfg := uri.QueryParameterDecodingConfig{
+			Name:    "uid",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.HasParam(cfg); err == nil {
+			if err := q.DecodeParam(cfg, func(d uri.Decoder) error {
+				return d.DecodeArray(func(d uri.Decoder) error {
+					var paramsDotUIDVal string
+					if err := func() error {
+						val, err := d.DecodeValue()
+						if err != nil {
+							return err
+						}
+
+						c, err := conv.ToString(val)
+						if err != nil {
+							return err
+						}
+
+						paramsDotUIDVal = c
+						return nil
+					}(); err != nil {
+						return err
+					}
+					params.UID = append(params.UID, paramsDotUIDVal)
+					return nil
+				})
+			}); err != nil {
+				return err
+			}
+			if err := func() error {
+				if params.UID == nil {
+					return errors.New("nil is invalid value")
+				}
+				if err := (validate.Array{
+					MinLength:    1,
+					MinLengthSet: true,
+					MaxLength:    100,
+					MaxLengthSet: true,
+				}).ValidateLength(len(params.UID)); err != nil {
+					return errors.Wrap(err, "array")
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return err
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "uid",
+			In:   "query",
+			Err:  err,
+		}
+	}
 	return params, nil
 }
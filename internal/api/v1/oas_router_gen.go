@@ -68,37 +68,91 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				return
 			}
 			switch elem[0] {
-			case 'o': // Prefix: "order/"
+			case 'a': // Prefix: "admin/erasures"
 
-				if l := len("order/"); len(elem) >= l && elem[0:l] == "order/" {
+				if l := len("admin/erasures"); len(elem) >= l && elem[0:l] == "admin/erasures" {
 					elem = elem[l:]
 				} else {
 					break
 				}
 
-				// Param: "orderUID"
-				// Leaf parameter, slashes are prohibited
-				idx := strings.IndexByte(elem, '/')
-				if idx >= 0 {
-					break
-				}
-				args[0] = elem
-				elem = ""
-
 				if len(elem) == 0 {
 					// Leaf node.
 					switch r.Method {
-					case "GET":
-						s.handleGetOrderRequest([1]string{
-							args[0],
-						}, elemIsEscaped, w, r)
+					case "POST":
+						s.handleEraseCustomerRequest([0]string{}, elemIsEscaped, w, r)
 					default:
-						s.notAllowed(w, r, "GET")
+						s.notAllowed(w, r, "POST")
 					}
 
 					return
 				}
 
+			case 'o': // Prefix: "order"
+
+				if l := len("order"); len(elem) >= l && elem[0:l] == "order" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case '/': // Prefix: "/"
+
+					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "orderUID"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleGetOrderRequest([1]string{
+								args[0],
+							}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+				case 's': // Prefix: "s"
+
+					if l := len("s"); len(elem) >= l && elem[0:l] == "s" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch r.Method {
+						case "GET":
+							s.handleGetOrdersRequest([0]string{}, elemIsEscaped, w, r)
+						default:
+							s.notAllowed(w, r, "GET")
+						}
+
+						return
+					}
+
+				}
+
 			}
 
 		}
@@ -211,40 +265,104 @@ func (s *Server) FindPath(method string, u *url.URL) (r Route, _ bool) {
 				}
 			}
 			switch elem[0] {
-			case 'o': // Prefix: "order/"
+			case 'a': // Prefix: "admin/erasures"
 
-				if l := len("order/"); len(elem) >= l && elem[0:l] == "order/" {
+				if l := len("admin/erasures"); len(elem) >= l && elem[0:l] == "admin/erasures" {
 					elem = elem[l:]
 				} else {
 					break
 				}
 
-				// Param: "orderUID"
-				// Leaf parameter, slashes are prohibited
-				idx := strings.IndexByte(elem, '/')
-				if idx >= 0 {
-					break
-				}
-				args[0] = elem
-				elem = ""
-
 				if len(elem) == 0 {
 					// Leaf node.
 					switch method {
-					case "GET":
-						r.name = GetOrderOperation
-						r.summary = "Get order by UID"
-						r.operationID = "getOrder"
+					case "POST":
+						r.name = EraseCustomerOperation
+						r.summary = "Erase customer personal data"
+						r.operationID = "eraseCustomer"
 						r.operationGroup = ""
-						r.pathPattern = "/order/{orderUID}"
+						r.pathPattern = "/admin/erasures"
 						r.args = args
-						r.count = 1
+						r.count = 0
 						return r, true
 					default:
 						return
 					}
 				}
 
+			case 'o': // Prefix: "order"
+
+				if l := len("order"); len(elem) >= l && elem[0:l] == "order" {
+					elem = elem[l:]
+				} else {
+					break
+				}
+
+				if len(elem) == 0 {
+					break
+				}
+				switch elem[0] {
+				case '/': // Prefix: "/"
+
+					if l := len("/"); len(elem) >= l && elem[0:l] == "/" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					// Param: "orderUID"
+					// Leaf parameter, slashes are prohibited
+					idx := strings.IndexByte(elem, '/')
+					if idx >= 0 {
+						break
+					}
+					args[0] = elem
+					elem = ""
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = GetOrderOperation
+							r.summary = "Get order by UID"
+							r.operationID = "getOrder"
+							r.operationGroup = ""
+							r.pathPattern = "/order/{orderUID}"
+							r.args = args
+							r.count = 1
+							return r, true
+						default:
+							return
+						}
+					}
+
+				case 's': // Prefix: "s"
+
+					if l := len("s"); len(elem) >= l && elem[0:l] == "s" {
+						elem = elem[l:]
+					} else {
+						break
+					}
+
+					if len(elem) == 0 {
+						// Leaf node.
+						switch method {
+						case "GET":
+							r.name = GetOrdersOperation
+							r.summary = "Get several orders by UID"
+							r.operationID = "getOrders"
+							r.operationGroup = ""
+							r.pathPattern = "/orders"
+							r.args = args
+							r.count = 0
+							return r, true
+						default:
+							return
+						}
+					}
+
+				}
+
 			}
 
 		}
@@ -7,39 +7,90 @@ import (
 	"time"
 )
 
+type ApiKeyAuth struct {
+	APIKey string
+	Roles  []string
+}
+
+// GetAPIKey returns the value of APIKey.
+func (s *ApiKeyAuth) GetAPIKey() string {
+	return s.APIKey
+}
+
+// GetRoles returns the value of Roles.
+func (s *ApiKeyAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetAPIKey sets the value of APIKey.
+func (s *ApiKeyAuth) SetAPIKey(val string) {
+	s.APIKey = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *ApiKeyAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
+type BearerAuth struct {
+	Token string
+	Roles []string
+}
+
+// GetToken returns the value of Token.
+func (s *BearerAuth) GetToken() string {
+	return s.Token
+}
+
+// GetRoles returns the value of Roles.
+func (s *BearerAuth) GetRoles() []string {
+	return s.Roles
+}
+
+// SetToken sets the value of Token.
+func (s *BearerAuth) SetToken(val string) {
+	s.Token = val
+}
+
+// SetRoles sets the value of Roles.
+func (s *BearerAuth) SetRoles(val []string) {
+	s.Roles = val
+}
+
+// Personal fields are omitted or masked depending on the caller's scopes.
 // Ref: #/components/schemas/Delivery
 type Delivery struct {
-	Name    string `json:"name"`
-	Phone   string `json:"phone"`
-	Zip     string `json:"zip"`
-	City    string `json:"city"`
-	Address string `json:"address"`
-	Region  string `json:"region"`
-	Email   string `json:"email"`
+	Name    OptString `json:"name"`
+	Phone   OptString `json:"phone"`
+	Zip     OptString `json:"zip"`
+	City    OptString `json:"city"`
+	Address OptString `json:"address"`
+	Region  string    `json:"region"`
+	Email   OptString `json:"email"`
 }
 
 // GetName returns the value of Name.
-func (s *Delivery) GetName() string {
+func (s *Delivery) GetName() OptString {
 	return s.Name
 }
 
 // GetPhone returns the value of Phone.
-func (s *Delivery) GetPhone() string {
+func (s *Delivery) GetPhone() OptString {
 	return s.Phone
 }
 
 // GetZip returns the value of Zip.
-func (s *Delivery) GetZip() string {
+func (s *Delivery) GetZip() OptString {
 	return s.Zip
 }
 
 // GetCity returns the value of City.
-func (s *Delivery) GetCity() string {
+func (s *Delivery) GetCity() OptString {
 	return s.City
 }
 
 // GetAddress returns the value of Address.
-func (s *Delivery) GetAddress() string {
+func (s *Delivery) GetAddress() OptString {
 	return s.Address
 }
 
@@ -49,32 +100,32 @@ func (s *Delivery) GetRegion() string {
 }
 
 // GetEmail returns the value of Email.
-func (s *Delivery) GetEmail() string {
+func (s *Delivery) GetEmail() OptString {
 	return s.Email
 }
 
 // SetName sets the value of Name.
-func (s *Delivery) SetName(val string) {
+func (s *Delivery) SetName(val OptString) {
 	s.Name = val
 }
 
 // SetPhone sets the value of Phone.
-func (s *Delivery) SetPhone(val string) {
+func (s *Delivery) SetPhone(val OptString) {
 	s.Phone = val
 }
 
 // SetZip sets the value of Zip.
-func (s *Delivery) SetZip(val string) {
+func (s *Delivery) SetZip(val OptString) {
 	s.Zip = val
 }
 
 // SetCity sets the value of City.
-func (s *Delivery) SetCity(val string) {
+func (s *Delivery) SetCity(val OptString) {
 	s.City = val
 }
 
 // SetAddress sets the value of Address.
-func (s *Delivery) SetAddress(val string) {
+func (s *Delivery) SetAddress(val OptString) {
 	s.Address = val
 }
 
@@ -84,10 +135,118 @@ func (s *Delivery) SetRegion(val string) {
 }
 
 // SetEmail sets the value of Email.
-func (s *Delivery) SetEmail(val string) {
+func (s *Delivery) SetEmail(val OptString) {
 	s.Email = val
 }
 
+type EraseCustomerBadRequest Error
+
+func (*EraseCustomerBadRequest) eraseCustomerRes() {}
+
+type EraseCustomerForbidden Error
+
+func (*EraseCustomerForbidden) eraseCustomerRes() {}
+
+type EraseCustomerInternalServerError Error
+
+func (*EraseCustomerInternalServerError) eraseCustomerRes() {}
+
+type EraseCustomerTooManyRequests Error
+
+func (*EraseCustomerTooManyRequests) eraseCustomerRes() {}
+
+type EraseCustomerUnauthorized Error
+
+func (*EraseCustomerUnauthorized) eraseCustomerRes() {}
+
+// Ref: #/components/schemas/ErasureReport
+type ErasureReport struct {
+	ID          int64     `json:"id"`
+	RequestedBy string    `json:"requested_by"`
+	OrderUids   []string  `json:"order_uids"`
+	ErasedAt    time.Time `json:"erased_at"`
+}
+
+// GetID returns the value of ID.
+func (s *ErasureReport) GetID() int64 {
+	return s.ID
+}
+
+// GetRequestedBy returns the value of RequestedBy.
+func (s *ErasureReport) GetRequestedBy() string {
+	return s.RequestedBy
+}
+
+// GetOrderUids returns the value of OrderUids.
+func (s *ErasureReport) GetOrderUids() []string {
+	return s.OrderUids
+}
+
+// GetErasedAt returns the value of ErasedAt.
+func (s *ErasureReport) GetErasedAt() time.Time {
+	return s.ErasedAt
+}
+
+// SetID sets the value of ID.
+func (s *ErasureReport) SetID(val int64) {
+	s.ID = val
+}
+
+// SetRequestedBy sets the value of RequestedBy.
+func (s *ErasureReport) SetRequestedBy(val string) {
+	s.RequestedBy = val
+}
+
+// SetOrderUids sets the value of OrderUids.
+func (s *ErasureReport) SetOrderUids(val []string) {
+	s.OrderUids = val
+}
+
+// SetErasedAt sets the value of ErasedAt.
+func (s *ErasureReport) SetErasedAt(val time.Time) {
+	s.ErasedAt = val
+}
+
+func (*ErasureReport) eraseCustomerRes() {}
+
+// At least one field must be set; orders matching any of them are erased.
+// Ref: #/components/schemas/ErasureRequest
+type ErasureRequest struct {
+	CustomerID OptString `json:"customer_id"`
+	Email      OptString `json:"email"`
+	Phone      OptString `json:"phone"`
+}
+
+// GetCustomerID returns the value of CustomerID.
+func (s *ErasureRequest) GetCustomerID() OptString {
+	return s.CustomerID
+}
+
+// GetEmail returns the value of Email.
+func (s *ErasureRequest) GetEmail() OptString {
+	return s.Email
+}
+
+// GetPhone returns the value of Phone.
+func (s *ErasureRequest) GetPhone() OptString {
+	return s.Phone
+}
+
+// SetCustomerID sets the value of CustomerID.
+func (s *ErasureRequest) SetCustomerID(val OptString) {
+	s.CustomerID = val
+}
+
+// SetEmail sets the value of Email.
+func (s *ErasureRequest) SetEmail(val OptString) {
+	s.Email = val
+}
+
+// SetPhone sets the value of Phone.
+func (s *ErasureRequest) SetPhone(val OptString) {
+	s.Phone = val
+}
+
 // Ref: #/components/schemas/Error
 type Error struct {
 	Message string `json:"message"`
@@ -103,6 +262,10 @@ func (s *Error) SetMessage(val string) {
 	s.Message = val
 }
 
+type GetOrderForbidden Error
+
+func (*GetOrderForbidden) getOrderRes() {}
+
 type GetOrderInternalServerError Error
 
 func (*GetOrderInternalServerError) getOrderRes() {}
@@ -111,6 +274,393 @@ type GetOrderNotFound Error
 
 func (*GetOrderNotFound) getOrderRes() {}
 
+// GetOrderNotModified is response for GetOrder operation.
+type GetOrderNotModified struct {
+	CacheControl string
+	ETag         string
+	LastModified string
+	Vary         string
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetOrderNotModified) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *GetOrderNotModified) GetETag() string {
+	return s.ETag
+}
+
+// GetLastModified returns the value of LastModified.
+func (s *GetOrderNotModified) GetLastModified() string {
+	return s.LastModified
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrderNotModified) GetVary() string {
+	return s.Vary
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetOrderNotModified) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *GetOrderNotModified) SetETag(val string) {
+	s.ETag = val
+}
+
+// SetLastModified sets the value of LastModified.
+func (s *GetOrderNotModified) SetLastModified(val string) {
+	s.LastModified = val
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrderNotModified) SetVary(val string) {
+	s.Vary = val
+}
+
+func (*GetOrderNotModified) getOrderRes() {}
+
+// MessagePack map with the same fields as the JSON representation.
+type GetOrderOKApplicationMsgpack struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetOrderOKApplicationMsgpack) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetOrderOKApplicationMsgpackHeaders wraps GetOrderOKApplicationMsgpack with response headers.
+type GetOrderOKApplicationMsgpackHeaders struct {
+	CacheControl string
+	ETag         string
+	LastModified string
+	Vary         string
+	Response     GetOrderOKApplicationMsgpack
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetOrderOKApplicationMsgpackHeaders) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *GetOrderOKApplicationMsgpackHeaders) GetETag() string {
+	return s.ETag
+}
+
+// GetLastModified returns the value of LastModified.
+func (s *GetOrderOKApplicationMsgpackHeaders) GetLastModified() string {
+	return s.LastModified
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrderOKApplicationMsgpackHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrderOKApplicationMsgpackHeaders) GetResponse() GetOrderOKApplicationMsgpack {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetOrderOKApplicationMsgpackHeaders) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *GetOrderOKApplicationMsgpackHeaders) SetETag(val string) {
+	s.ETag = val
+}
+
+// SetLastModified sets the value of LastModified.
+func (s *GetOrderOKApplicationMsgpackHeaders) SetLastModified(val string) {
+	s.LastModified = val
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrderOKApplicationMsgpackHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrderOKApplicationMsgpackHeaders) SetResponse(val GetOrderOKApplicationMsgpack) {
+	s.Response = val
+}
+
+func (*GetOrderOKApplicationMsgpackHeaders) getOrderRes() {}
+
+// Protobuf message order.v1.Order (api/proto/order/v1/order.proto).
+type GetOrderOKApplicationProtobuf struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetOrderOKApplicationProtobuf) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetOrderOKApplicationProtobufHeaders wraps GetOrderOKApplicationProtobuf with response headers.
+type GetOrderOKApplicationProtobufHeaders struct {
+	CacheControl string
+	ETag         string
+	LastModified string
+	Vary         string
+	Response     GetOrderOKApplicationProtobuf
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *GetOrderOKApplicationProtobufHeaders) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *GetOrderOKApplicationProtobufHeaders) GetETag() string {
+	return s.ETag
+}
+
+// GetLastModified returns the value of LastModified.
+func (s *GetOrderOKApplicationProtobufHeaders) GetLastModified() string {
+	return s.LastModified
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrderOKApplicationProtobufHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrderOKApplicationProtobufHeaders) GetResponse() GetOrderOKApplicationProtobuf {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *GetOrderOKApplicationProtobufHeaders) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *GetOrderOKApplicationProtobufHeaders) SetETag(val string) {
+	s.ETag = val
+}
+
+// SetLastModified sets the value of LastModified.
+func (s *GetOrderOKApplicationProtobufHeaders) SetLastModified(val string) {
+	s.LastModified = val
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrderOKApplicationProtobufHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrderOKApplicationProtobufHeaders) SetResponse(val GetOrderOKApplicationProtobuf) {
+	s.Response = val
+}
+
+func (*GetOrderOKApplicationProtobufHeaders) getOrderRes() {}
+
+type GetOrderTooManyRequests Error
+
+func (*GetOrderTooManyRequests) getOrderRes() {}
+
+type GetOrderUnauthorized Error
+
+func (*GetOrderUnauthorized) getOrderRes() {}
+
+type GetOrdersBadRequest Error
+
+func (*GetOrdersBadRequest) getOrdersRes() {}
+
+type GetOrdersForbidden Error
+
+func (*GetOrdersForbidden) getOrdersRes() {}
+
+type GetOrdersInternalServerError Error
+
+func (*GetOrdersInternalServerError) getOrdersRes() {}
+
+// MessagePack array of orders with the same fields as the JSON representation.
+type GetOrdersOKApplicationMsgpack struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetOrdersOKApplicationMsgpack) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetOrdersOKApplicationMsgpackHeaders wraps GetOrdersOKApplicationMsgpack with response headers.
+type GetOrdersOKApplicationMsgpackHeaders struct {
+	Vary     string
+	Response GetOrdersOKApplicationMsgpack
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrdersOKApplicationMsgpackHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrdersOKApplicationMsgpackHeaders) GetResponse() GetOrdersOKApplicationMsgpack {
+	return s.Response
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrdersOKApplicationMsgpackHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrdersOKApplicationMsgpackHeaders) SetResponse(val GetOrdersOKApplicationMsgpack) {
+	s.Response = val
+}
+
+func (*GetOrdersOKApplicationMsgpackHeaders) getOrdersRes() {}
+
+// Protobuf message order.v1.OrderList (api/proto/order/v1/order.proto).
+type GetOrdersOKApplicationProtobuf struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetOrdersOKApplicationProtobuf) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetOrdersOKApplicationProtobufHeaders wraps GetOrdersOKApplicationProtobuf with response headers.
+type GetOrdersOKApplicationProtobufHeaders struct {
+	Vary     string
+	Response GetOrdersOKApplicationProtobuf
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrdersOKApplicationProtobufHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrdersOKApplicationProtobufHeaders) GetResponse() GetOrdersOKApplicationProtobuf {
+	return s.Response
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrdersOKApplicationProtobufHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrdersOKApplicationProtobufHeaders) SetResponse(val GetOrdersOKApplicationProtobuf) {
+	s.Response = val
+}
+
+func (*GetOrdersOKApplicationProtobufHeaders) getOrdersRes() {}
+
+// One JSON order per line.
+type GetOrdersOKApplicationXNdjson struct {
+	Data io.Reader
+}
+
+// Read reads data from the Data reader.
+//
+// Kept to satisfy the io.Reader interface.
+func (s GetOrdersOKApplicationXNdjson) Read(p []byte) (n int, err error) {
+	if s.Data == nil {
+		return 0, io.EOF
+	}
+	return s.Data.Read(p)
+}
+
+// GetOrdersOKApplicationXNdjsonHeaders wraps GetOrdersOKApplicationXNdjson with response headers.
+type GetOrdersOKApplicationXNdjsonHeaders struct {
+	Vary     string
+	Response GetOrdersOKApplicationXNdjson
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrdersOKApplicationXNdjsonHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrdersOKApplicationXNdjsonHeaders) GetResponse() GetOrdersOKApplicationXNdjson {
+	return s.Response
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrdersOKApplicationXNdjsonHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrdersOKApplicationXNdjsonHeaders) SetResponse(val GetOrdersOKApplicationXNdjson) {
+	s.Response = val
+}
+
+func (*GetOrdersOKApplicationXNdjsonHeaders) getOrdersRes() {}
+
+// GetOrdersOKHeaders wraps []Order with response headers.
+type GetOrdersOKHeaders struct {
+	Vary     string
+	Response []Order
+}
+
+// GetVary returns the value of Vary.
+func (s *GetOrdersOKHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *GetOrdersOKHeaders) GetResponse() []Order {
+	return s.Response
+}
+
+// SetVary sets the value of Vary.
+func (s *GetOrdersOKHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *GetOrdersOKHeaders) SetResponse(val []Order) {
+	s.Response = val
+}
+
+func (*GetOrdersOKHeaders) getOrdersRes() {}
+
+type GetOrdersTooManyRequests Error
+
+func (*GetOrdersTooManyRequests) getOrdersRes() {}
+
+type GetOrdersUnauthorized Error
+
+func (*GetOrdersUnauthorized) getOrdersRes() {}
+
 type IndexOK struct {
 	Data io.Reader
 }
@@ -250,22 +800,70 @@ func (s *Item) SetStatus(val int32) {
 	s.Status = val
 }
 
+// NewOptString returns new OptString with value set to v.
+func NewOptString(v string) OptString {
+	return OptString{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptString is optional string.
+type OptString struct {
+	Value string
+	Set   bool
+}
+
+// IsSet returns true if OptString was set.
+func (o OptString) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptString) Reset() {
+	var v string
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptString) SetTo(v string) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptString) Get() (v string, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptString) Or(d string) string {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // Ref: #/components/schemas/Order
 type Order struct {
-	OrderUID          string    `json:"order_uid"`
-	TrackNumber       string    `json:"track_number"`
-	Entry             string    `json:"entry"`
-	Locale            string    `json:"locale"`
-	InternalSignature string    `json:"internal_signature"`
-	CustomerID        string    `json:"customer_id"`
-	DeliveryService   string    `json:"delivery_service"`
-	ShardKey          string    `json:"shard_key"`
-	SmID              int32     `json:"sm_id"`
-	DateCreated       time.Time `json:"date_created"`
-	OffShard          string    `json:"off_shard"`
-	Delivery          Delivery  `json:"delivery"`
-	Payment           Payment   `json:"payment"`
-	Items             []Item    `json:"items"`
+	OrderUID    string `json:"order_uid"`
+	TrackNumber string `json:"track_number"`
+	Entry       string `json:"entry"`
+	Locale      string `json:"locale"`
+	// Omitted or masked depending on the caller's scopes.
+	InternalSignature OptString `json:"internal_signature"`
+	// Omitted or masked depending on the caller's scopes.
+	CustomerID      OptString `json:"customer_id"`
+	DeliveryService string    `json:"delivery_service"`
+	ShardKey        string    `json:"shard_key"`
+	SmID            int32     `json:"sm_id"`
+	DateCreated     time.Time `json:"date_created"`
+	OffShard        string    `json:"off_shard"`
+	Delivery        Delivery  `json:"delivery"`
+	Payment         Payment   `json:"payment"`
+	Items           []Item    `json:"items"`
 }
 
 // GetOrderUID returns the value of OrderUID.
@@ -289,12 +887,12 @@ func (s *Order) GetLocale() string {
 }
 
 // GetInternalSignature returns the value of InternalSignature.
-func (s *Order) GetInternalSignature() string {
+func (s *Order) GetInternalSignature() OptString {
 	return s.InternalSignature
 }
 
 // GetCustomerID returns the value of CustomerID.
-func (s *Order) GetCustomerID() string {
+func (s *Order) GetCustomerID() OptString {
 	return s.CustomerID
 }
 
@@ -359,12 +957,12 @@ func (s *Order) SetLocale(val string) {
 }
 
 // SetInternalSignature sets the value of InternalSignature.
-func (s *Order) SetInternalSignature(val string) {
+func (s *Order) SetInternalSignature(val OptString) {
 	s.InternalSignature = val
 }
 
 // SetCustomerID sets the value of CustomerID.
-func (s *Order) SetCustomerID(val string) {
+func (s *Order) SetCustomerID(val OptString) {
 	s.CustomerID = val
 }
 
@@ -408,29 +1006,90 @@ func (s *Order) SetItems(val []Item) {
 	s.Items = val
 }
 
-func (*Order) getOrderRes() {}
+// OrderHeaders wraps Order with response headers.
+type OrderHeaders struct {
+	CacheControl string
+	ETag         string
+	LastModified string
+	Vary         string
+	Response     Order
+}
+
+// GetCacheControl returns the value of CacheControl.
+func (s *OrderHeaders) GetCacheControl() string {
+	return s.CacheControl
+}
+
+// GetETag returns the value of ETag.
+func (s *OrderHeaders) GetETag() string {
+	return s.ETag
+}
+
+// GetLastModified returns the value of LastModified.
+func (s *OrderHeaders) GetLastModified() string {
+	return s.LastModified
+}
+
+// GetVary returns the value of Vary.
+func (s *OrderHeaders) GetVary() string {
+	return s.Vary
+}
+
+// GetResponse returns the value of Response.
+func (s *OrderHeaders) GetResponse() Order {
+	return s.Response
+}
+
+// SetCacheControl sets the value of CacheControl.
+func (s *OrderHeaders) SetCacheControl(val string) {
+	s.CacheControl = val
+}
+
+// SetETag sets the value of ETag.
+func (s *OrderHeaders) SetETag(val string) {
+	s.ETag = val
+}
+
+// SetLastModified sets the value of LastModified.
+func (s *OrderHeaders) SetLastModified(val string) {
+	s.LastModified = val
+}
+
+// SetVary sets the value of Vary.
+func (s *OrderHeaders) SetVary(val string) {
+	s.Vary = val
+}
+
+// SetResponse sets the value of Response.
+func (s *OrderHeaders) SetResponse(val Order) {
+	s.Response = val
+}
+
+func (*OrderHeaders) getOrderRes() {}
 
 // Ref: #/components/schemas/Payment
 type Payment struct {
-	Transaction  string `json:"transaction"`
-	Request      string `json:"request"`
-	Currency     string `json:"currency"`
-	Provider     string `json:"provider"`
-	Amount       int32  `json:"amount"`
-	PaymentDt    int32  `json:"payment_dt"`
-	Bank         string `json:"bank"`
-	DeliveryCost int32  `json:"delivery_cost"`
-	GoodsTotal   int32  `json:"goods_total"`
-	CustomFee    int32  `json:"custom_fee"`
+	// Omitted or masked depending on the caller's scopes.
+	Transaction OptString `json:"transaction"`
+	// Omitted or masked depending on the caller's scopes.
+	Request      OptString `json:"request"`
+	Currency     string    `json:"currency"`
+	Provider     string    `json:"provider"`
+	Amount       int32     `json:"amount"`
+	PaymentDt    int32     `json:"payment_dt"`
+	Bank         string    `json:"bank"`
+	DeliveryCost int32     `json:"delivery_cost"`
+	GoodsTotal   int32     `json:"goods_total"`
+	CustomFee    int32     `json:"custom_fee"`
 }
 
 // GetTransaction returns the value of Transaction.
-func (s *Payment) GetTransaction() string {
+func (s *Payment) GetTransaction() OptString {
 	return s.Transaction
 }
 
 // GetRequest returns the value of Request.
-func (s *Payment) GetRequest() string {
+func (s *Payment) GetRequest() OptString {
 	return s.Request
 }
 
@@ -475,12 +1134,12 @@ func (s *Payment) GetCustomFee() int32 {
 }
 
 // SetTransaction sets the value of Transaction.
-func (s *Payment) SetTransaction(val string) {
+func (s *Payment) SetTransaction(val OptString) {
 	s.Transaction = val
 }
 
 // SetRequest sets the value of Request.
-func (s *Payment) SetRequest(val string) {
+func (s *Payment) SetRequest(val OptString) {
 	s.Request = val
 }
 
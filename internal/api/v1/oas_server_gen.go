@@ -8,12 +8,26 @@ import (
 
 // Handler handles operations described by OpenAPI v3 specification.
 type Handler interface {
+	// EraseCustomer implements eraseCustomer operation.
+	//
+	// Anonymizes delivery data of every order matching customer_id, email or phone and records an audit
+	// entry. Orders, payments and items are kept.
+	//
+	// POST /admin/erasures
+	EraseCustomer(ctx context.Context, req *ErasureRequest) (EraseCustomerRes, error)
 	// GetOrder implements getOrder operation.
 	//
 	// Get order by UID.
 	//
 	// GET /order/{orderUID}
 	GetOrder(ctx context.Context, params GetOrderParams) (GetOrderRes, error)
+	// GetOrders implements getOrders operation.
+	//
+	// Returns the orders found among the requested UIDs, in request order; unknown UIDs are skipped. The
+	// representation is chosen by the Accept header.
+	//
+	// GET /orders
+	GetOrders(ctx context.Context, params GetOrdersParams) (GetOrdersRes, error)
 	// Index implements index operation.
 	//
 	// Web UI.
@@ -25,18 +39,20 @@ type Handler interface {
 // Server implements http server based on OpenAPI v3 specification and
 // calls Handler to handle requests.
 type Server struct {
-	h Handler
+	h   Handler
+	sec SecurityHandler
 	baseServer
 }
 
 // NewServer creates new Server.
-func NewServer(h Handler, opts ...ServerOption) (*Server, error) {
+func NewServer(h Handler, sec SecurityHandler, opts ...ServerOption) (*Server, error) {
 	s, err := newServerConfig(opts...).baseServer()
 	if err != nil {
 		return nil, err
 	}
 	return &Server{
 		h:          h,
+		sec:        sec,
 		baseServer: s,
 	}, nil
 }
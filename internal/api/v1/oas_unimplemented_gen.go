@@ -13,6 +13,16 @@ type UnimplementedHandler struct{}
 
 var _ Handler = UnimplementedHandler{}
 
+// EraseCustomer implements eraseCustomer operation.
+//
+// Anonymizes delivery data of every order matching customer_id, email or phone and records an audit
+// entry. Orders, payments and items are kept.
+//
+// POST /admin/erasures
+func (UnimplementedHandler) EraseCustomer(ctx context.Context, req *ErasureRequest) (r EraseCustomerRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetOrder implements getOrder operation.
 //
 // Get order by UID.
@@ -22,6 +32,16 @@ func (UnimplementedHandler) GetOrder(ctx context.Context, params GetOrderParams)
 	return r, ht.ErrNotImplemented
 }
 
+// GetOrders implements getOrders operation.
+//
+// Returns the orders found among the requested UIDs, in request order; unknown UIDs are skipped. The
+// representation is chosen by the Accept header.
+//
+// GET /orders
+func (UnimplementedHandler) GetOrders(ctx context.Context, params GetOrdersParams) (r GetOrdersRes, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // Index implements index operation.
 //
 // Web UI.
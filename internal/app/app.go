@@ -3,16 +3,27 @@ package app
 import (
 	"app/internal/closer"
 	"app/internal/config"
+	"app/internal/fieldcrypt"
+	grpcv1 "app/internal/grpc/v1"
+	"app/internal/health"
 	v1 "app/internal/http/v1"
 	"app/internal/logger"
+	"app/internal/migrate"
 	"app/internal/otelx"
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"syscall"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
 )
 
 const serviceName = "wb-orders"
@@ -21,16 +32,38 @@ type App struct {
 	diContainer *diContainer
 	httpServer  *http.Server
 	listener    net.Listener
+	grpcServer  *grpc.Server
+	grpcLis     net.Listener
 	otel        otelx.InitResult
+
+	role string
+	tool bool
+}
+
+type Option func(*App)
+
+// WithRole задаёт роль процесса (api, ingester, all) вместо APP_ROLE.
+func WithRole(role string) Option {
+	return func(a *App) { a.role = role }
 }
 
-func NewApp(ctx context.Context) (*App, error) {
+// AsTool поднимает приложение для разовой команды CLI: без HTTP-сервера,
+// worker'а и экспорта телеметрии.
+func AsTool() Option {
+	return func(a *App) { a.tool = true }
+}
+
+func NewApp(ctx context.Context, opts ...Option) (*App, error) {
 	a := &App{}
+	for _, opt := range opts {
+		opt(a)
+	}
+
 	if err := a.initDeps(ctx); err != nil {
 		log.Printf("[app] init failed: %v", err)
 		return nil, err
 	}
-	log.Printf("[app] initialized")
+	log.Printf("[app] initialized role=%s", a.role)
 	return a, nil
 }
 
@@ -50,21 +83,51 @@ func (app *App) Run(ctx context.Context) error {
 	return nil
 }
 
+// RunGRPC обслуживает gRPC API до остановки сервера.
+func (app *App) RunGRPC(ctx context.Context) error {
+	_ = ctx
+	if app.grpcServer == nil || app.grpcLis == nil {
+		return errors.New("grpc server not initialized")
+	}
+
+	log.Printf("[grpc] serving on %s", app.grpcLis.Addr().String())
+	if err := app.grpcServer.Serve(app.grpcLis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		log.Printf("[grpc] serve error: %v", err)
+		return err
+	}
+
+	log.Printf("[grpc] stopped")
+	return nil
+}
+
+type initStep struct {
+	name string
+	fn   func(context.Context) error
+}
+
 func (app *App) initDeps(ctx context.Context) error {
-	steps := []struct {
-		name string
-		fn   func(context.Context) error
-	}{
+	steps := []initStep{
 		{"config", app.initConfig},
+		{"role", app.initRole},
 		{"otel", app.initOTel},
 		{"logger", app.initLogger},
 		{"closer", app.initCloser},
 		{"di", app.initDi},
 		{"infra", app.initInfra},
-		{"listener", app.initListener},
-		{"http-server", app.initHTTPServer},
-		{"register-closers", app.registerClosers},
 	}
+	if !app.tool {
+		steps = append(steps,
+			initStep{"reload", app.initReload},
+			initStep{"migrate", app.initMigrate},
+			initStep{"partitions", app.initPartitions},
+			initStep{"archive", app.initArchive},
+			initStep{"reencrypt", app.initReencrypt},
+			initStep{"listener", app.initListener},
+			initStep{"grpc-server", app.initGRPCServer},
+			initStep{"http-server", app.initHTTPServer},
+		)
+	}
+	steps = append(steps, initStep{"register-closers", app.registerClosers})
 
 	for _, s := range steps {
 		log.Printf("[app] init %s...", s.name)
@@ -80,11 +143,38 @@ func (app *App) initDeps(ctx context.Context) error {
 
 func (app *App) initConfig(ctx context.Context) error {
 	_ = ctx
-	return config.Init()
+	if err := config.Init(); err != nil {
+		return err
+	}
+
+	c := config.AppConfig.Masked()
+	log.Printf("[config] env=%s role=%s instance=%s postgres=%s kafka=%v cache=%s",
+		c.Env, c.Role, c.InstanceID, c.Postgres.DSN, c.Kafka.Brokers, c.Cache.Backend)
+	return nil
+}
+
+func (app *App) initRole(ctx context.Context) error {
+	_ = ctx
+	if app.role == "" {
+		app.role = config.AppConfig.Role
+	}
+	switch app.role {
+	case config.RoleAPI, config.RoleIngester, config.RoleAll:
+		return nil
+	default:
+		return fmt.Errorf("unknown role %q: want %s, %s or %s",
+			app.role, config.RoleAPI, config.RoleIngester, config.RoleAll)
+	}
 }
 
 func (app *App) initOTel(ctx context.Context) error {
-	tel, err := otelx.Init(ctx, serviceName, config.AppConfig.Env)
+	if app.tool {
+		return nil
+	}
+	tel, err := otelx.Init(ctx, serviceName, config.AppConfig.Env,
+		attribute.String("service.role", app.role),
+		semconv.ServiceInstanceID(config.AppConfig.InstanceID),
+	)
 	if err != nil {
 		return err
 	}
@@ -101,6 +191,29 @@ func (app *App) initLogger(ctx context.Context) error {
 	)
 }
 
+// initReload подписывает уровень логирования на hot reload и запускает
+// наблюдение за файлом конфигурации и SIGHUP. Остальные компоненты
+// подписываются сами при создании в DI.
+func (app *App) initReload(ctx context.Context) error {
+	_ = ctx
+	config.Subscribe(func(c *config.Config, changed config.Changes) {
+		if !changed.Has("logger.level") {
+			return
+		}
+		if lvl, err := zapcore.ParseLevel(c.Logger.Level); err == nil {
+			logger.SetLevel(lvl)
+		}
+	})
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+	go config.Watch(watchCtx)
+	closer.AddNamed("config-watch", func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	return nil
+}
+
 func (app *App) initCloser(ctx context.Context) error {
 	_ = ctx
 	closer.SetLogger(logger.Logger())
@@ -110,7 +223,8 @@ func (app *App) initCloser(ctx context.Context) error {
 
 func (app *App) initDi(ctx context.Context) error {
 	_ = ctx
-	app.diContainer = NewDIContainer()
+	app.diContainer = NewDIContainer(app.role)
+	app.diContainer.tool = app.tool
 	return nil
 }
 
@@ -118,9 +232,102 @@ func (app *App) initInfra(ctx context.Context) error {
 	return app.diContainer.Init(ctx)
 }
 
+func (app *App) initMigrate(ctx context.Context) error {
+	if !config.AppConfig.Postgres.MigrateOnStart {
+		return nil
+	}
+	pool, err := app.diContainer.PgxPool(ctx)
+	if err != nil {
+		return err
+	}
+	return migrate.WithPool(ctx, pool, func(r *migrate.Runner) error {
+		n, err := r.Up(ctx)
+		log.Printf("[migrate] applied %d migrations", n)
+		return err
+	})
+}
+
+// initPartitions запускает обслуживание секций в процессах, которые пишут
+// заказы. Реплики соревнуются за advisory lock, работу делает одна.
+func (app *App) initPartitions(ctx context.Context) error {
+	cfg := config.AppConfig.Partitions
+	if !cfg.Enabled || !ingests(app.role) {
+		return nil
+	}
+	pool, err := app.diContainer.PgxPool(ctx)
+	if err != nil {
+		return err
+	}
+
+	m, err := app.diContainer.PartitionMaintainer(ctx)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	go m.Run(runCtx, pool, cfg.Interval)
+	closer.AddNamed("partition-maintenance", func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	return nil
+}
+
+// initArchive запускает архивацию старых заказов в процессах, которые
+// пишут заказы. Как и обслуживание секций, работу делает одна реплика.
+func (app *App) initArchive(ctx context.Context) error {
+	cfg := config.AppConfig.Archive
+	if !cfg.Enabled || !ingests(app.role) {
+		return nil
+	}
+	pool, err := app.diContainer.PgxPool(ctx)
+	if err != nil {
+		return err
+	}
+	a, err := app.diContainer.Archiver(ctx, false)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	go a.Run(runCtx, pool, cfg.Interval)
+	closer.AddNamed("archive", func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	return nil
+}
+
+// initReencrypt запускает перешифровку доставок на активный KEK в процессах,
+// которые пишут заказы. Строки блокируются с SKIP LOCKED, поэтому job
+// работает на всех репликах сразу, без лидера.
+func (app *App) initReencrypt(ctx context.Context) error {
+	cfg := config.AppConfig.Encryption
+	if !cfg.Enabled || cfg.ReencryptInterval == 0 || !ingests(app.role) {
+		return nil
+	}
+	r, err := app.diContainer.PrimaryOrderRepository(ctx)
+	if err != nil {
+		return err
+	}
+
+	runCtx, cancel := context.WithCancel(context.Background())
+	go fieldcrypt.RunReencrypt(runCtx, r, cfg.ReencryptBatch, cfg.ReencryptInterval)
+	closer.AddNamed("reencrypt", func(ctx context.Context) error {
+		cancel()
+		return nil
+	})
+	return nil
+}
+
+// initListener слушает HTTP_ADDR для API. Процесс без API (ingester)
+// поднимает только служебный сервер на HTTP_OPS_ADDR для проб.
 func (app *App) initListener(ctx context.Context) error {
 	_ = ctx
 	addr := config.AppConfig.HTTP.Addr
+	if !app.HTTPEnabled() {
+		addr = config.AppConfig.HTTP.OpsAddr
+	}
 
 	l, err := net.Listen("tcp", addr)
 	if err != nil {
@@ -130,20 +337,119 @@ func (app *App) initListener(ctx context.Context) error {
 	return nil
 }
 
-func (app *App) initHTTPServer(ctx context.Context) error {
+// initGRPCServer поднимает gRPC API рядом с HTTP API. Шаг идёт до
+// http-server: health gRPC переходит в NOT_SERVING раньше, чем readiness
+// выдерживает паузу перед закрытием серверов.
+func (app *App) initGRPCServer(ctx context.Context) error {
+	if !app.GRPCEnabled() {
+		return nil
+	}
+	cfg := config.AppConfig.GRPC
+
 	svc, err := app.diContainer.OrderService(ctx)
 	if err != nil {
 		return err
 	}
+	keys, verifier, err := app.diContainer.Authenticators(ctx)
+	if err != nil {
+		return err
+	}
+	policy, err := app.diContainer.PIIPolicy()
+	if err != nil {
+		return err
+	}
 
-	api, err := v1.NewAPI(svc)
+	l, err := net.Listen("tcp", cfg.Addr)
 	if err != nil {
 		return err
 	}
+	srv, hs := grpcv1.NewServer(svc, grpcv1.NewSecurity(keys, verifier), policy,
+		grpcv1.WithMaxBatch(cfg.MaxBatch),
+		grpcv1.WithReflection(cfg.Reflection),
+	)
+	app.grpcServer, app.grpcLis = srv, l
 
+	closer.AddFirstNamed("grpc-health", func(ctx context.Context) error {
+		hs.Shutdown()
+		return nil
+	})
+	return nil
+}
+
+func (app *App) initHTTPServer(ctx context.Context) error {
+	ready, err := app.diContainer.Readiness(ctx)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health.LiveHandler())
+	mux.Handle("/readyz", ready.ReadyHandler())
+
+	// Первым шагом shutdown процесс становится not-ready и ждёт, пока
+	// балансировщик это заметит, и только потом закрываются сервер и ресурсы.
+	closer.AddFirstNamed("readiness", func(ctx context.Context) error {
+		ready.SetShuttingDown()
+		return sleepCtx(ctx, config.AppConfig.HTTP.ShutdownDelay)
+	})
+
+	if app.HTTPEnabled() {
+		svc, err := app.diContainer.OrderService(ctx)
+		if err != nil {
+			return err
+		}
+
+		sec, err := app.diContainer.Security(ctx)
+		if err != nil {
+			return err
+		}
+
+		policy, err := app.diContainer.PIIPolicy()
+		if err != nil {
+			return err
+		}
+
+		eraser, err := app.diContainer.Eraser(ctx)
+		if err != nil {
+			return err
+		}
+
+		limiter, err := app.diContainer.RateLimiter(ctx)
+		if err != nil {
+			return err
+		}
+
+		compressor, err := app.diContainer.Compressor()
+		if err != nil {
+			return err
+		}
+
+		gql, err := app.diContainer.GraphQL(ctx)
+		if err != nil {
+			return err
+		}
+
+		api, err := v1.NewAPI(svc, eraser, sec, policy,
+			v1.WithRateLimiter(limiter),
+			v1.WithCompressor(compressor),
+			v1.WithOrderMaxAge(config.AppConfig.HTTP.OrderMaxAge),
+			v1.WithFeed(app.diContainer.Feed(), config.AppConfig.Feed.Keepalive),
+			v1.WithGraphQL(gql),
+		)
+		if err != nil {
+			return err
+		}
+		mux.Handle("/", api)
+	}
+
+	cfg := config.AppConfig.HTTP
 	app.httpServer = &http.Server{
-		Addr:    app.listener.Addr().String(),
-		Handler: api,
+		Addr:              app.listener.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
 	}
 	return nil
 }
@@ -165,6 +471,26 @@ func (app *App) registerClosers(ctx context.Context) error {
 		return app.httpServer.Shutdown(ctx)
 	})
 
+	// GracefulStop ждёт открытые потоки ListOrders; по таймауту shutdown
+	// они обрываются.
+	closer.AddNamed("grpc-server", func(ctx context.Context) error {
+		if app.grpcServer == nil {
+			return nil
+		}
+		done := make(chan struct{})
+		go func() {
+			app.grpcServer.GracefulStop()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			app.grpcServer.Stop()
+			return ctx.Err()
+		}
+	})
+
 	closer.AddNamed("listener", func(ctx context.Context) error {
 		if app.listener == nil {
 			return nil
@@ -175,6 +501,48 @@ func (app *App) registerClosers(ctx context.Context) error {
 	return nil
 }
 
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
 func (app *App) DIContainer() *diContainer {
 	return app.diContainer
 }
+
+func (app *App) Role() string {
+	return app.role
+}
+
+// HTTPEnabled сообщает, обслуживает ли процесс HTTP API.
+func (app *App) HTTPEnabled() bool {
+	return !app.tool && servesAPI(app.role)
+}
+
+// GRPCEnabled сообщает, обслуживает ли процесс gRPC API.
+func (app *App) GRPCEnabled() bool {
+	return app.HTTPEnabled() && config.AppConfig.GRPC.Enabled
+}
+
+// WorkerEnabled сообщает, читает ли процесс заказы из Kafka.
+func (app *App) WorkerEnabled() bool {
+	return !app.tool && ingests(app.role)
+}
+
+func servesAPI(role string) bool {
+	return role == config.RoleAPI || role == config.RoleAll
+}
+
+func ingests(role string) bool {
+	return role == config.RoleIngester || role == config.RoleAll
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	orderCache "app/internal/cache/order"
+	"app/internal/closer"
+	"app/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/segmentio/kafka-go"
+)
+
+type CacheStats struct {
+	Backend string `json:"backend"`
+	Source  string `json:"source"`
+	Entries int    `json:"entries"`
+}
+
+// CacheStats считает записи кэша снаружи обслуживающего процесса: для Redis —
+// по ключам с префиксом, для in-memory — по последнему снапшоту.
+func (d *diContainer) CacheStats(ctx context.Context) (CacheStats, error) {
+	cfg := config.AppConfig
+	stats := CacheStats{Backend: cfg.Cache.Backend}
+
+	switch cfg.Cache.Backend {
+	case config.CacheBackendRedis, config.CacheBackendTiered:
+		stats.Source = "redis " + cfg.Redis.Addr
+		stats.Entries = d.redisCache(ctx).Len()
+		return stats, nil
+
+	case config.CacheBackendMemory:
+		path := cfg.Cache.SnapshotPath
+		if path == "" {
+			return stats, errors.New("in-memory cache lives in the serving process: set CACHE_SNAPSHOT_PATH to inspect its snapshot")
+		}
+		c := orderCache.NewWithSoftTTL(d.softTTL, d.ttl)
+		defer c.Close()
+
+		n, err := c.LoadSnapshot(path)
+		if err != nil {
+			return stats, err
+		}
+		stats.Source = "snapshot " + path
+		stats.Entries = n
+		return stats, nil
+
+	default:
+		return stats, fmt.Errorf("unknown cache backend %q", cfg.Cache.Backend)
+	}
+}
+
+// DLQReader читает DLQ-топик в отдельной consumer group, чтобы переотправка
+// не мешала основному consumer'у.
+func (d *diContainer) DLQReader(groupID string) (*kafka.Reader, error) {
+	cfg := config.AppConfig.Kafka
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka brokers is empty")
+	}
+	if cfg.DLQTopic == "" {
+		return nil, errors.New("kafka dlq topic is empty")
+	}
+
+	log.Printf("[kafka] brokers=%v dlq=%q group=%q", cfg.Brokers, cfg.DLQTopic, groupID)
+
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		GroupID: groupID,
+		Topic:   cfg.DLQTopic,
+	})
+	closer.AddNamed("kafka-dlq-reader", func(ctx context.Context) error {
+		return r.Close()
+	})
+	return r, nil
+}
+
+// ReplayWriter пишет сообщения в топик, указанный в самом сообщении.
+func (d *diContainer) ReplayWriter() (*kafka.Writer, error) {
+	cfg := config.AppConfig.Kafka
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka brokers is empty")
+	}
+
+	w := &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}
+	closer.AddNamed("kafka-replay-writer", func(ctx context.Context) error {
+		return w.Close()
+	})
+	return w, nil
+}
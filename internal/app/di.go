@@ -3,58 +3,106 @@ package app
 import (
 	"app/internal/adapter"
 	kaf "app/internal/adapter/kafka"
+	"app/internal/archive"
+	"app/internal/auth"
 	"app/internal/cache"
+	"app/internal/cache/invalidation"
 	cacheobs "app/internal/cache/obs"
 	orderCache "app/internal/cache/order"
+	redisCache "app/internal/cache/redis"
+	"app/internal/cache/tiered"
 	"app/internal/closer"
 	"app/internal/config"
+	"app/internal/erasure"
+	"app/internal/feed"
+	"app/internal/fieldcrypt"
+	"app/internal/graphql"
+	"app/internal/health"
+	"app/internal/http/compress"
+	v1 "app/internal/http/v1"
+	"app/internal/model"
+	"app/internal/objectstore"
+	"app/internal/partition"
+	"app/internal/pii"
+	"app/internal/postgres"
+	"app/internal/ratelimit"
 	"app/internal/repository"
+	"app/internal/repository/apikey"
 	repoobs "app/internal/repository/obs"
 	repo "app/internal/repository/order"
 	serviceInter "app/internal/service"
 	service "app/internal/service/order"
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	goredis "github.com/redis/go-redis/v9"
 	"github.com/segmentio/kafka-go"
 )
 
 type diContainer struct {
+	role string
+	// tool — контейнер разовой команды CLI: in-memory кэш без снапшота и
+	// фоновой очистки, чтобы команда не перезаписала снапшот сервиса.
+	tool bool
+
 	kafkaReader *kafka.Reader
 	dlqWriter   *kafka.Writer
 	pgxPool     *pgxpool.Pool
+	replicaPool *pgxpool.Pool
+	redisClient *goredis.Client
+	invWriter   *kafka.Writer
+	invReader   *invalidation.TopicReader
 	ttl         time.Duration
+	softTTL     time.Duration
 
 	consumer adapter.Consumer
 	svc      serviceInter.Service
-	cache    cache.Cache
+	cache    cache.OrderCache
+	local    *orderCache.CacheOrder
 	repo     repository.Repository
+	baseRepo *repo.OrderRepository
+	cipher   *fieldcrypt.Cipher
+
+	feed       *feed.Hub
+	feedWriter *kafka.Writer
+	feedReader *feed.TailReader
+	feedRelay  *feed.Relay
+
+	apiKeys   *auth.APIKeys
+	jwt       *auth.JWT
+	authReady bool
 
-	worker *kaf.Worker
+	invalidator cache.Invalidator
+	invListener *invalidation.Listener
+
+	worker    *kaf.Worker
+	ready     *health.Checker
+	cacheWarm atomic.Bool
 }
 
-func NewDIContainer() *diContainer {
-	return &diContainer{}
+func NewDIContainer(role string) *diContainer {
+	return &diContainer{role: role}
 }
 
+// Init проверяет конфиг. Инфраструктура (pgx pool, Kafka, Redis) создаётся
+// лениво при первом обращении, поэтому каждая команда поднимает только то,
+// что ей нужно.
 func (d *diContainer) Init(ctx context.Context) error {
+	_ = ctx
 	if config.AppConfig == nil {
 		return errors.New("config.AppConfig is nil: call config.Init() first")
 	}
 
-	if err := d.initTTL(); err != nil {
-		return err
-	}
-	if err := d.initPGX(ctx); err != nil {
-		return err
-	}
-	if err := d.initKafka(); err != nil {
-		return err
-	}
-	return nil
+	return d.initTTL()
 }
 
 func (d *diContainer) OrderAdapter(ctx context.Context) (adapter.Consumer, error) {
@@ -62,10 +110,11 @@ func (d *diContainer) OrderAdapter(ctx context.Context) (adapter.Consumer, error
 	if d.consumer != nil {
 		return d.consumer, nil
 	}
-	if d.kafkaReader == nil {
-		return nil, errors.New("kafka reader is nil: call Init() first")
+	reader, err := d.KafkaReader()
+	if err != nil {
+		return nil, err
 	}
-	d.consumer = kaf.New(d.kafkaReader)
+	d.consumer = kaf.New(reader)
 	return d.consumer, nil
 }
 
@@ -83,27 +132,86 @@ func (d *diContainer) OrderService(ctx context.Context) (serviceInter.Service, e
 		return nil, err
 	}
 
-	d.svc = service.New(r, c)
+	opts := []service.Option{
+		service.WithRefreshTimeout(config.AppConfig.Cache.RefreshTimeout),
+	}
+	if inv := d.CacheInvalidator(ctx); inv != nil {
+		opts = append(opts, service.WithInvalidator(inv))
+	}
+	if pub := d.feedPublisher(ctx); pub != nil {
+		opts = append(opts, service.WithPublisher(pub))
+	}
+
+	d.svc = service.New(r, c, opts...)
 	return d.svc, nil
 }
 
 func (d *diContainer) OrderRepository(ctx context.Context) (repository.Repository, error) {
-	_ = ctx
 	if d.repo != nil {
 		return d.repo, nil
 	}
-	if d.pgxPool == nil {
-		return nil, errors.New("pgx pool is nil: call Init() first")
+	pool, err := d.PgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	replicaPool, err := d.ReplicaPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cipher, err := d.Cipher()
+	if err != nil {
+		return nil, err
 	}
 
-	base := repo.New(d.pgxPool)
+	cfg := config.AppConfig.Postgres
+	opts := []repo.Option{repo.WithCipher(cipher)}
+	if replicaPool != nil {
+		opts = append(opts, repo.WithReplica(postgres.WithAcquireTimeout(replicaPool, cfg.AcquireTimeout), cfg.ReplicaLagWindow))
+	}
+	base := repo.New(postgres.WithAcquireTimeout(pool, cfg.AcquireTimeout), opts...)
+	if replicaPool != nil {
+		d.probeReplica(replicaPool, base)
+	}
+	d.baseRepo = base
 	d.repo = repoobs.Wrap(base)
 
 	return d.repo, nil
 }
 
-func (d *diContainer) OrderCache(ctx context.Context) (cache.Cache, error) {
-	_ = ctx
+// probeReplica раз в postgres.replica_check_interval пингует реплику и
+// переводит чтения на primary, пока она недоступна.
+func (d *diContainer) probeReplica(pool *pgxpool.Pool, r *repo.OrderRepository) {
+	cfg := config.AppConfig.Postgres
+	ctx, cancel := context.WithCancel(context.Background())
+	closer.AddNamed("replica-probe", func(context.Context) error {
+		cancel()
+		return nil
+	})
+
+	go func() {
+		t := time.NewTicker(cfg.ReplicaCheckInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+
+			pingCtx, pingCancel := context.WithTimeout(ctx, cfg.ReplicaCheckInterval)
+			err := pool.Ping(pingCtx)
+			pingCancel()
+			if ctx.Err() != nil {
+				return
+			}
+			r.SetReplicaHealthy(err == nil)
+		}
+	}()
+}
+
+func (d *diContainer) OrderCache(ctx context.Context) (cache.OrderCache, error) {
 	if d.cache != nil {
 		return d.cache, nil
 	}
@@ -111,7 +219,37 @@ func (d *diContainer) OrderCache(ctx context.Context) (cache.Cache, error) {
 		return nil, errors.New("cache ttl is invalid: call Init() first")
 	}
 
-	base := orderCache.New(d.ttl)
+	var base cache.OrderCache
+	switch backend := config.AppConfig.Cache.Backend; backend {
+	case config.CacheBackendMemory:
+		base = d.memoryCache()
+	case config.CacheBackendRedis:
+		r := d.redisCache(ctx)
+		subscribeTTL(r)
+		base = r
+	case config.CacheBackendTiered:
+		r := d.redisCache(ctx)
+		subscribeTTL(r)
+		base = tiered.New[string, model.Order](d.memoryCache(), r)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q", backend)
+	}
+
+	if d.local == nil {
+		d.cacheWarm.Store(true)
+	}
+
+	d.cache = cacheobs.Wrap("orders", base)
+	return d.cache, nil
+}
+
+func (d *diContainer) memoryCache() *orderCache.CacheOrder {
+	base := orderCache.NewWithSoftTTL(d.softTTL, d.ttl)
+	if d.tool {
+		d.cacheWarm.Store(true)
+		d.local = base
+		return base
+	}
 
 	interval := time.Minute
 	if d.ttl < interval {
@@ -119,14 +257,424 @@ func (d *diContainer) OrderCache(ctx context.Context) (cache.Cache, error) {
 	}
 	base.StartWorker(interval)
 
-	d.cache = cacheobs.Wrap(base)
-
 	closer.AddNamed("order-cache", func(ctx context.Context) error {
 		base.Close()
 		return nil
 	})
 
-	return d.cache, nil
+	path := config.AppConfig.Cache.SnapshotPath
+	if path == "" {
+		d.cacheWarm.Store(true)
+	} else {
+		// Снапшот грузится в фоне, чтобы не задерживать старт; до окончания
+		// загрузки /readyz сообщает, что кэш прогревается. Снапшоттер стартует
+		// после загрузки, иначе он перезаписал бы файл неполным кэшем.
+		go func() {
+			n, err := base.LoadSnapshot(path)
+			if err != nil {
+				log.Printf("[cache] load snapshot %s failed: %v", path, err)
+			} else {
+				log.Printf("[cache] restored %d entries from snapshot %s", n, path)
+			}
+			d.cacheWarm.Store(true)
+
+			base.StartSnapshotter(path, config.AppConfig.Cache.SnapshotInterval)
+		}()
+
+		closer.AddNamed("order-cache-snapshot", func(ctx context.Context) error {
+			if !d.cacheWarm.Load() {
+				return nil
+			}
+			_, err := base.SaveSnapshot(path)
+			return err
+		})
+	}
+
+	subscribeTTL(base)
+	d.local = base
+	return base
+}
+
+// subscribeTTL применяет к кэшу новые cache.ttl и cache.soft_ttl при hot reload.
+func subscribeTTL(c cache.TTLSetter) {
+	config.Subscribe(func(cfg *config.Config, changed config.Changes) {
+		if changed.Has("cache.ttl", "cache.soft_ttl") {
+			c.SetTTL(cfg.Cache.SoftTTL, cfg.Cache.TTL)
+		}
+	})
+}
+
+// CacheInvalidator возвращает publisher инвалидаций или nil, если рассылка
+// выключена или у реплики нет локального кэша. Роль не важна: api тоже
+// пишет заказы (gRPC SubmitOrder).
+func (d *diContainer) CacheInvalidator(ctx context.Context) cache.Invalidator {
+	if d.local == nil {
+		return nil
+	}
+	return d.invalidationPublisher(ctx)
+}
+
+// invalidationPublisher создаёт publisher инвалидаций независимо от роли
+// или возвращает nil, если kafka.invalidation_topic не задан.
+func (d *diContainer) invalidationPublisher(ctx context.Context) cache.Invalidator {
+	if d.invalidator != nil {
+		return d.invalidator
+	}
+	cfg := config.AppConfig.Kafka
+	if cfg.InvalidationTopic == "" {
+		return nil
+	}
+
+	log.Printf("[kafka] cache invalidation topic=%q instance=%q", cfg.InvalidationTopic, config.AppConfig.InstanceID)
+
+	ensureCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	if err := invalidation.EnsureTopic(ensureCtx, cfg.Brokers, cfg.InvalidationTopic, cfg.InvalidationReplication); err != nil {
+		log.Printf("[kafka] ensure invalidation topic failed: %v", err)
+	}
+	cancel()
+
+	d.invWriter = &kafka.Writer{
+		Addr:         kafka.TCP(cfg.Brokers...),
+		Topic:        cfg.InvalidationTopic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireOne,
+		BatchTimeout: 10 * time.Millisecond,
+	}
+	closer.AddNamed("kafka-invalidation-writer", func(ctx context.Context) error {
+		return d.invWriter.Close()
+	})
+
+	d.invalidator = invalidation.NewPublisher(d.invWriter, config.AppConfig.InstanceID)
+	return d.invalidator
+}
+
+// InvalidationListener возвращает слушателя инвалидаций для локального кэша
+// или nil, если рассылка выключена. Каждая реплика читает все партиции без
+// consumer group, начиная с момента записи снапшота кэша: инвалидации,
+// пропущенные за время рестарта, применяются к восстановленным записям.
+// Изменённый заказ затем читается с primary, пока реплика БД может
+// отставать, иначе в кэш вернулась бы прежняя версия.
+func (d *diContainer) InvalidationListener(ctx context.Context) (*invalidation.Listener, error) {
+	if d.invListener != nil {
+		return d.invListener, nil
+	}
+	if _, err := d.OrderCache(ctx); err != nil {
+		return nil, err
+	}
+
+	cfg := config.AppConfig.Kafka
+	if cfg.InvalidationTopic == "" || d.local == nil {
+		return nil, nil
+	}
+
+	d.invReader = invalidation.NewTopicReader(cfg.Brokers, cfg.InvalidationTopic, invalidationSince())
+	closer.AddNamed("kafka-invalidation-reader", func(ctx context.Context) error {
+		return d.invReader.Close()
+	})
+
+	if _, err := d.OrderRepository(ctx); err != nil {
+		return nil, err
+	}
+	base := d.baseRepo
+	d.invListener = invalidation.NewListener(d.invReader, d.local, config.AppConfig.InstanceID,
+		invalidation.OnInvalidate(func(key string) {
+			if uid, ok := strings.CutPrefix(key, "order:"); ok {
+				base.MarkWritten(uid)
+			}
+		}),
+	)
+	return d.invListener, nil
+}
+
+// invalidationReplayMargin — запас на расхождение часов реплики и
+// продюсеров инвалидаций; повторное удаление ключа безвредно.
+const invalidationReplayMargin = time.Minute
+
+// invalidationSince — с какого момента читать инвалидации: со времени записи
+// снапшота, из которого восстанавливается кэш, иначе с момента старта.
+func invalidationSince() time.Time {
+	since := time.Now()
+	if path := config.AppConfig.Cache.SnapshotPath; path != "" {
+		saved, err := orderCache.SnapshotTime(path)
+		if err != nil {
+			log.Printf("[cache] read snapshot time %s failed: %v", path, err)
+		} else if !saved.IsZero() && saved.Before(since) {
+			since = saved
+		}
+	}
+	return since.Add(-invalidationReplayMargin)
+}
+
+func (d *diContainer) redisCache(ctx context.Context) *redisCache.CacheOrder {
+	cfg := config.AppConfig.Redis
+	return redisCache.New(d.RedisClient(ctx), d.ttl, cfg.Timeout, cfg.KeyPrefix)
+}
+
+// RedisClient возвращает общий клиент Redis для кэша и лимитов запросов.
+func (d *diContainer) RedisClient(ctx context.Context) *goredis.Client {
+	cfg := config.AppConfig.Redis
+
+	if d.redisClient == nil {
+		log.Printf("[redis] addr=%s db=%d", cfg.Addr, cfg.DB)
+		d.redisClient = goredis.NewClient(&goredis.Options{
+			Addr:         cfg.Addr,
+			Password:     cfg.Password,
+			DB:           cfg.DB,
+			DialTimeout:  cfg.Timeout,
+			ReadTimeout:  cfg.Timeout,
+			WriteTimeout: cfg.Timeout,
+		})
+
+		pingCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+		if err := d.redisClient.Ping(pingCtx).Err(); err != nil {
+			log.Printf("[redis] ping failed, cache will degrade to misses: %v", err)
+		}
+		cancel()
+
+		closer.AddNamed("redis-client", func(ctx context.Context) error {
+			return d.redisClient.Close()
+		})
+	}
+
+	return d.redisClient
+}
+
+// Feed — лента новых заказов по feed.*; nil, если она выключена или
+// процесс не обслуживает API. С kafka.feed_topic хаб наполняет FeedRelay из
+// общего топика, и ID событий одинаковы на всех репликах; без него в ленту
+// попадают только заказы, записанные этим процессом.
+func (d *diContainer) Feed() *feed.Hub {
+	cfg := config.AppConfig.Feed
+	if d.feed != nil || !cfg.Enabled || !servesAPI(d.role) {
+		return d.feed
+	}
+
+	opts := []feed.Option{
+		feed.WithRingSize(cfg.RingSize),
+		feed.WithBuffer(cfg.Buffer),
+		feed.WithSlowPolicy(feed.SlowPolicy(cfg.SlowPolicy)),
+		feed.WithMaxSubscribers(cfg.MaxSubscribers),
+	}
+	if config.AppConfig.Kafka.FeedTopic != "" {
+		opts = append(opts, feed.WithEpoch(feedEpoch))
+	}
+	d.feed = feed.NewHub(opts...)
+	// Открытые потоки закрываются вместе с HTTP-сервером, иначе Shutdown
+	// ждал бы их до таймаута.
+	closer.AddNamed("feed", d.feed.Close)
+	log.Printf("[feed] ring=%d buffer=%d slow_policy=%s topic=%q",
+		cfg.RingSize, cfg.Buffer, cfg.SlowPolicy, config.AppConfig.Kafka.FeedTopic)
+	return d.feed
+}
+
+// feedEpoch — эпоха ID событий ленты из топика: общая для всех реплик.
+const feedEpoch = "kafka"
+
+// feedPublisher — куда сервис отдаёт записанные заказы: в kafka.feed_topic
+// при любой роли (api тоже пишет заказы через gRPC), а без топика — прямо в
+// хаб процесса. nil, если лента выключена или заказам некуда идти.
+func (d *diContainer) feedPublisher(ctx context.Context) serviceInter.Publisher {
+	if !config.AppConfig.Feed.Enabled {
+		return nil
+	}
+	cfg := config.AppConfig.Kafka
+	if cfg.FeedTopic == "" {
+		if hub := d.Feed(); hub != nil {
+			return hub
+		}
+		return nil
+	}
+	if d.feedWriter == nil {
+		ensureCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		if err := feed.EnsureTopic(ensureCtx, cfg.Brokers, cfg.FeedTopic, cfg.FeedReplication, cfg.FeedRetention); err != nil {
+			log.Printf("[kafka] ensure feed topic failed: %v", err)
+		}
+		cancel()
+
+		d.feedWriter = &kafka.Writer{
+			Addr:         kafka.TCP(cfg.Brokers...),
+			Topic:        cfg.FeedTopic,
+			RequiredAcks: kafka.RequireOne,
+			BatchTimeout: 10 * time.Millisecond,
+		}
+		closer.AddNamed("kafka-feed-writer", func(ctx context.Context) error {
+			return d.feedWriter.Close()
+		})
+		log.Printf("[kafka] feed topic=%q", cfg.FeedTopic)
+	}
+	return feed.NewTopicPublisher(d.feedWriter)
+}
+
+// FeedRelay возвращает перенос событий из kafka.feed_topic в хаб или nil,
+// если лента выключена, процесс не обслуживает API или топик не задан.
+// Каждая реплика читает топик без consumer group с последних
+// feed.ring_size событий, поэтому кольца реплик совпадают.
+func (d *diContainer) FeedRelay() *feed.Relay {
+	if d.feedRelay != nil {
+		return d.feedRelay
+	}
+	cfg := config.AppConfig.Kafka
+	hub := d.Feed()
+	if hub == nil || cfg.FeedTopic == "" {
+		return nil
+	}
+
+	d.feedReader = feed.NewTailReader(cfg.Brokers, cfg.FeedTopic, config.AppConfig.Feed.RingSize)
+	closer.AddNamed("kafka-feed-reader", func(ctx context.Context) error {
+		return d.feedReader.Close()
+	})
+	d.feedRelay = feed.NewRelay(d.feedReader, hub)
+	return d.feedRelay
+}
+
+// GraphQL — обработчик /graphql по graphql.*; nil, если он выключен.
+func (d *diContainer) GraphQL(ctx context.Context) (http.Handler, error) {
+	cfg := config.AppConfig.GraphQL
+	if !cfg.Enabled {
+		return nil, nil
+	}
+
+	svc, err := d.OrderService(ctx)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := d.PIIPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[graphql] max_depth=%d max_complexity=%d introspection=%v", cfg.MaxDepth, cfg.MaxComplexity, cfg.Introspection)
+	return graphql.NewHandler(svc, policy,
+		graphql.WithMaxDepth(cfg.MaxDepth),
+		graphql.WithMaxComplexity(cfg.MaxComplexity),
+		graphql.WithIntrospection(cfg.Introspection),
+	), nil
+}
+
+// Compressor — сжатие ответов API по http.compress_*; nil, если список
+// кодировок пуст.
+func (d *diContainer) Compressor() (*compress.Compressor, error) {
+	cfg := config.AppConfig.HTTP
+	if len(cfg.CompressEncodings) == 0 {
+		return nil, nil
+	}
+	return compress.New(cfg.CompressEncodings, compress.MinSize(cfg.CompressMinSize))
+}
+
+// RateLimiter собирает лимиты запросов по ratelimit.* и подписывает их на
+// hot reload. Выключенный лимитер не ограничивает ни один маршрут. Клиент
+// с проверенными учётными данными получает бакет по subject.
+func (d *diContainer) RateLimiter(ctx context.Context) (*ratelimit.Limiter, error) {
+	cfg := config.AppConfig.RateLimit
+
+	sec, err := d.Security(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var store ratelimit.Store = ratelimit.NewMemory()
+	if cfg.Backend == config.RateLimitBackendRedis {
+		store = ratelimit.NewRedis(d.RedisClient(ctx), config.AppConfig.Redis.KeyPrefix+"ratelimit:")
+	}
+
+	rules, err := rateLimitRules(cfg)
+	if err != nil {
+		return nil, err
+	}
+	l := ratelimit.New(store, rules,
+		ratelimit.TrustProxy(proxyHops(cfg)),
+		ratelimit.Identify(func(r *http.Request) (string, bool) {
+			id, err := sec.Authenticate(r)
+			return id.Subject, err == nil
+		}),
+	)
+
+	config.Subscribe(func(c *config.Config, changed config.Changes) {
+		if !changed.Has("ratelimit.enabled", "ratelimit.default", "ratelimit.routes", "ratelimit.trust_proxy", "ratelimit.proxy_hops") {
+			return
+		}
+		// Правила проверены при загрузке конфига.
+		if rules, err := rateLimitRules(c.RateLimit); err == nil {
+			l.SetRules(rules)
+		}
+		l.SetTrustProxy(proxyHops(c.RateLimit))
+	})
+
+	log.Printf("[ratelimit] enabled=%t backend=%s default=%s", cfg.Enabled, cfg.Backend, cfg.Default)
+	return l, nil
+}
+
+// proxyHops — сколько адресов X-Forwarded-For дописали доверенные прокси;
+// 0 — заголовкам не доверять.
+func proxyHops(cfg config.RateLimitConfig) int {
+	if !cfg.TrustProxy {
+		return 0
+	}
+	return cfg.ProxyHops
+}
+
+func rateLimitRules(cfg config.RateLimitConfig) (ratelimit.Rules, error) {
+	if !cfg.Enabled {
+		return ratelimit.Rules{}, nil
+	}
+	return ratelimit.ParseRules(cfg.Default, cfg.Routes)
+}
+
+// Readiness собирает проверки готовности для роли процесса. API не зависит
+// от Kafka и остаётся готовым, даже если брокеры недоступны.
+func (d *diContainer) Readiness(ctx context.Context) (*health.Checker, error) {
+	if d.ready != nil {
+		return d.ready, nil
+	}
+
+	pool, err := d.PgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c := health.New(config.AppConfig.HTTP.ReadyTimeout)
+	c.Add("postgres", pool.Ping)
+
+	if ingests(d.role) {
+		worker, err := d.Worker(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		brokers := config.AppConfig.Kafka.Brokers
+		c.Add("kafka", func(ctx context.Context) error {
+			return pingKafka(ctx, brokers)
+		})
+
+		stall := config.AppConfig.Kafka.WorkerStallTimeout
+		c.Add("worker", func(ctx context.Context) error {
+			return worker.Alive(stall)
+		})
+	}
+
+	if servesAPI(d.role) {
+		c.Add("cache", func(ctx context.Context) error {
+			if !d.cacheWarm.Load() {
+				return errors.New("warming up")
+			}
+			return nil
+		})
+	}
+
+	d.ready = c
+	return d.ready, nil
+}
+
+func pingKafka(ctx context.Context, brokers []string) error {
+	err := errors.New("kafka brokers is empty")
+	for _, b := range brokers {
+		var conn *kafka.Conn
+		conn, err = kafka.DialContext(ctx, "tcp", b)
+		if err == nil {
+			return conn.Close()
+		}
+	}
+	return err
 }
 
 func (d *diContainer) Worker(ctx context.Context) (*kaf.Worker, error) {
@@ -142,54 +690,309 @@ func (d *diContainer) Worker(ctx context.Context) (*kaf.Worker, error) {
 	if err != nil {
 		return nil, err
 	}
-	if d.dlqWriter == nil {
-		return nil, errors.New("dlq writer is nil: call Init() first")
+	dlq, err := d.DLQWriter()
+	if err != nil {
+		return nil, err
 	}
 
-	d.worker = kaf.NewWorker(consumer, svc, d.dlqWriter)
+	d.worker = kaf.NewWorker(consumer, svc, dlq,
+		kaf.WithRetries(retryPolicy(config.Current().Kafka)),
+	)
+	worker := d.worker
+	config.Subscribe(func(c *config.Config, changed config.Changes) {
+		if changed.Has("kafka.max_retries", "kafka.retry_base_backoff", "kafka.retry_max_backoff") {
+			worker.SetRetryPolicy(retryPolicy(c.Kafka))
+		}
+	})
 	return d.worker, nil
 }
 
-func (d *diContainer) initTTL() error {
-	d.ttl = config.AppConfig.Cache.TTL
-	if d.ttl <= 0 {
-		d.ttl = 5 * time.Minute
+func retryPolicy(cfg config.KafkaConfig) kaf.RetryPolicy {
+	return kaf.RetryPolicy{
+		MaxRetries:  cfg.MaxRetries,
+		BaseBackoff: cfg.RetryBaseBackoff,
+		MaxBackoff:  cfg.RetryMaxBackoff,
 	}
+}
+
+func (d *diContainer) initTTL() error {
+	d.ttl = config.Current().Cache.TTL
+	d.softTTL = config.Current().Cache.SoftTTL
 	return nil
 }
 
-func (d *diContainer) initPGX(ctx context.Context) error {
-	log.Printf("[pg] connecting: %s", config.AppConfig.Postgres.DSN)
-	pool, err := pgxpool.New(ctx, config.AppConfig.Postgres.DSN)
+func (d *diContainer) PgxPool(ctx context.Context) (*pgxpool.Pool, error) {
+	if d.pgxPool != nil {
+		return d.pgxPool, nil
+	}
+
+	pool, err := newPgxPool(ctx, "primary", config.AppConfig.Postgres.DSN)
 	if err != nil {
-		log.Printf("[pg] connect failed: %v", err)
-		return err
+		return nil, err
 	}
 	d.pgxPool = pool
-	log.Printf("[pg] pool created")
+	return d.pgxPool, nil
+}
+
+// ReplicaPool возвращает пул реплики или nil, если postgres.replica_dsn пуст.
+func (d *diContainer) ReplicaPool(ctx context.Context) (*pgxpool.Pool, error) {
+	if d.replicaPool != nil || config.AppConfig.Postgres.ReplicaDSN == "" {
+		return d.replicaPool, nil
+	}
+
+	pool, err := newPgxPool(ctx, "replica", config.AppConfig.Postgres.ReplicaDSN)
+	if err != nil {
+		return nil, err
+	}
+	d.replicaPool = pool
+	return d.replicaPool, nil
+}
 
-	closer.AddNamed("pgxpool", func(ctx context.Context) error {
-		d.pgxPool.Close()
+// PartitionMaintainer — обслуживание секций. С включённым архивом месяц
+// старше retention выгружается архиватором перед удалением секций.
+func (d *diContainer) PartitionMaintainer(ctx context.Context) (*partition.Maintainer, error) {
+	cfg := config.AppConfig.Partitions
+	opts := partition.Options{
+		PremakeMonths:   cfg.PremakeMonths,
+		RetentionMonths: cfg.RetentionMonths,
+	}
+	if config.AppConfig.Archive.Enabled {
+		a, err := d.Archiver(ctx, false)
+		if err != nil {
+			return nil, err
+		}
+		opts.Archive = func(ctx context.Context, conn partition.Conn, month time.Time) error {
+			_, err := a.ArchiveMonth(ctx, conn, month)
+			return err
+		}
+	}
+	return partition.New(opts), nil
+}
+
+// APIKeyRepository — ключи API в PostgreSQL (primary: только что выданный
+// ключ должен работать сразу).
+func (d *diContainer) APIKeyRepository(ctx context.Context) (*apikey.Repository, error) {
+	pool, err := d.PgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return apikey.New(postgres.WithAcquireTimeout(pool, config.AppConfig.Postgres.AcquireTimeout)), nil
+}
+
+// Authenticators собирает проверку API-ключей и JWT по auth.*. Выключенная
+// схема — nil. Результат общий для HTTP и gRPC: JWKS обновляется один раз.
+func (d *diContainer) Authenticators(ctx context.Context) (*auth.APIKeys, *auth.JWT, error) {
+	if d.authReady {
+		return d.apiKeys, d.jwt, nil
+	}
+	cfg := config.AppConfig.Auth
+
+	var keys *auth.APIKeys
+	if cfg.APIKeys {
+		store, err := d.APIKeyRepository(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		keys = auth.NewAPIKeys(store, cfg.APIKeyCacheTTL)
+	}
+
+	var verifier *auth.JWT
+	if cfg.JWTEnabled() {
+		// ctx фонового обновления JWKS живёт до shutdown, а не до конца init.
+		refreshCtx, cancel := context.WithCancel(context.Background())
+		v, err := auth.NewJWT(refreshCtx, auth.JWTOptions{
+			JWKSURL:         cfg.JWKSURL,
+			JWKSFile:        cfg.JWKSFile,
+			RefreshInterval: cfg.JWKSRefresh,
+			Issuer:          cfg.JWTIssuer,
+			Audience:        cfg.JWTAudience,
+			Leeway:          cfg.JWTLeeway,
+		})
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		closer.AddNamed("jwks-refresh", func(ctx context.Context) error {
+			cancel()
+			return nil
+		})
+		verifier = v
+	}
+
+	log.Printf("[auth] api_keys=%t jwt=%t", keys != nil, verifier != nil)
+	d.apiKeys, d.jwt, d.authReady = keys, verifier, true
+	return keys, verifier, nil
+}
+
+// Security — схемы безопасности HTTP API.
+func (d *diContainer) Security(ctx context.Context) (*v1.Security, error) {
+	keys, verifier, err := d.Authenticators(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return v1.NewSecurity(keys, verifier), nil
+}
+
+// PIIPolicy — политика маскирования с правилами pii.rules (проверены при загрузке конфига).
+func (d *diContainer) PIIPolicy() (pii.Policy, error) {
+	return pii.DefaultPolicy.WithRules(config.AppConfig.PII.Rules)
+}
+
+// ObjectStore возвращает хранилище архива по archive.store.
+func (d *diContainer) ObjectStore() objectstore.Store {
+	cfg := config.AppConfig.Archive
+	if cfg.Store == config.ArchiveStoreS3 {
+		return objectstore.NewS3(objectstore.S3Options{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			Prefix:    cfg.S3Prefix,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	}
+	return objectstore.NewFS(cfg.FSDir)
+}
+
+// Eraser возвращает сервис удаления персональных данных. Удаление
+// рассылается всем репликам с локальным кэшем, в какой бы роли ни
+// выполнялось: иначе стёртые данные отдавались бы до истечения TTL.
+func (d *diContainer) Eraser(ctx context.Context) (*erasure.Service, error) {
+	r, err := d.PrimaryOrderRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c, err := d.OrderCache(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []erasure.Option
+	if inv := d.invalidationPublisher(ctx); inv != nil {
+		opts = append(opts, erasure.WithInvalidator(inv))
+	}
+	return erasure.New(r, c, opts...), nil
+}
+
+// Cipher возвращает шифр персональных данных доставки или nil, если
+// шифрование выключено.
+func (d *diContainer) Cipher() (*fieldcrypt.Cipher, error) {
+	cfg := config.AppConfig.Encryption
+	if !cfg.Enabled || d.cipher != nil {
+		return d.cipher, nil
+	}
+
+	raw := cfg.Keys
+	if cfg.KeysFile != "" {
+		b, err := os.ReadFile(cfg.KeysFile)
+		if err != nil {
+			return nil, fmt.Errorf("encryption.keys_file: %w", err)
+		}
+		raw = string(b)
+	}
+	keks, err := fieldcrypt.ParseKeys(raw)
+	if err != nil {
+		return nil, fmt.Errorf("encryption.keys: %w", err)
+	}
+	bidxKey, err := base64.StdEncoding.DecodeString(cfg.BlindIndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("encryption.blind_index_key: %w", err)
+	}
+
+	c, err := fieldcrypt.New(keks, cfg.ActiveKeyID, bidxKey)
+	if err != nil {
+		return nil, err
+	}
+	d.cipher = c
+	return d.cipher, nil
+}
+
+// PrimaryOrderRepository — репозиторий без реплики и обёртки метрик для
+// фоновых задач, которым нужны его служебные методы.
+func (d *diContainer) PrimaryOrderRepository(ctx context.Context) (*repo.OrderRepository, error) {
+	pool, err := d.PgxPool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cipher, err := d.Cipher()
+	if err != nil {
+		return nil, err
+	}
+	return repo.New(postgres.WithAcquireTimeout(pool, config.AppConfig.Postgres.AcquireTimeout),
+		repo.WithCipher(cipher)), nil
+}
+
+// Archiver читает и удаляет заказы только через primary: реплика может
+// отставать, и удаление не совпадёт с выгрузкой.
+func (d *diContainer) Archiver(ctx context.Context, dryRun bool) (*archive.Archiver, error) {
+	src, err := d.PrimaryOrderRepository(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := config.AppConfig.Archive
+	return archive.New(src, d.ObjectStore(), archive.Options{
+		AfterMonths: cfg.AfterMonths,
+		BatchSize:   cfg.BatchSize,
+		DryRun:      dryRun,
+	}), nil
+}
+
+// newPgxPool создаёт пул с общими настройками postgres.* и метриками
+// pg_pool_* с атрибутом pool=name.
+func newPgxPool(ctx context.Context, name, dsn string) (*pgxpool.Pool, error) {
+	cfg := config.AppConfig.Postgres
+	log.Printf("[pg] %s connecting: %s max_conns=%d min_conns=%d statement_timeout=%s acquire_timeout=%s",
+		name, config.MaskDSN(dsn), cfg.MaxConns, cfg.MinConns, cfg.StatementTimeout, cfg.AcquireTimeout)
+
+	poolCfg, err := postgres.ParseConfig(dsn, postgres.PoolOptions{
+		MaxConns:          int32(cfg.MaxConns),
+		MinConns:          int32(cfg.MinConns),
+		MaxConnLifetime:   cfg.MaxConnLifetime,
+		MaxConnIdleTime:   cfg.MaxConnIdleTime,
+		HealthCheckPeriod: cfg.HealthCheckPeriod,
+		StatementTimeout:  cfg.StatementTimeout,
+		ApplicationName:   cfg.ApplicationName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("parse postgres %s dsn: %w", name, err)
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolCfg)
+	if err != nil {
+		log.Printf("[pg] %s connect failed: %v", name, err)
+		return nil, err
+	}
+	log.Printf("[pg] %s pool created", name)
+
+	reg, err := postgres.RegisterMetrics(pool, name)
+	if err != nil {
+		log.Printf("[pg] %s pool metrics disabled: %v", name, err)
+	}
+
+	closer.AddNamed("pgxpool-"+name, func(ctx context.Context) error {
+		if reg != nil {
+			_ = reg.Unregister()
+		}
+		pool.Close()
 		return nil
 	})
-	return nil
+	return pool, nil
 }
 
-func (d *diContainer) initKafka() error {
-	cfg := config.AppConfig.Kafka
+func (d *diContainer) KafkaReader() (*kafka.Reader, error) {
+	if d.kafkaReader != nil {
+		return d.kafkaReader, nil
+	}
 
-	log.Printf("[kafka] brokers=%v topic=%q group=%q dlq=%q",
-		cfg.Brokers, cfg.Topic, cfg.GroupID, cfg.DLQTopic,
-	)
+	cfg := config.AppConfig.Kafka
+	log.Printf("[kafka] brokers=%v topic=%q group=%q", cfg.Brokers, cfg.Topic, cfg.GroupID)
 
 	if len(cfg.Brokers) == 0 {
-		return errors.New("kafka brokers is empty")
+		return nil, errors.New("kafka brokers is empty")
 	}
 	if cfg.Topic == "" {
-		return errors.New("kafka topic is empty")
-	}
-	if cfg.DLQTopic == "" {
-		return errors.New("kafka dlq topic is empty")
+		return nil, errors.New("kafka topic is empty")
 	}
 
 	d.kafkaReader = kafka.NewReader(kafka.ReaderConfig{
@@ -198,6 +1001,27 @@ func (d *diContainer) initKafka() error {
 		Topic:   cfg.Topic,
 	})
 
+	closer.AddNamed("kafka-reader", func(ctx context.Context) error {
+		return d.kafkaReader.Close()
+	})
+	return d.kafkaReader, nil
+}
+
+func (d *diContainer) DLQWriter() (*kafka.Writer, error) {
+	if d.dlqWriter != nil {
+		return d.dlqWriter, nil
+	}
+
+	cfg := config.AppConfig.Kafka
+	log.Printf("[kafka] brokers=%v dlq=%q", cfg.Brokers, cfg.DLQTopic)
+
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafka brokers is empty")
+	}
+	if cfg.DLQTopic == "" {
+		return nil, errors.New("kafka dlq topic is empty")
+	}
+
 	d.dlqWriter = &kafka.Writer{
 		Addr:         kafka.TCP(cfg.Brokers...),
 		Topic:        cfg.DLQTopic,
@@ -206,12 +1030,8 @@ func (d *diContainer) initKafka() error {
 		Async:        false,
 	}
 
-	closer.AddNamed("kafka-reader", func(ctx context.Context) error {
-		return d.kafkaReader.Close()
-	})
 	closer.AddNamed("kafka-dlq-writer", func(ctx context.Context) error {
 		return d.dlqWriter.Close()
 	})
-
-	return nil
+	return d.dlqWriter, nil
 }
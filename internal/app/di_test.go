@@ -0,0 +1,100 @@
+package app
+
+import (
+	"app/internal/closer"
+	"app/internal/config"
+	"app/internal/logger"
+	"app/internal/model"
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRepo struct{}
+
+func (fakeRepo) SetOrder(context.Context, model.Order) error { return nil }
+
+func (fakeRepo) GetOrder(context.Context, string) (model.Order, error) {
+	return model.Order{}, model.ErrNotFound
+}
+
+func (fakeRepo) GetOrders(context.Context, []string) ([]model.Order, error) { return nil, nil }
+
+func (fakeRepo) ListOrderUIDs(context.Context, model.OrderFilter) ([]string, error) {
+	return nil, nil
+}
+
+type fakeInvalidator struct {
+	mu   sync.Mutex
+	keys []string
+}
+
+func (f *fakeInvalidator) Invalidate(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+// useConfig подменяет config.AppConfig на время теста. config.Init
+// вызывается заранее: иначе первый config.Current() загрузил бы конфиг
+// заново поверх подменённого.
+func useConfig(t *testing.T, c config.Config) {
+	t.Helper()
+	_ = config.Init()
+	prev := config.AppConfig
+	config.AppConfig = &c
+	t.Cleanup(func() { config.AppConfig = prev })
+}
+
+// Реплика api пишет заказы через gRPC и должна рассылать инвалидации,
+// иначе остальные реплики отдают старый заказ до истечения TTL.
+func TestDIContainer_APIRoleServicePublishesInvalidations(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+	c := config.Defaults()
+	c.Cache.Backend = config.CacheBackendMemory
+	c.Kafka.InvalidationTopic = "orders.invalidation"
+	c.Feed.Enabled = false
+	useConfig(t, c)
+
+	ctx := context.Background()
+	d := NewDIContainer(config.RoleAPI)
+	require.NoError(t, d.Init(ctx))
+	d.repo = fakeRepo{}
+	inv := &fakeInvalidator{}
+	d.invalidator = inv
+
+	svc, err := d.OrderService(ctx)
+	require.NoError(t, err)
+	require.NoError(t, svc.ProcessOrder(ctx, model.Order{OrderUUID: "a"}))
+	require.Equal(t, []string{"order:a"}, inv.keys)
+}
+
+// Команда CLI не читает и не пишет снапшот кэша: иначе при выходе она
+// перезаписала бы снапшот сервиса своим почти пустым кэшем.
+func TestDIContainer_ToolSkipsCacheSnapshot(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+	c := config.Defaults()
+	c.Cache.Backend = config.CacheBackendMemory
+	c.Cache.SnapshotPath = filepath.Join(t.TempDir(), "cache.snapshot")
+	useConfig(t, c)
+
+	ctx := context.Background()
+	d := NewDIContainer(config.RoleAll)
+	d.tool = true
+	require.NoError(t, d.Init(ctx))
+
+	oc, err := d.OrderCache(ctx)
+	require.NoError(t, err)
+	oc.Set("order:a", model.Order{OrderUUID: "a"})
+	require.Eventually(t, d.cacheWarm.Load, time.Second, time.Millisecond)
+
+	require.NoError(t, closer.CloseAll(ctx))
+	_, err = os.Stat(c.Cache.SnapshotPath)
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"app/internal/config"
+	"app/internal/feed"
+	"app/internal/http/compress"
+	"app/internal/pii"
+	"app/internal/ratelimit"
+	"fmt"
+)
+
+// ConfigValidators — проверки конфига, которые разбирают значения доменными
+// пакетами. Передаются в config.Sources, поэтому работают и при reload.
+func ConfigValidators() []config.Validator {
+	return []config.Validator{validateDomainConfig}
+}
+
+func validateDomainConfig(c *config.Config) []string {
+	var p []string
+	add := func(key, format string, args ...any) {
+		p = append(p, key+": "+fmt.Sprintf(format, args...))
+	}
+
+	if _, err := compress.New(c.HTTP.CompressEncodings); err != nil {
+		add("http.compress_encodings", "%v", err)
+	}
+
+	switch feed.SlowPolicy(c.Feed.SlowPolicy) {
+	case feed.Drop, feed.Disconnect:
+	default:
+		add("feed.slow_policy", "must be one of %s, %s, got %q", feed.Drop, feed.Disconnect, c.Feed.SlowPolicy)
+	}
+
+	if _, err := ratelimit.ParseLimit(c.RateLimit.Default); err != nil {
+		add("ratelimit.default", "%v", err)
+	}
+	for _, r := range c.RateLimit.Routes {
+		if _, err := ratelimit.ParseRule(r); err != nil {
+			add("ratelimit.routes", "%v", err)
+		}
+	}
+
+	if _, err := pii.DefaultPolicy.WithRules(c.PII.Rules); err != nil {
+		add("pii.rules", "%v", err)
+	}
+	return p
+}
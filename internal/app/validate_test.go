@@ -0,0 +1,34 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"app/internal/config"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigValidators(t *testing.T) {
+	_, err := config.Load(config.Sources{
+		Overrides: map[string]string{
+			"ratelimit.default":       "fast",
+			"ratelimit.routes":        "/admin/=1/s:5, order=1/s",
+			"feed.slow_policy":        "block",
+			"http.compress_encodings": "gzip,lz4",
+		},
+		Validators: ConfigValidators(),
+	})
+
+	var verr *config.ValidationError
+	require.True(t, errors.As(err, &verr), "want ValidationError, got %v", err)
+	require.Contains(t, verr.Problems, `ratelimit.default: limit "fast": want RATE/PERIOD[:BURST] or off`)
+	require.Contains(t, verr.Problems, `ratelimit.routes: rule "order=1/s": path prefix must start with /`)
+	require.Contains(t, verr.Problems, `feed.slow_policy: must be one of drop, disconnect, got "block"`)
+	require.Len(t, verr.Problems, 4)
+}
+
+func TestConfigValidators_Defaults(t *testing.T) {
+	_, err := config.Load(config.Sources{Validators: ConfigValidators()})
+	require.NoError(t, err)
+}
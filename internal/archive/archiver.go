@@ -0,0 +1,314 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"app/internal/model"
+	"app/internal/objectstore"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID — ключ advisory lock: архивацию выполняет только одна реплика.
+const lockID int64 = 7_250_003
+
+var (
+	ErrCountMismatch = errors.New("archive: row count mismatch")
+	ErrBusy          = errors.New("archive: running on another instance")
+)
+
+// Source — откуда архивируются заказы (см. repository/order).
+type Source interface {
+	OldestOrderDate(ctx context.Context) (time.Time, bool, error)
+	CountOrders(ctx context.Context, from, to time.Time) (int, error)
+	ScanOrders(ctx context.Context, from, to time.Time, batch int, fn func(model.Order) error) (int, error)
+	DeleteOrders(ctx context.Context, from, to time.Time, expect int) error
+}
+
+type Options struct {
+	// AfterMonths — архивируются месяцы, закончившиеся больше AfterMonths
+	// месяцев назад: при 12 в октябре 2026 — всё до октября 2025.
+	AfterMonths int
+	// BatchSize — сколько заказов читать из БД за один запрос.
+	BatchSize int
+	// DryRun — выгрузить и проверить файлы, но не удалять строки.
+	DryRun bool
+}
+
+// Conn — выделенное соединение для advisory lock (см. partition.Conn).
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Report — что сделал один запуск. Leader == false: архивацию выполняет другая реплика.
+type Report struct {
+	Leader bool
+	Months []Month
+}
+
+// Month — результат архивации одного месяца.
+type Month struct {
+	Month   time.Time
+	Key     string
+	Orders  int
+	Deleted bool
+}
+
+type Archiver struct {
+	src   Source
+	store objectstore.Store
+	opts  Options
+	now   func() time.Time
+}
+
+func New(src Source, store objectstore.Store, opts Options) *Archiver {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	return &Archiver{src: src, store: store, opts: opts, now: time.Now}
+}
+
+// maxParts ограничивает перебор частей одного месяца.
+const maxParts = 1000
+
+// Key — ключ part-й части месяца в хранилище: orders/2025/03.avro для
+// первой, orders/2025/03-2.avro и далее для следующих. Хранилище не
+// перезаписывает объекты, поэтому каждая архивация месяца (заказы, дошедшие
+// после прошлой, или повтор после ErrCountMismatch) пишет новую часть.
+func Key(month time.Time, part int) string {
+	if part <= 1 {
+		return fmt.Sprintf("orders/%04d/%02d.avro", month.Year(), int(month.Month()))
+	}
+	return fmt.Sprintf("orders/%04d/%02d-%d.avro", month.Year(), int(month.Month()), part)
+}
+
+// Cutoff — начало первого месяца, который ещё не архивируется.
+func (a *Archiver) Cutoff() time.Time {
+	now := a.now().UTC()
+	cur := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return cur.AddDate(0, -a.opts.AfterMonths, 0)
+}
+
+// RunOnce архивирует все месяцы старше Cutoff, от самого старого. Каждый месяц
+// выгружается в Avro OCF, загружается в хранилище, перечитывается оттуда
+// для сверки числа записей и только после этого удаляется из БД.
+// На первой ошибке RunOnce останавливается: следующий запуск продолжит
+// с того же месяца. conn держит advisory lock на время запуска.
+func (a *Archiver) RunOnce(ctx context.Context, conn Conn) (rep Report, err error) {
+	rep.Leader, err = withLock(ctx, conn, func() error {
+		var err error
+		rep.Months, err = a.archive(ctx)
+		return err
+	})
+	return rep, err
+}
+
+// ArchiveMonth архивирует один месяц под тем же advisory lock, что и RunOnce:
+// так обслуживание секций выгружает месяц перед удалением его секций.
+// Если архивация сейчас идёт на другой реплике, возвращает ErrBusy.
+func (a *Archiver) ArchiveMonth(ctx context.Context, conn Conn, month time.Time) (res Month, err error) {
+	leader, err := withLock(ctx, conn, func() error {
+		var err error
+		res, err = a.month(ctx, month)
+		return err
+	})
+	if err == nil && !leader {
+		err = ErrBusy
+	}
+	return res, err
+}
+
+// withLock выполняет fn, если удалось взять advisory lock; false — lock
+// держит другая реплика.
+func withLock(ctx context.Context, conn Conn, fn func() error) (locked bool, err error) {
+	rows, err := conn.Query(ctx, "SELECT pg_try_advisory_lock($1)", lockID)
+	if err != nil {
+		return false, fmt.Errorf("archive: acquire lock: %w", err)
+	}
+	locked, err = pgx.CollectExactlyOneRow(rows, pgx.RowTo[bool])
+	if err != nil {
+		return false, fmt.Errorf("archive: acquire lock: %w", err)
+	}
+	if !locked {
+		return false, nil
+	}
+	defer func() {
+		if _, uerr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockID); uerr != nil && err == nil {
+			err = fmt.Errorf("archive: release lock: %w", uerr)
+		}
+	}()
+
+	return true, fn()
+}
+
+func (a *Archiver) archive(ctx context.Context) ([]Month, error) {
+	oldest, ok, err := a.src.OldestOrderDate(ctx)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	cutoff := a.Cutoff()
+	oldest = oldest.UTC()
+	var done []Month
+	for m := time.Date(oldest.Year(), oldest.Month(), 1, 0, 0, 0, 0, time.UTC); m.Before(cutoff); m = m.AddDate(0, 1, 0) {
+		res, err := a.month(ctx, m)
+		if err != nil {
+			return done, fmt.Errorf("archive %s: %w", m.Format("2006-01"), err)
+		}
+		if res.Orders > 0 {
+			done = append(done, res)
+		}
+	}
+	return done, nil
+}
+
+// Run запускает RunOnce сразу и затем раз в interval до отмены ctx.
+func (a *Archiver) Run(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		a.runPooled(ctx, pool)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (a *Archiver) runPooled(ctx context.Context, pool *pgxpool.Pool) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[archive] acquire conn: %v", err)
+		}
+		return
+	}
+	defer conn.Release()
+
+	if _, err := a.RunOnce(ctx, conn); err != nil {
+		log.Printf("[archive] run failed: %v", err)
+	}
+}
+
+func (a *Archiver) month(ctx context.Context, from time.Time) (Month, error) {
+	to := from.AddDate(0, 1, 0)
+	res := Month{Month: from}
+
+	want, err := a.src.CountOrders(ctx, from, to)
+	if err != nil || want == 0 {
+		return res, err
+	}
+
+	f, err := os.CreateTemp("", "orders-archive-*.avro")
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	enc, err := ocf.NewEncoderWithSchema(OrderSchema, f, ocf.WithCodec(ocf.Deflate))
+	if err != nil {
+		return res, err
+	}
+	// Заголовок OCF кодируется по avro-тегам, поэтому записи кодируются
+	// отдельно через avroAPI и добавляются в файл готовыми байтами.
+	written, err := a.src.ScanOrders(ctx, from, to, a.opts.BatchSize, func(o model.Order) error {
+		b, err := avroAPI.Marshal(OrderSchema, o)
+		if err != nil {
+			return err
+		}
+		_, err = enc.Write(b)
+		return err
+	})
+	if err != nil {
+		return res, err
+	}
+	if err := enc.Close(); err != nil {
+		return res, err
+	}
+	if written != want {
+		return res, fmt.Errorf("%w: counted %d, exported %d", ErrCountMismatch, want, written)
+	}
+
+	if res.Key, err = a.put(ctx, from, f); err != nil {
+		return res, err
+	}
+
+	stored, err := a.countStored(ctx, res.Key)
+	if err != nil {
+		return res, fmt.Errorf("verify %s: %w", res.Key, err)
+	}
+	if stored != written {
+		return res, fmt.Errorf("%w: exported %d, stored %d", ErrCountMismatch, written, stored)
+	}
+	res.Orders = written
+
+	if a.opts.DryRun {
+		log.Printf("[archive] %s: %d orders -> %s (dry run, rows kept)", from.Format("2006-01"), written, res.Key)
+		return res, nil
+	}
+	if err := a.src.DeleteOrders(ctx, from, to, written); err != nil {
+		return res, err
+	}
+	res.Deleted = true
+	log.Printf("[archive] %s: %d orders -> %s, rows deleted", from.Format("2006-01"), written, res.Key)
+	return res, nil
+}
+
+// put загружает файл в первую свободную часть месяца и возвращает её ключ.
+func (a *Archiver) put(ctx context.Context, month time.Time, f *os.File) (string, error) {
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return "", err
+	}
+	for part := 1; part <= maxParts; part++ {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return "", err
+		}
+		key := Key(month, part)
+		err := a.store.Put(ctx, key, f, size)
+		if errors.Is(err, objectstore.ErrExists) {
+			continue
+		}
+		return key, err
+	}
+	return "", fmt.Errorf("archive: %d parts of %s already exist", maxParts, month.Format("2006-01"))
+}
+
+// countStored перечитывает файл из хранилища и считает записи в нём.
+func (a *Archiver) countStored(ctx context.Context, key string) (int, error) {
+	rc, err := a.store.Get(ctx, key)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	dec, err := ocf.NewDecoder(rc, ocf.WithDecoderConfig(avroAPI))
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for dec.HasNext() {
+		var o model.Order
+		if err := dec.Decode(&o); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, dec.Error()
+}
@@ -0,0 +1,255 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"app/internal/model"
+	"app/internal/objectstore"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeSource хранит заказы в памяти; extra добавляет в выгрузку лишние
+// заказы, имитируя запись в архивируемый диапазон после подсчёта.
+type fakeSource struct {
+	orders  []model.Order
+	extra   int
+	deleted [][2]time.Time
+}
+
+func (f *fakeSource) in(from, to time.Time) []model.Order {
+	var out []model.Order
+	for _, o := range f.orders {
+		if !o.DateCreated.Before(from) && o.DateCreated.Before(to) {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+func (f *fakeSource) OldestOrderDate(context.Context) (time.Time, bool, error) {
+	if len(f.orders) == 0 {
+		return time.Time{}, false, nil
+	}
+	oldest := f.orders[0].DateCreated
+	for _, o := range f.orders {
+		if o.DateCreated.Before(oldest) {
+			oldest = o.DateCreated
+		}
+	}
+	return oldest, true, nil
+}
+
+func (f *fakeSource) CountOrders(_ context.Context, from, to time.Time) (int, error) {
+	return len(f.in(from, to)), nil
+}
+
+func (f *fakeSource) ScanOrders(_ context.Context, from, to time.Time, _ int, fn func(model.Order) error) (int, error) {
+	orders := f.in(from, to)
+	for i := 0; i < f.extra; i++ {
+		orders = append(orders, orders[0])
+	}
+	for i, o := range orders {
+		if err := fn(o); err != nil {
+			return i, err
+		}
+	}
+	return len(orders), nil
+}
+
+func (f *fakeSource) DeleteOrders(_ context.Context, from, to time.Time, expect int) error {
+	if got := len(f.in(from, to)); got != expect {
+		return fmt.Errorf("deleted %d, expected %d", got, expect)
+	}
+	f.deleted = append(f.deleted, [2]time.Time{from, to})
+	return nil
+}
+
+func testOrder(uid string, created time.Time) model.Order {
+	return model.Order{
+		OrderUUID:   uid,
+		TrackNumber: "track-" + uid,
+		Entry:       "WBIL",
+		DateCreated: created,
+		Delivery:    model.Delivery{Name: "n", Phone: "+7", Email: "e@x"},
+		Payment:     model.Payment{Transaction: uid, Currency: "RUB", Amount: 100, PaymentDT: 1},
+		Items:       []model.Item{{ChrtID: 1, Name: "item", Price: 100, TotalPrice: 100}},
+	}
+}
+
+func newTestArchiver(src Source, store objectstore.Store, opts Options, now time.Time) *Archiver {
+	a := New(src, store, opts)
+	a.now = func() time.Time { return now }
+	return a
+}
+
+func expectLock(mock pgxmock.PgxConnIface, got bool) {
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(lockID).
+		WillReturnRows(pgxmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(got))
+}
+
+func expectUnlock(mock pgxmock.PgxConnIface) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+}
+
+func TestKey(t *testing.T) {
+	month := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	require.Equal(t, "orders/2025/03.avro", Key(month, 1))
+	require.Equal(t, "orders/2025/03-2.avro", Key(month, 2))
+}
+
+func TestArchiver_RunOnce_NotLeader(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	expectLock(mock, false)
+
+	src := &fakeSource{orders: []model.Order{testOrder("a", time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC))}}
+	rep, err := newTestArchiver(src, objectstore.NewFS(t.TempDir()), Options{AfterMonths: 12}, time.Now()).
+		RunOnce(context.Background(), mock)
+	require.NoError(t, err)
+	require.False(t, rep.Leader)
+	require.Empty(t, src.deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiver_RunOnce_ArchivesOldMonths(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	expectLock(mock, true)
+	expectUnlock(mock)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	src := &fakeSource{orders: []model.Order{
+		testOrder("a", time.Date(2025, 8, 3, 10, 0, 0, 0, time.UTC)),
+		testOrder("b", time.Date(2025, 8, 20, 10, 0, 0, 0, time.UTC)),
+		testOrder("c", time.Date(2025, 9, 30, 23, 0, 0, 0, time.UTC)),
+		// Октябрь 2025 ещё не старше 12 месяцев.
+		testOrder("d", time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)),
+	}}
+	store := objectstore.NewFS(t.TempDir())
+
+	rep, err := newTestArchiver(src, store, Options{AfterMonths: 12}, now).RunOnce(context.Background(), mock)
+	require.NoError(t, err)
+	require.True(t, rep.Leader)
+	require.Len(t, rep.Months, 2)
+	require.Equal(t, "orders/2025/08.avro", rep.Months[0].Key)
+	require.Equal(t, 2, rep.Months[0].Orders)
+	require.Equal(t, 1, rep.Months[1].Orders)
+	require.Len(t, src.deleted, 2)
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	rc, err := store.Get(context.Background(), "orders/2025/08.avro")
+	require.NoError(t, err)
+	defer func() { _ = rc.Close() }()
+	dec, err := ocf.NewDecoder(rc, ocf.WithDecoderConfig(avroAPI))
+	require.NoError(t, err)
+
+	var got []model.Order
+	for dec.HasNext() {
+		var o model.Order
+		require.NoError(t, dec.Decode(&o))
+		got = append(got, o)
+	}
+	require.NoError(t, dec.Error())
+	require.Equal(t, src.orders[:2], got)
+}
+
+func TestArchiver_RunOnce_CountMismatchKeepsRows(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	expectLock(mock, true)
+	expectUnlock(mock)
+
+	src := &fakeSource{
+		orders: []model.Order{testOrder("a", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))},
+		extra:  1,
+	}
+	rep, err := newTestArchiver(src, objectstore.NewFS(t.TempDir()), Options{AfterMonths: 1}, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).
+		RunOnce(context.Background(), mock)
+	require.True(t, errors.Is(err, ErrCountMismatch), "got %v", err)
+	require.True(t, rep.Leader)
+	require.Empty(t, rep.Months)
+	require.Empty(t, src.deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiver_DryRunKeepsRows(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	expectLock(mock, true)
+	expectUnlock(mock)
+
+	src := &fakeSource{orders: []model.Order{testOrder("a", time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC))}}
+	store := objectstore.NewFS(t.TempDir())
+	rep, err := newTestArchiver(src, store, Options{AfterMonths: 1, DryRun: true}, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)).
+		RunOnce(context.Background(), mock)
+	require.NoError(t, err)
+	require.Len(t, rep.Months, 1)
+	require.False(t, rep.Months[0].Deleted)
+	require.Empty(t, src.deleted)
+
+	_, err = store.Get(context.Background(), "orders/2025/01.avro")
+	require.NoError(t, err)
+}
+
+func TestArchiver_ArchiveMonth(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	expectLock(mock, false)
+	expectLock(mock, true)
+	expectUnlock(mock)
+
+	jan := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := &fakeSource{orders: []model.Order{
+		testOrder("a", jan.AddDate(0, 0, 4)),
+		testOrder("b", jan.AddDate(0, 1, 4)),
+	}}
+	a := newTestArchiver(src, objectstore.NewFS(t.TempDir()), Options{AfterMonths: 12}, time.Now())
+
+	_, err = a.ArchiveMonth(context.Background(), mock, jan)
+	require.ErrorIs(t, err, ErrBusy)
+	require.Empty(t, src.deleted)
+
+	res, err := a.ArchiveMonth(context.Background(), mock, jan)
+	require.NoError(t, err)
+	require.Equal(t, 1, res.Orders)
+	require.True(t, res.Deleted)
+	require.Equal(t, [][2]time.Time{{jan, jan.AddDate(0, 1, 0)}}, src.deleted)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestArchiver_RearchiveWritesNewPart(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	for i := 0; i < 2; i++ {
+		expectLock(mock, true)
+		expectUnlock(mock)
+	}
+
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := &fakeSource{orders: []model.Order{testOrder("a", jan.AddDate(0, 0, 2))}}
+	store := objectstore.NewFS(t.TempDir())
+	a := newTestArchiver(src, store, Options{AfterMonths: 1}, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC))
+
+	first, err := a.ArchiveMonth(context.Background(), mock, jan)
+	require.NoError(t, err)
+	require.Equal(t, "orders/2025/01.avro", first.Key)
+
+	// Заказ с датой уже архивированного месяца дошёл позже.
+	src.orders = []model.Order{testOrder("late", jan.AddDate(0, 0, 20))}
+	second, err := a.ArchiveMonth(context.Background(), mock, jan)
+	require.NoError(t, err)
+	require.Equal(t, "orders/2025/01-2.avro", second.Key)
+
+	n, err := a.countStored(context.Background(), first.Key)
+	require.NoError(t, err)
+	require.Equal(t, 1, n, "first part is kept")
+	require.NoError(t, mock.ExpectationsWereMet())
+}
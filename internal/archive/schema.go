@@ -0,0 +1,72 @@
+package archive
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/hamba/avro/v2"
+)
+
+// namespace — пространство имён Avro-записей архива.
+const namespace = "wb.orders"
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// avroAPI кодирует структуры по json-тегам, чтобы имена полей в архиве
+// совпадали с API и сообщениями Kafka.
+var avroAPI = avro.Config{TagKey: "json"}.Freeze()
+
+// OrderSchema — Avro-схема model.Order. Выводится из структуры: новое поле
+// в модели автоматически попадает в архив.
+var OrderSchema = mustSchema(reflect.TypeOf(model.Order{}))
+
+func mustSchema(t reflect.Type) avro.Schema {
+	b, err := json.Marshal(schemaOf(t))
+	if err != nil {
+		panic(err)
+	}
+	s, err := avro.Parse(string(b))
+	if err != nil {
+		panic(fmt.Sprintf("archive: bad schema for %s: %v", t, err))
+	}
+	return s
+}
+
+func schemaOf(t reflect.Type) any {
+	if t == timeType {
+		return map[string]string{"type": "long", "logicalType": "timestamp-micros"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int64:
+		return "long"
+	case reflect.Int32:
+		return "int"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float64:
+		return "double"
+	case reflect.Slice:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem())}
+	case reflect.Struct:
+		fields := make([]map[string]any, 0, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" || name == "" {
+				continue
+			}
+			fields = append(fields, map[string]any{"name": name, "type": schemaOf(f.Type)})
+		}
+		return map[string]any{"type": "record", "name": t.Name(), "namespace": namespace, "fields": fields}
+	default:
+		panic(fmt.Sprintf("archive: unsupported field type %s", t))
+	}
+}
@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"app/internal/model"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyPrefix отличает ключи сервиса от прочих секретов (например, при поиске утечек).
+const KeyPrefix = "wbo_"
+
+// KeyStore — хранилище ключей (см. repository/apikey).
+type KeyStore interface {
+	Lookup(ctx context.Context, hash []byte) (model.APIKey, error)
+}
+
+// GenerateKey возвращает новый ключ и его хэш для хранения.
+func GenerateKey() (key string, hash []byte, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	key = KeyPrefix + base64.RawURLEncoding.EncodeToString(b)
+	return key, HashKey(key), nil
+}
+
+// HashKey — SHA-256 ключа. Ключи случайные и длинные, поэтому медленный
+// хэш (bcrypt и т. п.) не нужен, а поиск по хэшу остаётся индексным.
+func HashKey(key string) []byte {
+	sum := sha256.Sum256([]byte(key))
+	return sum[:]
+}
+
+// negativeTTL — сколько помнить неизвестный ключ. Новые ключи случайны и
+// в этом кэше не окажутся, так что срок нужен только против повторного
+// перебора одного и того же ключа.
+const negativeTTL = 10 * time.Second
+
+// maxCached ограничивает кэш: неизвестные ключи сверх него не запоминаются,
+// чтобы перебор случайных ключей не раздувал память.
+const maxCached = 4096
+
+// APIKeys проверяет ключи по KeyStore. Найденные ключи кэшируются на ttl,
+// поэтому отзыв ключа вступает в силу не позже чем через ttl. Неизвестные
+// ключи кэшируются на negativeTTL, чтобы не ходить за ними в базу.
+type APIKeys struct {
+	store KeyStore
+	ttl   time.Duration
+	now   func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+}
+
+type cachedKey struct {
+	id      Identity
+	found   bool
+	expires time.Time
+}
+
+func NewAPIKeys(store KeyStore, ttl time.Duration) *APIKeys {
+	return &APIKeys{store: store, ttl: ttl, now: time.Now, cache: make(map[string]cachedKey)}
+}
+
+func (a *APIKeys) Verify(ctx context.Context, key string) (Identity, error) {
+	if !strings.HasPrefix(key, KeyPrefix) {
+		return Identity{}, ErrUnauthenticated
+	}
+	hash := HashKey(key)
+	cacheKey := string(hash)
+
+	now := a.now()
+	a.mu.Lock()
+	c, ok := a.cache[cacheKey]
+	a.mu.Unlock()
+	if ok && now.Before(c.expires) {
+		if !c.found {
+			return Identity{}, ErrUnauthenticated
+		}
+		return c.id, nil
+	}
+
+	k, err := a.store.Lookup(ctx, hash)
+	if errors.Is(err, model.ErrNotFound) {
+		a.mu.Lock()
+		delete(a.cache, cacheKey)
+		a.evictExpired(now)
+		if a.ttl > 0 && len(a.cache) < maxCached {
+			a.cache[cacheKey] = cachedKey{expires: now.Add(min(a.ttl, negativeTTL))}
+		}
+		a.mu.Unlock()
+		return Identity{}, ErrUnauthenticated
+	}
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrUnavailable, err)
+	}
+
+	id := Identity{
+		Subject: "apikey:" + strconv.FormatInt(k.ID, 10),
+		Name:    k.Name,
+		Method:  MethodAPIKey,
+		Scopes:  k.Scopes,
+	}
+	if a.ttl > 0 {
+		a.mu.Lock()
+		a.cache[cacheKey] = cachedKey{id: id, found: true, expires: now.Add(a.ttl)}
+		a.evictExpired(now)
+		a.mu.Unlock()
+	}
+	return id, nil
+}
+
+// evictExpired чистит кэш, когда он разрастается; вызывается под mu.
+func (a *APIKeys) evictExpired(now time.Time) {
+	if len(a.cache) < 1024 {
+		return
+	}
+	for k, c := range a.cache {
+		if !now.Before(c.expires) {
+			delete(a.cache, k)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package auth
+
+import (
+	"app/internal/model"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKeyStore struct {
+	keys    map[string]model.APIKey
+	lookups int
+}
+
+func (f *fakeKeyStore) Lookup(_ context.Context, hash []byte) (model.APIKey, error) {
+	f.lookups++
+	k, ok := f.keys[string(hash)]
+	if !ok {
+		return model.APIKey{}, model.ErrNotFound
+	}
+	return k, nil
+}
+
+func TestAPIKeys_Verify(t *testing.T) {
+	key, hash, err := GenerateKey()
+	require.NoError(t, err)
+	store := &fakeKeyStore{keys: map[string]model.APIKey{
+		string(hash): {ID: 7, Name: "ci", Scopes: []string{"orders:read"}},
+	}}
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := NewAPIKeys(store, time.Minute)
+	a.now = func() time.Time { return now }
+
+	id, err := a.Verify(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, Identity{Subject: "apikey:7", Name: "ci", Method: MethodAPIKey, Scopes: []string{"orders:read"}}, id)
+	require.True(t, id.HasScopes("orders:read"))
+	require.False(t, id.HasScopes("orders:write"))
+
+	// Повтор в пределах ttl не ходит в хранилище, даже если ключ уже отозван.
+	delete(store.keys, string(hash))
+	_, err = a.Verify(context.Background(), key)
+	require.NoError(t, err)
+	require.Equal(t, 1, store.lookups)
+
+	now = now.Add(time.Minute)
+	_, err = a.Verify(context.Background(), key)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	require.Equal(t, 2, store.lookups)
+}
+
+func TestAPIKeys_CachesUnknownKeyBriefly(t *testing.T) {
+	key, _, err := GenerateKey()
+	require.NoError(t, err)
+	store := &fakeKeyStore{}
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	a := NewAPIKeys(store, time.Minute)
+	a.now = func() time.Time { return now }
+
+	for range 3 {
+		_, err = a.Verify(context.Background(), key)
+		require.ErrorIs(t, err, ErrUnauthenticated)
+	}
+	require.Equal(t, 1, store.lookups)
+
+	now = now.Add(negativeTTL)
+	_, err = a.Verify(context.Background(), key)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	require.Equal(t, 2, store.lookups)
+}
+
+type failingKeyStore struct{}
+
+func (failingKeyStore) Lookup(context.Context, []byte) (model.APIKey, error) {
+	return model.APIKey{}, errors.New("connection refused")
+}
+
+func TestAPIKeys_StoreFailureIsUnavailable(t *testing.T) {
+	key, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	_, err = NewAPIKeys(failingKeyStore{}, time.Minute).Verify(context.Background(), key)
+	require.ErrorIs(t, err, ErrUnavailable)
+	require.NotErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestAPIKeys_RejectsForeignFormatWithoutLookup(t *testing.T) {
+	store := &fakeKeyStore{}
+	_, err := NewAPIKeys(store, time.Minute).Verify(context.Background(), "not-a-key")
+	require.ErrorIs(t, err, ErrUnauthenticated)
+	require.Zero(t, store.lookups)
+}
+
+func testJWKS(t *testing.T, kid string, pub *ecdsa.PublicKey) []byte {
+	t.Helper()
+
+	raw, err := pub.ECDH()
+	require.NoError(t, err)
+	b := raw.Bytes() // 0x04 || X || Y
+	enc := base64.RawURLEncoding.EncodeToString
+	out, err := json.Marshal(map[string]any{"keys": []map[string]string{{
+		"kty": "EC", "crv": "P-256", "alg": "ES256", "use": "sig", "kid": kid,
+		"x": enc(b[1:33]), "y": enc(b[33:]),
+	}}})
+	require.NoError(t, err)
+	return out
+}
+
+func signToken(t *testing.T, key *ecdsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	tok.Header["kid"] = kid
+	s, err := tok.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func TestJWT_Verify(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, testJWKS(t, "k1", &key.PublicKey), 0o600))
+
+	v, err := NewJWT(context.Background(), JWTOptions{JWKSFile: path, Issuer: "https://idp", Audience: "orders"})
+	require.NoError(t, err)
+
+	valid := jwt.MapClaims{
+		"sub": "user-1", "iss": "https://idp", "aud": "orders",
+		"exp": time.Now().Add(time.Hour).Unix(), "scope": "orders:read profile",
+	}
+	id, err := v.Verify(context.Background(), signToken(t, key, "k1", valid))
+	require.NoError(t, err)
+	require.Equal(t, Identity{Subject: "user-1", Method: MethodJWT, Scopes: []string{"orders:read", "profile"}}, id)
+
+	other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	for name, token := range map[string]string{
+		"expired":      signToken(t, key, "k1", jwt.MapClaims{"sub": "u", "iss": "https://idp", "aud": "orders", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"no exp":       signToken(t, key, "k1", jwt.MapClaims{"sub": "u", "iss": "https://idp", "aud": "orders"}),
+		"wrong aud":    signToken(t, key, "k1", jwt.MapClaims{"sub": "u", "iss": "https://idp", "aud": "billing", "exp": time.Now().Add(time.Hour).Unix()}),
+		"wrong issuer": signToken(t, key, "k1", jwt.MapClaims{"sub": "u", "iss": "https://evil", "aud": "orders", "exp": time.Now().Add(time.Hour).Unix()}),
+		"foreign key":  signToken(t, other, "k1", valid),
+		"garbage":      "a.b.c",
+	} {
+		_, err := v.Verify(context.Background(), token)
+		require.True(t, errors.Is(err, ErrUnauthenticated), "%s: got %v", name, err)
+	}
+}
+
+func TestJWT_VerifyWithJWKSURL(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	jwks := testJWKS(t, "k1", &key.PublicKey)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(jwks)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	v, err := NewJWT(ctx, JWTOptions{JWKSURL: srv.URL, RefreshInterval: time.Hour})
+	require.NoError(t, err)
+
+	id, err := v.Verify(context.Background(), signToken(t, key, "k1", jwt.MapClaims{
+		"sub": "svc", "exp": time.Now().Add(time.Hour).Unix(), "scp": []string{"orders:read"},
+	}))
+	require.NoError(t, err)
+	require.Equal(t, []string{"orders:read"}, id.Scopes)
+}
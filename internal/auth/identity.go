@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+)
+
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+)
+
+var (
+	// ErrUnauthenticated — учётные данные отсутствуют, неизвестны или недействительны.
+	ErrUnauthenticated = errors.New("auth: invalid credentials")
+	// ErrForbidden — клиент опознан, но у него нет нужного scope.
+	ErrForbidden = errors.New("auth: insufficient scope")
+	// ErrUnavailable — учётные данные не удалось проверить: хранилище
+	// ключей недоступно. Это сбой сервиса, а не ошибка клиента.
+	ErrUnavailable = errors.New("auth: credential store unavailable")
+)
+
+// Identity — кто выполняет запрос.
+type Identity struct {
+	// Subject — "apikey:<id>" для API-ключа или sub из JWT.
+	Subject string
+	// Name — имя ключа; для JWT пусто.
+	Name   string
+	Method string
+	Scopes []string
+}
+
+func (i Identity) HasScopes(required ...string) bool {
+	for _, s := range required {
+		if !slices.Contains(i.Scopes, s) {
+			return false
+		}
+	}
+	return true
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// FromContext возвращает Identity запроса; ok == false, если запрос не аутентифицирован.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
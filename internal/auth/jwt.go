@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/MicahParks/keyfunc/v3"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTOptions — откуда брать ключи и какие claims требовать. Задаётся ровно
+// один из JWKSURL и JWKSFile; пустые Issuer и Audience не проверяются.
+type JWTOptions struct {
+	JWKSURL  string
+	JWKSFile string
+	// RefreshInterval — период перечитывания JWKSURL; неизвестный kid
+	// дополнительно вызывает внеочередное обновление.
+	RefreshInterval time.Duration
+	Issuer          string
+	Audience        string
+	Leeway          time.Duration
+}
+
+// signingMethods — только асимметричные алгоритмы: ключи из JWKS публичные,
+// и HS* с ними превратил бы публичный ключ в общий секрет.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// JWT проверяет bearer-токены по ключам из JWKS.
+type JWT struct {
+	keys   keyfunc.Keyfunc
+	parser *jwt.Parser
+}
+
+// NewJWT загружает JWKS. Для JWKSURL ключи обновляются в фоне, пока жив ctx.
+func NewJWT(ctx context.Context, opts JWTOptions) (*JWT, error) {
+	var (
+		keys keyfunc.Keyfunc
+		err  error
+	)
+	switch {
+	case opts.JWKSFile != "":
+		raw, rerr := os.ReadFile(opts.JWKSFile)
+		if rerr != nil {
+			return nil, fmt.Errorf("auth: read jwks: %w", rerr)
+		}
+		keys, err = keyfunc.NewJWKSetJSON(raw)
+	case opts.JWKSURL != "":
+		keys, err = keyfunc.NewDefaultOverrideCtx(ctx, []string{opts.JWKSURL}, keyfunc.Override{
+			RefreshInterval: opts.RefreshInterval,
+		})
+	default:
+		return nil, errors.New("auth: jwks url or file is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("auth: load jwks: %w", err)
+	}
+
+	popts := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		popts = append(popts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		popts = append(popts, jwt.WithAudience(opts.Audience))
+	}
+	return &JWT{keys: keys, parser: jwt.NewParser(popts...)}, nil
+}
+
+// Verify проверяет подпись и claims токена. Scopes берутся из claim scope
+// (строка через пробел, RFC 8693) или scp (массив строк).
+func (j *JWT) Verify(ctx context.Context, token string) (Identity, error) {
+	claims := jwt.MapClaims{}
+	if _, err := j.parser.ParseWithClaims(token, claims, j.keys.KeyfuncCtx(ctx)); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	sub, err := claims.GetSubject()
+	if err != nil || sub == "" {
+		return Identity{}, fmt.Errorf("%w: missing sub", ErrUnauthenticated)
+	}
+	return Identity{Subject: sub, Method: MethodJWT, Scopes: scopes(claims)}, nil
+}
+
+func scopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	list, _ := claims["scp"].([]any)
+	out := make([]string, 0, len(list))
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
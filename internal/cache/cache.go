@@ -2,10 +2,47 @@ package cache
 
 import (
 	"app/internal/model"
+	"context"
+	"time"
 )
 
-type Cache interface {
-	Get(key string) (model.Order, error)
-	Set(key string, value model.Order) error
-	Delete(key string)
+// Cache — типизированный кэш с TTL.
+//
+// Get возвращает model.ErrNotFound для отсутствующего ключа, model.ErrCacheMiss
+// для истёкшей записи и значение вместе с model.ErrStale для записи, которая
+// устарела, но ещё может быть отдана (stale-while-revalidate).
+type Cache[K comparable, V any] interface {
+	Get(key K) (V, error)
+	Set(key K, value V) error
+	// SetWithTTL сохраняет запись с TTL, отличным от TTL кэша по умолчанию.
+	SetWithTTL(key K, value V, ttl time.Duration) error
+	Delete(key K)
+	// GetOrLoad возвращает запись из кэша, а при промахе вызывает load
+	// и сохраняет результат. Конкурентные промахи по одному ключу
+	// выполняют load один раз.
+	GetOrLoad(key K, load func() (V, error)) (V, error)
+	// Generation возвращает поколение ключа; оно меняется при каждом Set и
+	// Delete. Загрузчик берёт его до чтения источника и передаёт в Fill.
+	Generation(key K) uint64
+	// Fill сохраняет загруженное значение, только если поколение ключа всё
+	// ещё gen: удаление или запись, случившиеся во время загрузки, не
+	// перезаписываются старым значением. Возвращает, сохранено ли значение.
+	Fill(key K, value V, gen uint64) bool
+	Len() int
+	// Range обходит живые записи, пока fn возвращает true.
+	Range(fn func(key K, value V) bool)
+	Clear()
+}
+
+// OrderCache — кэш заказов по ключу "order:<order_uid>".
+type OrderCache = Cache[string, model.Order]
+
+// TTLSetter — кэш, TTL которого можно поменять на лету (hot reload).
+type TTLSetter interface {
+	SetTTL(softTTL, hardTTL time.Duration)
+}
+
+// Invalidator оповещает другие реплики о том, что ключ изменился после записи.
+type Invalidator interface {
+	Invalidate(ctx context.Context, key string) error
 }
@@ -0,0 +1,248 @@
+package codec
+
+import (
+	"app/internal/model"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Компактное бинарное представление model.Order для внешних хранилищ кэша.
+// Формат: байт версии, затем поля в фиксированном порядке; строки кодируются
+// как uvarint-длина + байты, целые — как varint. Версия 2 добавила ETag
+// в конце; записи версии 1 читаются с пустым ETag.
+
+const orderVersion byte = 2
+
+var ErrCorrupted = errors.New("codec: corrupted data")
+
+func EncodeOrder(o model.Order) ([]byte, error) {
+	created, err := o.DateCreated.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("codec: encode date_created: %w", err)
+	}
+
+	w := writer{buf: make([]byte, 0, 256+len(o.Items)*96)}
+	w.byte(orderVersion)
+
+	w.string(o.OrderUUID)
+	w.string(o.TrackNumber)
+	w.string(o.Entry)
+	w.string(o.Locale)
+	w.string(o.InternalSignature)
+	w.string(o.CustomerID)
+	w.string(o.DeliveryService)
+	w.string(o.ShardKEy)
+	w.int(int64(o.SmID))
+	w.bytes(created)
+	w.string(o.OffShard)
+
+	d := o.Delivery
+	w.string(d.Name)
+	w.string(d.Phone)
+	w.string(d.Zip)
+	w.string(d.City)
+	w.string(d.Address)
+	w.string(d.Region)
+	w.string(d.Email)
+
+	p := o.Payment
+	w.string(p.Transaction)
+	w.string(p.RequestID)
+	w.string(p.Currency)
+	w.string(p.Provider)
+	w.int(int64(p.Amount))
+	w.int(int64(p.PaymentDT))
+	w.string(p.Bank)
+	w.int(int64(p.DeliveryCost))
+	w.int(int64(p.GoodsTotal))
+	w.int(int64(p.CustomFee))
+
+	w.uint(uint64(len(o.Items)))
+	for _, it := range o.Items {
+		w.int(it.ChrtID)
+		w.string(it.TrackNumber)
+		w.int(int64(it.Price))
+		w.string(it.Rid)
+		w.string(it.Name)
+		w.int(int64(it.Sale))
+		w.string(it.Size)
+		w.int(int64(it.TotalPrice))
+		w.int(int64(it.NmID))
+		w.string(it.Brand)
+		w.int(int64(it.Status))
+	}
+
+	w.string(o.ETag)
+
+	return w.buf, nil
+}
+
+func DecodeOrder(b []byte) (model.Order, error) {
+	r := reader{buf: b}
+
+	version := r.byte()
+	if r.err == nil && (version < 1 || version > orderVersion) {
+		return model.Order{}, fmt.Errorf("codec: unsupported version %d", version)
+	}
+
+	var o model.Order
+	o.OrderUUID = r.string()
+	o.TrackNumber = r.string()
+	o.Entry = r.string()
+	o.Locale = r.string()
+	o.InternalSignature = r.string()
+	o.CustomerID = r.string()
+	o.DeliveryService = r.string()
+	o.ShardKEy = r.string()
+	o.SmID = int(r.int())
+	created := r.bytes()
+	o.OffShard = r.string()
+
+	o.Delivery.Name = r.string()
+	o.Delivery.Phone = r.string()
+	o.Delivery.Zip = r.string()
+	o.Delivery.City = r.string()
+	o.Delivery.Address = r.string()
+	o.Delivery.Region = r.string()
+	o.Delivery.Email = r.string()
+
+	o.Payment.Transaction = r.string()
+	o.Payment.RequestID = r.string()
+	o.Payment.Currency = r.string()
+	o.Payment.Provider = r.string()
+	o.Payment.Amount = int(r.int())
+	o.Payment.PaymentDT = int(r.int())
+	o.Payment.Bank = r.string()
+	o.Payment.DeliveryCost = int(r.int())
+	o.Payment.GoodsTotal = int(r.int())
+	o.Payment.CustomFee = int(r.int())
+
+	n := r.uint()
+	if r.err == nil && n > uint64(len(r.buf)) {
+		return model.Order{}, ErrCorrupted
+	}
+	if n > 0 {
+		o.Items = make([]model.Item, 0, n)
+	}
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		var it model.Item
+		it.ChrtID = r.int()
+		it.TrackNumber = r.string()
+		it.Price = int(r.int())
+		it.Rid = r.string()
+		it.Name = r.string()
+		it.Sale = int(r.int())
+		it.Size = r.string()
+		it.TotalPrice = int(r.int())
+		it.NmID = int(r.int())
+		it.Brand = r.string()
+		it.Status = int(r.int())
+		o.Items = append(o.Items, it)
+	}
+
+	if version >= 2 {
+		o.ETag = r.string()
+	}
+
+	if r.err != nil {
+		return model.Order{}, r.err
+	}
+	if len(r.buf) != 0 {
+		return model.Order{}, ErrCorrupted
+	}
+
+	if err := o.DateCreated.UnmarshalBinary(created); err != nil {
+		return model.Order{}, fmt.Errorf("codec: decode date_created: %w", err)
+	}
+
+	return o, nil
+}
+
+type writer struct {
+	buf []byte
+}
+
+func (w *writer) byte(b byte) {
+	w.buf = append(w.buf, b)
+}
+
+func (w *writer) uint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *writer) int(v int64) {
+	w.buf = binary.AppendVarint(w.buf, v)
+}
+
+func (w *writer) bytes(b []byte) {
+	w.uint(uint64(len(b)))
+	w.buf = append(w.buf, b...)
+}
+
+func (w *writer) string(s string) {
+	w.uint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+type reader struct {
+	buf []byte
+	err error
+}
+
+func (r *reader) byte() byte {
+	if r.err != nil {
+		return 0
+	}
+	if len(r.buf) == 0 {
+		r.err = ErrCorrupted
+		return 0
+	}
+	b := r.buf[0]
+	r.buf = r.buf[1:]
+	return b
+}
+
+func (r *reader) uint() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(r.buf)
+	if n <= 0 {
+		r.err = ErrCorrupted
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *reader) int() int64 {
+	if r.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = ErrCorrupted
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return v
+}
+
+func (r *reader) bytes() []byte {
+	n := r.uint()
+	if r.err != nil {
+		return nil
+	}
+	if n > uint64(len(r.buf)) {
+		r.err = ErrCorrupted
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+func (r *reader) string() string {
+	return string(r.bytes())
+}
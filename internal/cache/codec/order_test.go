@@ -0,0 +1,65 @@
+package codec
+
+import (
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrder_RoundTrip(t *testing.T) {
+	want := model.Order{
+		OrderUUID:         "b563feb7b2b84b6test",
+		TrackNumber:       "WBILMTESTTRACK",
+		Entry:             "WBIL",
+		Locale:            "en",
+		InternalSignature: "",
+		CustomerID:        "test",
+		DeliveryService:   "meest",
+		ShardKEy:          "9",
+		SmID:              99,
+		DateCreated:       time.Date(2021, 11, 26, 6, 22, 19, 0, time.FixedZone("MSK", 3*3600)),
+		OffShard:          "1",
+		Delivery:          model.Delivery{Name: "Test Testov", Phone: "+9720000000", Zip: "2639809", City: "Kiryat Mozkin", Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com"},
+		Payment:           model.Payment{Transaction: "b563feb7b2b84b6test", Currency: "USD", Provider: "wbpay", Amount: 1817, PaymentDT: 1637907727, Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317, CustomFee: -1},
+		Items:             []model.Item{{ChrtID: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest", Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317, NmID: 2389212, Brand: "Vivienne Sabo", Status: 202}},
+		ETag:              "0123456789abcdef",
+	}
+
+	b, err := EncodeOrder(want)
+	require.NoError(t, err)
+
+	got, err := DecodeOrder(b)
+	require.NoError(t, err)
+	require.True(t, want.DateCreated.Equal(got.DateCreated))
+	got.DateCreated = want.DateCreated
+	require.Equal(t, want, got)
+}
+
+func TestDecodeOrder_Version1(t *testing.T) {
+	b, err := EncodeOrder(model.Order{OrderUUID: "uid-1"})
+	require.NoError(t, err)
+
+	// Версия 1 — тот же формат без ETag в конце.
+	v1 := append([]byte{1}, b[1:len(b)-1]...)
+	got, err := DecodeOrder(v1)
+	require.NoError(t, err)
+	require.Equal(t, "uid-1", got.OrderUUID)
+	require.Empty(t, got.ETag)
+}
+
+func TestDecodeOrder_Corrupted(t *testing.T) {
+	b, err := EncodeOrder(model.Order{OrderUUID: "uid-1"})
+	require.NoError(t, err)
+
+	_, err = DecodeOrder(b[:len(b)-3])
+	require.ErrorIs(t, err, ErrCorrupted)
+
+	_, err = DecodeOrder(append(b, 0))
+	require.ErrorIs(t, err, ErrCorrupted)
+
+	_, err = DecodeOrder(nil)
+	require.ErrorIs(t, err, ErrCorrupted)
+}
@@ -0,0 +1,24 @@
+package invalidation
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Event — событие инвалидации. Ключ кэша дублируется в ключе сообщения Kafka,
+// чтобы compacted-топик хранил только последнее событие по каждому ключу.
+type Event struct {
+	Key    string    `json:"k"`
+	Origin string    `json:"o"`
+	At     time.Time `json:"t"`
+}
+
+func encode(e Event) ([]byte, error) {
+	return json.Marshal(e)
+}
+
+func decode(b []byte) (Event, error) {
+	var e Event
+	err := json.Unmarshal(b, &e)
+	return e, err
+}
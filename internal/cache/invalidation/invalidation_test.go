@@ -0,0 +1,102 @@
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"app/internal/logger"
+	"app/internal/mocks"
+	"app/internal/model"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeWriter struct {
+	mu   sync.Mutex
+	msgs []kafka.Message
+	err  error
+}
+
+func (w *fakeWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.msgs = append(w.msgs, msgs...)
+	return w.err
+}
+
+type fakeReader struct {
+	msgs    []kafka.Message
+	i       int
+	drained chan struct{}
+}
+
+func (r *fakeReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if r.i < len(r.msgs) {
+		m := r.msgs[r.i]
+		r.i++
+		return m, nil
+	}
+	close(r.drained)
+	<-ctx.Done()
+	return kafka.Message{}, ctx.Err()
+}
+
+func TestPublisher_Invalidate_WritesKeyedEvent(t *testing.T) {
+	w := &fakeWriter{}
+	p := NewPublisher(w, "pod-a")
+
+	require.NoError(t, p.Invalidate(context.Background(), "order:uid-1"))
+
+	require.Len(t, w.msgs, 1)
+	require.Equal(t, []byte("order:uid-1"), w.msgs[0].Key)
+
+	e, err := decode(w.msgs[0].Value)
+	require.NoError(t, err)
+	require.Equal(t, "order:uid-1", e.Key)
+	require.Equal(t, "pod-a", e.Origin)
+}
+
+func TestPublisher_Invalidate_WriteError(t *testing.T) {
+	wantErr := errors.New("broker down")
+	p := NewPublisher(&fakeWriter{err: wantErr}, "pod-a")
+
+	require.ErrorIs(t, p.Invalidate(context.Background(), "order:uid-1"), wantErr)
+}
+
+func TestListener_Run_EvictsForeignAndSkipsOwnEvents(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	own, _ := encode(Event{Key: "order:own", Origin: "pod-a"})
+	foreign, _ := encode(Event{Key: "order:foreign", Origin: "pod-b"})
+
+	r := &fakeReader{
+		msgs: []kafka.Message{
+			{Value: own},
+			{Value: []byte("not json")},
+			{Value: foreign},
+		},
+		drained: make(chan struct{}),
+	}
+
+	var marked, markedBeforeDelete []string
+	c := mocks.NewMockCache[string, model.Order](t)
+	c.EXPECT().Delete("order:foreign").
+		Run(func(string) { markedBeforeDelete = append([]string(nil), marked...) }).
+		Return().Once()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	l := NewListener(r, c, "pod-a", OnInvalidate(func(key string) { marked = append(marked, key) }))
+
+	done := make(chan error, 1)
+	go func() { done <- l.Run(ctx) }()
+
+	<-r.drained
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+
+	// Чтение после удаления из кэша уже должно идти на primary.
+	require.Equal(t, []string{"order:foreign"}, markedBeforeDelete)
+}
@@ -0,0 +1,100 @@
+package invalidation
+
+import (
+	"app/internal/cache"
+	"app/internal/logger"
+	"context"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+const retryDelay = time.Second
+
+type Reader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+}
+
+// Listener читает события инвалидации других реплик и удаляет ключи
+// из локального кэша. Собственные события пропускаются.
+type Listener struct {
+	reader  Reader
+	cache   cache.OrderCache
+	origin  string
+	onEvent func(key string)
+}
+
+type Option func(*Listener)
+
+// OnInvalidate вызывает fn для ключа до удаления из кэша: например, чтобы
+// следующее чтение заказа шло на primary, а не на отстающую реплику БД.
+func OnInvalidate(fn func(key string)) Option {
+	return func(l *Listener) { l.onEvent = fn }
+}
+
+func NewListener(r Reader, c cache.OrderCache, origin string, opts ...Option) *Listener {
+	l := &Listener{reader: r, cache: c, origin: origin}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+func (l *Listener) Run(ctx context.Context) error {
+	logger.Info(ctx, "cache invalidation listener started", zap.String("origin", l.origin))
+
+	for {
+		msg, err := l.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info(ctx, "cache invalidation listener stopped")
+				return ctx.Err()
+			}
+			logger.Warn(ctx, "cache invalidation read failed", zap.Error(err))
+			if err := sleepCtx(ctx, retryDelay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		l.handle(ctx, msg)
+	}
+}
+
+func (l *Listener) handle(ctx context.Context, msg kafka.Message) {
+	e, err := decode(msg.Value)
+	if err != nil || e.Key == "" {
+		logger.Warn(ctx, "bad invalidation event",
+			zap.Int("partition", msg.Partition),
+			zap.Int64("offset", msg.Offset),
+			zap.Error(err),
+		)
+		return
+	}
+
+	if e.Origin == l.origin {
+		return
+	}
+
+	if l.onEvent != nil {
+		l.onEvent(e.Key)
+	}
+	l.cache.Delete(e.Key)
+	logger.Debug(ctx, "cache key invalidated",
+		zap.String("key", e.Key),
+		zap.String("origin", e.Origin),
+	)
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package invalidation
+
+import (
+	"app/internal/otelx"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const publishTimeout = 2 * time.Second
+
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+type Publisher struct {
+	writer Writer
+	origin string
+}
+
+func NewPublisher(w Writer, origin string) *Publisher {
+	return &Publisher{writer: w, origin: origin}
+}
+
+func (p *Publisher) Invalidate(ctx context.Context, key string) error {
+	b, err := encode(Event{Key: key, Origin: p.origin, At: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("marshal invalidation event: %w", err)
+	}
+
+	msg := kafka.Message{Key: []byte(key), Value: b}
+	otelx.InjectKafka(ctx, &msg)
+
+	writeCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	if err := p.writer.WriteMessages(writeCtx, msg); err != nil {
+		return fmt.Errorf("publish invalidation: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// TopicReader читает все партиции топика инвалидаций без consumer group,
+// начиная с заданного момента. Группа не нужна: смещения не коммитятся,
+// каждый старт реплики заново читает хвост compacted-топика, и на брокере
+// не копятся группы ушедших реплик.
+type TopicReader struct {
+	brokers []string
+	topic   string
+	since   time.Time
+
+	mu      sync.Mutex
+	readers []*kafka.Reader
+	started bool
+	closed  bool
+
+	msgs   chan kafka.Message
+	errs   chan error
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTopicReader не обращается к брокеру: партиции открываются при первом
+// ReadMessage, поэтому недоступная Kafka не мешает старту процесса.
+func NewTopicReader(brokers []string, topic string, since time.Time) *TopicReader {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TopicReader{
+		brokers: brokers,
+		topic:   topic,
+		since:   since,
+		msgs:    make(chan kafka.Message),
+		errs:    make(chan error),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// start открывает по читателю на партицию и ставит каждого на первое
+// сообщение не раньше since.
+func (t *TopicReader) start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.started {
+		return nil
+	}
+	if t.closed {
+		return errors.New("invalidation reader is closed")
+	}
+	if len(t.brokers) == 0 {
+		return errors.New("kafka brokers is empty")
+	}
+
+	conn, err := kafka.DialContext(ctx, "tcp", t.brokers[0])
+	if err != nil {
+		return err
+	}
+	parts, err := conn.ReadPartitions(t.topic)
+	conn.Close()
+	if err != nil {
+		return err
+	}
+
+	readers := make([]*kafka.Reader, 0, len(parts))
+	for _, p := range parts {
+		r := kafka.NewReader(kafka.ReaderConfig{Brokers: t.brokers, Topic: t.topic, Partition: p.ID})
+		readers = append(readers, r)
+		if err := r.SetOffsetAt(ctx, t.since); err != nil {
+			for _, r := range readers {
+				_ = r.Close()
+			}
+			return err
+		}
+	}
+
+	t.readers, t.started = readers, true
+	for _, r := range readers {
+		t.wg.Add(1)
+		go t.pump(r)
+	}
+	return nil
+}
+
+func (t *TopicReader) pump(r *kafka.Reader) {
+	defer t.wg.Done()
+	for {
+		m, err := r.ReadMessage(t.ctx)
+		if t.ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			select {
+			case t.errs <- err:
+			case <-t.ctx.Done():
+				return
+			}
+			continue
+		}
+		select {
+		case t.msgs <- m:
+		case <-t.ctx.Done():
+			return
+		}
+	}
+}
+
+// ReadMessage возвращает следующее сообщение любой партиции.
+func (t *TopicReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if err := t.start(ctx); err != nil {
+		return kafka.Message{}, err
+	}
+	select {
+	case m := <-t.msgs:
+		return m, nil
+	case err := <-t.errs:
+		return kafka.Message{}, err
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (t *TopicReader) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	readers := t.readers
+	t.mu.Unlock()
+
+	t.cancel()
+	var errs []error
+	for _, r := range readers {
+		errs = append(errs, r.Close())
+	}
+	t.wg.Wait()
+	return errors.Join(errs...)
+}
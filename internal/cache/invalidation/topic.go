@@ -0,0 +1,47 @@
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// EnsureTopic создаёт compacted-топик для событий инвалидации, если его ещё нет.
+func EnsureTopic(ctx context.Context, brokers []string, topic string, replicationFactor int) error {
+	if len(brokers) == 0 {
+		return errors.New("kafka brokers is empty")
+	}
+
+	conn, err := kafka.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return err
+	}
+
+	cc, err := kafka.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	err = cc.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     1,
+		ReplicationFactor: replicationFactor,
+		ConfigEntries: []kafka.ConfigEntry{
+			{ConfigName: "cleanup.policy", ConfigValue: "compact"},
+		},
+	})
+	if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return err
+	}
+	return nil
+}
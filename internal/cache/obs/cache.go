@@ -5,6 +5,8 @@ import (
 	"app/internal/logger"
 	"app/internal/model"
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -16,17 +18,22 @@ import (
 	"go.uber.org/zap"
 )
 
-type Cache struct {
-	next   cache.Cache
+type Cache[K comparable, V any] struct {
+	name   string
+	next   cache.Cache[K, V]
 	tracer trace.Tracer
+	attrs  metric.MeasurementOption
 
-	dur  metric.Float64Histogram
-	errs metric.Int64Counter
-	hits metric.Int64Counter
-	miss metric.Int64Counter
+	dur   metric.Float64Histogram
+	errs  metric.Int64Counter
+	hits  metric.Int64Counter
+	miss  metric.Int64Counter
+	stale metric.Int64Counter
 }
 
-func Wrap(next cache.Cache) cache.Cache {
+// Wrap добавляет трейсы и метрики к кэшу. name попадает в атрибут "cache"
+// всех метрик, чтобы различать несколько кэшей в одном процессе.
+func Wrap[K comparable, V any](name string, next cache.Cache[K, V]) cache.Cache[K, V] {
 	m := otel.Meter("app/cache")
 
 	dur, err := m.Float64Histogram("cache_duration_ms", metric.WithUnit("ms"))
@@ -49,69 +56,107 @@ func Wrap(next cache.Cache) cache.Cache {
 		miss, _ = noop.NewMeterProvider().Meter("noop").Int64Counter("cache_miss_total")
 	}
 
-	return &Cache{
+	stale, err := m.Int64Counter("cache_stale_total")
+	if err != nil {
+		stale, _ = noop.NewMeterProvider().Meter("noop").Int64Counter("cache_stale_total")
+	}
+
+	return &Cache[K, V]{
+		name:   name,
 		next:   next,
 		tracer: otel.Tracer("app/cache"),
+		attrs:  metric.WithAttributes(attribute.String("cache", name)),
 		dur:    dur,
 		errs:   errs,
 		hits:   hits,
 		miss:   miss,
+		stale:  stale,
 	}
 }
 
-func (c *Cache) Get(key string) (model.Order, error) {
-	ctx := context.Background()
+func (c *Cache[K, V]) start(op string, key K) (context.Context, trace.Span) {
+	return c.tracer.Start(context.Background(), "cache."+op,
+		trace.WithAttributes(
+			attribute.String("cache.name", c.name),
+			attribute.String("cache.key", fmt.Sprint(key)),
+		),
+	)
+}
+
+func (c *Cache[K, V]) opAttrs(op string) metric.MeasurementOption {
+	return metric.WithAttributes(attribute.String("cache", c.name), attribute.String("op", op))
+}
+
+func (c *Cache[K, V]) Get(key K) (V, error) {
 	start := time.Now()
 
-	ctx, span := c.tracer.Start(ctx, "cache.Get",
-		trace.WithAttributes(attribute.String("cache.key", key)),
-	)
+	ctx, span := c.start("Get", key)
 	defer span.End()
 
 	v, err := c.next.Get(key)
 
-	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()),
-		metric.WithAttributes(attribute.String("op", "Get")),
-	)
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs("Get"))
 
+	return c.observeGet(ctx, span, key, v, err)
+}
+
+func (c *Cache[K, V]) observeGet(ctx context.Context, span trace.Span, key K, v V, err error) (V, error) {
 	if err == nil {
-		c.hits.Add(ctx, 1)
+		c.hits.Add(ctx, 1, c.attrs)
 		span.SetStatus(codes.Ok, "hit")
 		return v, nil
 	}
 
-	c.miss.Add(ctx, 1)
+	if errors.Is(err, model.ErrStale) {
+		c.stale.Add(ctx, 1, c.attrs)
+		span.SetStatus(codes.Ok, "stale")
+		return v, err
+	}
+
+	c.miss.Add(ctx, 1, c.attrs)
 	span.RecordError(err)
 	span.SetStatus(codes.Error, "miss")
 
 	if err != model.ErrNotFound && err != model.ErrCacheMiss {
-		c.errs.Add(ctx, 1, metric.WithAttributes(attribute.String("op", "Get")))
-		logger.Warn(ctx, "cache get failed", zap.String("key", key), zap.Error(err))
+		c.errs.Add(ctx, 1, c.opAttrs("Get"))
+		logger.Warn(ctx, "cache get failed",
+			zap.String("cache", c.name),
+			zap.String("key", fmt.Sprint(key)),
+			zap.Error(err),
+		)
 	}
 
-	return model.Order{}, err
+	var zero V
+	return zero, err
 }
 
-func (c *Cache) Set(key string, value model.Order) error {
-	ctx := context.Background()
+func (c *Cache[K, V]) Set(key K, value V) error {
+	return c.set("Set", key, func() error { return c.next.Set(key, value) })
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	return c.set("SetWithTTL", key, func() error { return c.next.SetWithTTL(key, value, ttl) })
+}
+
+func (c *Cache[K, V]) set(op string, key K, fn func() error) error {
 	start := time.Now()
 
-	ctx, span := c.tracer.Start(ctx, "cache.Set",
-		trace.WithAttributes(attribute.String("cache.key", key)),
-	)
+	ctx, span := c.start(op, key)
 	defer span.End()
 
-	err := c.next.Set(key, value)
+	err := fn()
 
-	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()),
-		metric.WithAttributes(attribute.String("op", "Set")),
-	)
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs(op))
 
 	if err != nil {
-		c.errs.Add(ctx, 1, metric.WithAttributes(attribute.String("op", "Set")))
+		c.errs.Add(ctx, 1, c.opAttrs(op))
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "error")
-		logger.Warn(ctx, "cache set failed", zap.String("key", key), zap.Error(err))
+		logger.Warn(ctx, "cache set failed",
+			zap.String("cache", c.name),
+			zap.String("key", fmt.Sprint(key)),
+			zap.Error(err),
+		)
 		return err
 	}
 
@@ -119,20 +164,85 @@ func (c *Cache) Set(key string, value model.Order) error {
 	return nil
 }
 
-func (c *Cache) Delete(key string) {
-	ctx := context.Background()
+func (c *Cache[K, V]) Delete(key K) {
 	start := time.Now()
 
-	ctx, span := c.tracer.Start(ctx, "cache.Delete",
-		trace.WithAttributes(attribute.String("cache.key", key)),
-	)
+	ctx, span := c.start("Delete", key)
 	defer span.End()
 
 	c.next.Delete(key)
 
-	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()),
-		metric.WithAttributes(attribute.String("op", "Delete")),
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs("Delete"))
+
+	span.SetStatus(codes.Ok, "ok")
+}
+
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	start := time.Now()
+
+	ctx, span := c.start("GetOrLoad", key)
+	defer span.End()
+
+	loaded := false
+	v, err := c.next.GetOrLoad(key, func() (V, error) {
+		loaded = true
+		return load()
+	})
+
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs("GetOrLoad"))
+
+	if loaded {
+		c.miss.Add(ctx, 1, c.attrs)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "load error")
+			return v, err
+		}
+		span.SetStatus(codes.Ok, "loaded")
+		return v, nil
+	}
+
+	return c.observeGet(ctx, span, key, v, err)
+}
+
+func (c *Cache[K, V]) Generation(key K) uint64 {
+	return c.next.Generation(key)
+}
+
+func (c *Cache[K, V]) Fill(key K, value V, gen uint64) bool {
+	start := time.Now()
+
+	ctx, span := c.start("Fill", key)
+	defer span.End()
+
+	ok := c.next.Fill(key, value, gen)
+
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs("Fill"))
+
+	span.SetAttributes(attribute.Bool("cache.filled", ok))
+	span.SetStatus(codes.Ok, "ok")
+	return ok
+}
+
+func (c *Cache[K, V]) Len() int {
+	return c.next.Len()
+}
+
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.next.Range(fn)
+}
+
+func (c *Cache[K, V]) Clear() {
+	start := time.Now()
+
+	ctx, span := c.tracer.Start(context.Background(), "cache.Clear",
+		trace.WithAttributes(attribute.String("cache.name", c.name)),
 	)
+	defer span.End()
+
+	c.next.Clear()
+
+	c.dur.Record(ctx, float64(time.Since(start).Milliseconds()), c.opAttrs("Clear"))
 
 	span.SetStatus(codes.Ok, "ok")
 }
@@ -1,6 +1,7 @@
 package order
 
 import (
+	"app/internal/cache/ttl"
 	"app/internal/logger"
 	"app/internal/model"
 	"context"
@@ -8,37 +9,42 @@ import (
 	"time"
 )
 
-type entry struct {
-	val       model.Order
-	expiresAt time.Time
-}
-
+// CacheOrder — in-memory кэш заказов поверх ttl.Cache со снапшотами на диск.
 type CacheOrder struct {
-	mu    sync.Mutex
-	cache map[string]entry
-	ttl   time.Duration
+	*ttl.Cache[string, model.Order]
 
-	cancel func()
-	wg     sync.WaitGroup
+	mu             sync.Mutex
+	snapshotCancel func()
+	wg             sync.WaitGroup
 }
 
 func New(ttl time.Duration) *CacheOrder {
+	return NewWithSoftTTL(ttl, ttl)
+}
+
+// NewWithSoftTTL — см. ttl.NewWithSoftTTL.
+func NewWithSoftTTL(softTTL, hardTTL time.Duration) *CacheOrder {
 	return &CacheOrder{
-		cache: make(map[string]entry),
-		ttl:   ttl,
+		Cache: ttl.NewWithSoftTTL[string, model.Order](softTTL, hardTTL),
 	}
 }
 
+func (c *CacheOrder) StartWorker(interval time.Duration) {
+	c.Cache.StartWorker(interval)
+	logger.Info(context.Background(), "cache janitor started")
+}
+
 func (c *CacheOrder) Close() {
 	c.mu.Lock()
-	cancel := c.cancel
-	c.cancel = nil
+	snapshotCancel := c.snapshotCancel
+	c.snapshotCancel = nil
 	c.mu.Unlock()
 
-	if cancel != nil {
-		cancel()
+	if snapshotCancel != nil {
+		snapshotCancel()
 	}
 
 	c.wg.Wait()
+	c.Cache.Close()
 	logger.Info(context.Background(), "cache closed")
 }
@@ -59,3 +59,36 @@ func TestCacheOrder_Delete(t *testing.T) {
 	_, err := c.Get(key)
 	require.ErrorIs(t, err, model.ErrNotFound)
 }
+
+func TestCacheOrder_Get_SoftExpired_ReturnsStale(t *testing.T) {
+	c := NewWithSoftTTL(20*time.Millisecond, time.Minute)
+
+	key := "k1"
+	want := model.Order{OrderUUID: "uid-1"}
+
+	require.NoError(t, c.Set(key, want))
+
+	time.Sleep(35 * time.Millisecond)
+
+	got, err := c.Get(key)
+	require.ErrorIs(t, err, model.ErrStale)
+	require.Equal(t, want, got)
+
+	require.NoError(t, c.Set(key, want))
+
+	got, err = c.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestCacheOrder_Get_HardExpired_NeverServesStale(t *testing.T) {
+	c := NewWithSoftTTL(10*time.Millisecond, 20*time.Millisecond)
+
+	key := "k1"
+	require.NoError(t, c.Set(key, model.Order{OrderUUID: "uid-1"}))
+
+	time.Sleep(35 * time.Millisecond)
+
+	_, err := c.Get(key)
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+}
@@ -1,7 +0,0 @@
-package order
-
-func (c *CacheOrder) Delete(key string) {
-	c.mu.Lock()
-	delete(c.cache, key)
-	c.mu.Unlock()
-}
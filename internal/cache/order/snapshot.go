@@ -0,0 +1,288 @@
+package order
+
+import (
+	"app/internal/cache/codec"
+	"app/internal/logger"
+	"app/internal/model"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Формат снапшота:
+//
+//	magic "WBCS" | version uint16 | savedAt int64 unix ns | count uvarint |
+//	count × (key | staleIn varint ns | expiresIn varint ns | order) |
+//	crc32 (IEEE) всего предыдущего содержимого, uint32 big-endian.
+//
+// key и order кодируются как uvarint-длина + байты, order — через codec.
+
+const snapshotVersion uint16 = 1
+
+var snapshotMagic = []byte("WBCS")
+
+var (
+	ErrSnapshotCorrupted = errors.New("cache snapshot: corrupted")
+	ErrSnapshotVersion   = errors.New("cache snapshot: unsupported version")
+)
+
+// SaveSnapshot атомарно записывает живые записи кэша в файл path
+// (через временный файл в том же каталоге и rename).
+func (c *CacheOrder) SaveSnapshot(path string) (int, error) {
+	now := time.Now()
+
+	type snapEntry struct {
+		key       string
+		val       model.Order
+		staleAt   time.Time
+		expiresAt time.Time
+	}
+
+	var entries []snapEntry
+	c.RangeEntries(func(key string, value model.Order, staleAt, expiresAt time.Time) bool {
+		entries = append(entries, snapEntry{key: key, val: value, staleAt: staleAt, expiresAt: expiresAt})
+		return true
+	})
+
+	buf := bytes.NewBuffer(make([]byte, 0, 64+len(entries)*512))
+	buf.Write(snapshotMagic)
+	_ = binary.Write(buf, binary.BigEndian, snapshotVersion)
+	_ = binary.Write(buf, binary.BigEndian, now.UnixNano())
+
+	var tmp []byte
+	tmp = binary.AppendUvarint(tmp[:0], uint64(len(entries)))
+	buf.Write(tmp)
+
+	for _, e := range entries {
+		b, err := codec.EncodeOrder(e.val)
+		if err != nil {
+			return 0, fmt.Errorf("cache snapshot: encode %q: %w", e.key, err)
+		}
+
+		tmp = binary.AppendUvarint(tmp[:0], uint64(len(e.key)))
+		tmp = append(tmp, e.key...)
+		tmp = binary.AppendVarint(tmp, int64(e.staleAt.Sub(now)))
+		tmp = binary.AppendVarint(tmp, int64(e.expiresAt.Sub(now)))
+		tmp = binary.AppendUvarint(tmp, uint64(len(b)))
+		buf.Write(tmp)
+		buf.Write(b)
+	}
+
+	sum := crc32.ChecksumIEEE(buf.Bytes())
+	_ = binary.Write(buf, binary.BigEndian, sum)
+
+	if err := writeFileAtomic(path, buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// LoadSnapshot загружает записи из файла path, пересчитывая оставшийся TTL.
+// Отсутствие файла ошибкой не считается. Уже истёкшие записи пропускаются,
+// существующие в кэше ключи не перезаписываются.
+func (c *CacheOrder) LoadSnapshot(path string) (int, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) < len(snapshotMagic)+2+8+4 || !bytes.Equal(data[:len(snapshotMagic)], snapshotMagic) {
+		return 0, ErrSnapshotCorrupted
+	}
+
+	body, tail := data[:len(data)-4], data[len(data)-4:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(tail) {
+		return 0, ErrSnapshotCorrupted
+	}
+
+	body = body[len(snapshotMagic):]
+	if v := binary.BigEndian.Uint16(body); v != snapshotVersion {
+		return 0, fmt.Errorf("%w: %d", ErrSnapshotVersion, v)
+	}
+	savedAt := time.Unix(0, int64(binary.BigEndian.Uint64(body[2:])))
+	body = body[2+8:]
+
+	count, n := binary.Uvarint(body)
+	if n <= 0 {
+		return 0, ErrSnapshotCorrupted
+	}
+	body = body[n:]
+
+	now := time.Now()
+	elapsed := max(now.Sub(savedAt), 0)
+	type loadedEntry struct {
+		val       model.Order
+		staleAt   time.Time
+		expiresAt time.Time
+	}
+	loaded := make(map[string]loadedEntry, min(count, uint64(len(body))))
+
+	for i := uint64(0); i < count; i++ {
+		key, rest, ok := readBytes(body)
+		if !ok {
+			return 0, ErrSnapshotCorrupted
+		}
+		staleIn, n1 := binary.Varint(rest)
+		if n1 <= 0 {
+			return 0, ErrSnapshotCorrupted
+		}
+		expiresIn, n2 := binary.Varint(rest[n1:])
+		if n2 <= 0 {
+			return 0, ErrSnapshotCorrupted
+		}
+		raw, rest, ok := readBytes(rest[n1+n2:])
+		if !ok {
+			return 0, ErrSnapshotCorrupted
+		}
+		body = rest
+
+		expiresIn -= int64(elapsed)
+		staleIn -= int64(elapsed)
+		if expiresIn <= 0 {
+			continue
+		}
+
+		order, err := codec.DecodeOrder(raw)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %v", ErrSnapshotCorrupted, err)
+		}
+
+		loaded[string(key)] = loadedEntry{
+			val:       order,
+			staleAt:   now.Add(time.Duration(staleIn)),
+			expiresAt: now.Add(time.Duration(expiresIn)),
+		}
+	}
+
+	if len(body) != 0 {
+		return 0, ErrSnapshotCorrupted
+	}
+
+	restored := 0
+	for k, e := range loaded {
+		if c.Restore(k, e.val, e.staleAt, e.expiresAt) {
+			restored++
+		}
+	}
+
+	return restored, nil
+}
+
+// StartSnapshotter периодически сохраняет снапшот кэша в path.
+func (c *CacheOrder) StartSnapshotter(path string, interval time.Duration) {
+	c.mu.Lock()
+	if c.snapshotCancel != nil {
+		c.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.snapshotCancel = cancel
+	c.mu.Unlock()
+
+	t := time.NewTicker(interval)
+	c.wg.Add(1)
+
+	logger.Info(context.Background(), "cache snapshotter started", zap.String("path", path))
+
+	go func() {
+		defer c.wg.Done()
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				if _, err := c.SaveSnapshot(path); err != nil {
+					logger.Warn(ctx, "cache snapshot failed", zap.String("path", path), zap.Error(err))
+				}
+			}
+		}
+	}()
+}
+
+func readBytes(b []byte) ([]byte, []byte, bool) {
+	l, n := binary.Uvarint(b)
+	if n <= 0 || l > uint64(len(b)-n) {
+		return nil, nil, false
+	}
+	b = b[n:]
+	return b[:l], b[l:], true
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := f.Name()
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	committed = true
+
+	if d, err := os.Open(dir); err == nil {
+		_ = d.Sync()
+		_ = d.Close()
+	}
+	return nil
+}
+
+// SnapshotTime возвращает момент записи снапшота path; для отсутствующего
+// файла — нулевое время.
+func SnapshotTime(path string) (time.Time, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer f.Close()
+
+	head := make([]byte, len(snapshotMagic)+2+8)
+	if _, err := io.ReadFull(f, head); err != nil || !bytes.Equal(head[:len(snapshotMagic)], snapshotMagic) {
+		return time.Time{}, ErrSnapshotCorrupted
+	}
+	head = head[len(snapshotMagic):]
+	if v := binary.BigEndian.Uint16(head); v != snapshotVersion {
+		return time.Time{}, fmt.Errorf("%w: %d", ErrSnapshotVersion, v)
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(head[2:]))), nil
+}
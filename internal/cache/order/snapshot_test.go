@@ -0,0 +1,126 @@
+package order
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheOrder_Snapshot_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	src := NewWithSoftTTL(time.Minute, time.Hour)
+	want := model.Order{
+		OrderUUID:   "uid-1",
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Items:       []model.Item{{ChrtID: 1, Name: "item"}},
+	}
+	require.NoError(t, src.Set("order:uid-1", want))
+	require.NoError(t, src.Set("order:uid-2", model.Order{OrderUUID: "uid-2"}))
+
+	n, err := src.SaveSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	dst := NewWithSoftTTL(time.Minute, time.Hour)
+	n, err = dst.LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+
+	got, err := dst.Get("order:uid-1")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+
+	dst.RangeEntries(func(key string, _ model.Order, staleAt, expiresAt time.Time) bool {
+		require.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, 5*time.Second)
+		require.WithinDuration(t, time.Now().Add(time.Minute), staleAt, 5*time.Second)
+		return true
+	})
+
+	matches, err := filepath.Glob(path + ".tmp-*")
+	require.NoError(t, err)
+	require.Empty(t, matches)
+}
+
+func TestCacheOrder_Snapshot_SkipsExpired(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	src := New(30 * time.Millisecond)
+	require.NoError(t, src.Set("order:uid-1", model.Order{OrderUUID: "uid-1"}))
+
+	_, err := src.SaveSnapshot(path)
+	require.NoError(t, err)
+
+	time.Sleep(50 * time.Millisecond)
+
+	dst := New(time.Minute)
+	n, err := dst.LoadSnapshot(path)
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestCacheOrder_LoadSnapshot_MissingFile(t *testing.T) {
+	c := New(time.Minute)
+
+	n, err := c.LoadSnapshot(filepath.Join(t.TempDir(), "missing.snap"))
+	require.NoError(t, err)
+	require.Equal(t, 0, n)
+}
+
+func TestCacheOrder_SnapshotTime(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	at, err := SnapshotTime(path)
+	require.NoError(t, err)
+	require.True(t, at.IsZero())
+
+	c := NewWithSoftTTL(time.Minute, time.Hour)
+	require.NoError(t, c.Set("order:uid-1", model.Order{OrderUUID: "uid-1"}))
+	before := time.Now()
+	_, err = c.SaveSnapshot(path)
+	require.NoError(t, err)
+
+	at, err = SnapshotTime(path)
+	require.NoError(t, err)
+	require.WithinDuration(t, before, at, time.Second)
+}
+
+func TestCacheOrder_LoadSnapshot_ChecksumMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	src := New(time.Minute)
+	require.NoError(t, src.Set("order:uid-1", model.Order{OrderUUID: "uid-1"}))
+	_, err := src.SaveSnapshot(path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(data)/2] ^= 0xff
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, err = New(time.Minute).LoadSnapshot(path)
+	require.ErrorIs(t, err, ErrSnapshotCorrupted)
+}
+
+func TestCacheOrder_LoadSnapshot_UnsupportedVersion(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache.snap")
+
+	_, err := New(time.Minute).SaveSnapshot(path)
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	data[len(snapshotMagic)+1] = 99
+	binary.BigEndian.PutUint32(data[len(data)-4:], crc32.ChecksumIEEE(data[:len(data)-4]))
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	_, err = New(time.Minute).LoadSnapshot(path)
+	require.ErrorIs(t, err, ErrSnapshotVersion)
+}
@@ -0,0 +1,81 @@
+package redis
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+const scanCount = 256
+
+type Client interface {
+	Get(ctx context.Context, key string) *goredis.StringCmd
+	Set(ctx context.Context, key string, value any, expiration time.Duration) *goredis.StatusCmd
+	Del(ctx context.Context, keys ...string) *goredis.IntCmd
+	Scan(ctx context.Context, cursor uint64, match string, count int64) *goredis.ScanCmd
+	Eval(ctx context.Context, script string, keys []string, args ...any) *goredis.Cmd
+}
+
+// CacheOrder — реализация cache.OrderCache поверх Redis. Ошибки и таймауты
+// Redis не роняют запрос: Get в этом случае возвращает промах кэша.
+type CacheOrder struct {
+	client  Client
+	prefix  string
+	ttl     atomic.Int64
+	timeout time.Duration
+}
+
+func New(client Client, ttl, timeout time.Duration, prefix string) *CacheOrder {
+	c := &CacheOrder{
+		client:  client,
+		prefix:  prefix,
+		timeout: timeout,
+	}
+	c.ttl.Store(int64(ttl))
+	return c
+}
+
+// SetTTL меняет TTL для новых записей. Soft TTL в Redis не хранится: устаревание
+// записей отслеживает только in-memory уровень.
+func (c *CacheOrder) SetTTL(softTTL, hardTTL time.Duration) {
+	_ = softTTL
+	c.ttl.Store(int64(hardTTL))
+}
+
+func (c *CacheOrder) key(key string) string {
+	return c.prefix + key
+}
+
+// genPrefix — ключи поколений (см. Generation) лежат рядом с записями, но
+// Len и Range их пропускают.
+const genPrefix = "gen:"
+
+func (c *CacheOrder) genKey(key string) string {
+	return c.prefix + genPrefix + key
+}
+
+func (c *CacheOrder) ctx() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), c.timeout)
+}
+
+// scan обходит ключи с префиксом кэша. Каждая страница SCAN получает свой таймаут.
+func (c *CacheOrder) scan(fn func(keys []string) bool) error {
+	var cursor uint64
+	for {
+		ctx, cancel := c.ctx()
+		keys, next, err := c.client.Scan(ctx, cursor, c.prefix+"*", scanCount).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		if len(keys) > 0 && !fn(keys) {
+			return nil
+		}
+		if next == 0 {
+			return nil
+		}
+		cursor = next
+	}
+}
@@ -0,0 +1,147 @@
+package redis
+
+import (
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCache(t *testing.T, ttl time.Duration) (*CacheOrder, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{
+		Addr:        mr.Addr(),
+		DialTimeout: 50 * time.Millisecond,
+		ReadTimeout: 50 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return New(client, ttl, 100*time.Millisecond, "test:"), mr
+}
+
+func TestCacheOrder_SetGet_OK(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	want := model.Order{
+		OrderUUID:   "uid-1",
+		DateCreated: time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC),
+		Delivery:    model.Delivery{Name: "n", Email: "e"},
+		Items:       []model.Item{{ChrtID: 1, Name: "item"}},
+	}
+
+	require.NoError(t, c.Set("k1", want))
+	require.True(t, mr.Exists("test:k1"))
+
+	got, err := c.Get("k1")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestCacheOrder_Get_NotFound(t *testing.T) {
+	c, _ := newTestCache(t, time.Minute)
+
+	_, err := c.Get("missing")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestCacheOrder_Get_Expired(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	require.NoError(t, c.Set("k1", model.Order{OrderUUID: "uid-1"}))
+	mr.FastForward(2 * time.Minute)
+
+	_, err := c.Get("k1")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestCacheOrder_Delete(t *testing.T) {
+	c, _ := newTestCache(t, time.Minute)
+
+	require.NoError(t, c.Set("k1", model.Order{OrderUUID: "uid-1"}))
+	c.Delete("k1")
+
+	_, err := c.Get("k1")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestCacheOrder_Fill_SkipsAfterDelete(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	gen := c.Generation("k1")
+	require.True(t, c.Fill("k1", model.Order{OrderUUID: "uid-1"}, gen))
+
+	// Загрузка началась, затем ключ удалили: её результат не сохраняется.
+	gen = c.Generation("k1")
+	c.Delete("k1")
+	require.False(t, c.Fill("k1", model.Order{OrderUUID: "uid-1"}, gen))
+	require.False(t, mr.Exists("test:k1"))
+	require.Zero(t, c.Len())
+
+	require.True(t, c.Fill("k1", model.Order{OrderUUID: "uid-1"}, c.Generation("k1")))
+	require.Equal(t, 1, c.Len())
+}
+
+func TestCacheOrder_Get_Corrupted_ReturnsCacheMissAndDeletes(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	require.NoError(t, mr.Set("test:k1", "garbage"))
+
+	_, err := c.Get("k1")
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+	require.False(t, mr.Exists("test:k1"))
+}
+
+func TestCacheOrder_Unavailable_DegradesToMiss(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+	mr.Close()
+
+	start := time.Now()
+	_, err := c.Get("k1")
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+	require.Less(t, time.Since(start), time.Second)
+
+	require.Error(t, c.Set("k1", model.Order{OrderUUID: "uid-1"}))
+}
+
+func TestCacheOrder_LenRangeClear(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	require.NoError(t, c.Set("order:uid-1", model.Order{OrderUUID: "uid-1"}))
+	require.NoError(t, c.Set("order:uid-2", model.Order{OrderUUID: "uid-2"}))
+	require.NoError(t, mr.Set("other:key", "foreign"))
+
+	require.Equal(t, 2, c.Len())
+
+	got := map[string]string{}
+	c.Range(func(key string, v model.Order) bool {
+		got[key] = v.OrderUUID
+		return true
+	})
+	require.Equal(t, map[string]string{"order:uid-1": "uid-1", "order:uid-2": "uid-2"}, got)
+
+	c.Clear()
+	require.Equal(t, 0, c.Len())
+	require.True(t, mr.Exists("other:key"))
+}
+
+func TestCacheOrder_SetWithTTL(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	require.NoError(t, c.SetWithTTL("k1", model.Order{OrderUUID: "uid-1"}, 10*time.Second))
+	require.Equal(t, 10*time.Second, mr.TTL("test:k1"))
+}
+
+func TestCacheOrder_SetTTL(t *testing.T) {
+	c, mr := newTestCache(t, time.Minute)
+
+	c.SetTTL(0, 10*time.Second)
+	require.NoError(t, c.Set("k1", model.Order{OrderUUID: "uid-1"}))
+	require.Equal(t, 10*time.Second, mr.TTL("test:k1"))
+}
@@ -0,0 +1,28 @@
+package redis
+
+import "time"
+
+// deleteScript удаляет запись и увеличивает её поколение, чтобы загрузка,
+// начатая до удаления, не вернула запись через Fill.
+const deleteScript = `
+redis.call('INCR', KEYS[2])
+if tonumber(ARGV[1]) > 0 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[1])
+end
+return redis.call('DEL', KEYS[1])`
+
+func (c *CacheOrder) Delete(key string) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	_ = c.client.Eval(ctx, deleteScript, []string{c.key(key), c.genKey(key)},
+		time.Duration(c.ttl.Load()).Milliseconds()).Err()
+}
+
+func (c *CacheOrder) Clear() {
+	_ = c.scan(func(keys []string) bool {
+		ctx, cancel := c.ctx()
+		defer cancel()
+		return c.client.Del(ctx, keys...).Err() == nil
+	})
+}
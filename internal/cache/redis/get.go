@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"app/internal/cache/codec"
+	"app/internal/model"
+	"errors"
+	"fmt"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func (c *CacheOrder) Get(key string) (model.Order, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	b, err := c.client.Get(ctx, c.key(key)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return model.Order{}, model.ErrNotFound
+	}
+	if err != nil {
+		return model.Order{}, fmt.Errorf("%w: redis get: %v", model.ErrCacheMiss, err)
+	}
+
+	order, err := codec.DecodeOrder(b)
+	if err != nil {
+		c.Delete(key)
+		return model.Order{}, fmt.Errorf("%w: %v", model.ErrCacheMiss, err)
+	}
+
+	return order, nil
+}
+
+func (c *CacheOrder) GetOrLoad(key string, load func() (model.Order, error)) (model.Order, error) {
+	if v, err := c.Get(key); err == nil {
+		return v, nil
+	}
+
+	v, err := load()
+	if err != nil {
+		return model.Order{}, err
+	}
+
+	_ = c.Set(key, v)
+	return v, nil
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"app/internal/model"
+	"strings"
+)
+
+// Len возвращает число ключей кэша в Redis. Ошибка Redis даёт 0.
+func (c *CacheOrder) Len() int {
+	n := 0
+	_ = c.scan(func(keys []string) bool {
+		for _, k := range keys {
+			if !c.isGen(k) {
+				n++
+			}
+		}
+		return true
+	})
+	return n
+}
+
+func (c *CacheOrder) isGen(k string) bool {
+	return strings.HasPrefix(k, c.prefix+genPrefix)
+}
+
+func (c *CacheOrder) Range(fn func(key string, value model.Order) bool) {
+	_ = c.scan(func(keys []string) bool {
+		for _, k := range keys {
+			if c.isGen(k) {
+				continue
+			}
+			key := strings.TrimPrefix(k, c.prefix)
+			v, err := c.Get(key)
+			if err != nil {
+				continue
+			}
+			if !fn(key, v) {
+				return false
+			}
+		}
+		return true
+	})
+}
@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"app/internal/cache/codec"
+	"app/internal/model"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// setScript пишет запись и увеличивает её поколение; с ARGV[3] — только
+// если поколение всё ещё равно ему. Поколение живёт столько же, сколько
+// запись: истёкшее поколение читается как 0 и не совпадёт со старым gen.
+const setScript = `
+if ARGV[3] ~= '' and (redis.call('GET', KEYS[2]) or '0') ~= ARGV[3] then
+	return 0
+end
+redis.call('INCR', KEYS[2])
+if tonumber(ARGV[2]) > 0 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[2])
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1`
+
+func (c *CacheOrder) Set(key string, value model.Order) error {
+	return c.SetWithTTL(key, value, time.Duration(c.ttl.Load()))
+}
+
+func (c *CacheOrder) SetWithTTL(key string, value model.Order, ttl time.Duration) error {
+	b, err := codec.EncodeOrder(value)
+	if err != nil {
+		return err
+	}
+
+	if _, err := c.set(key, b, ttl, ""); err != nil {
+		return fmt.Errorf("redis set: %w", err)
+	}
+	return nil
+}
+
+// Generation читает поколение ключа; ошибка Redis даёт 0, и Fill по нему
+// сохранит запись, только если поколения действительно нет.
+func (c *CacheOrder) Generation(key string) uint64 {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	gen, err := c.client.Get(ctx, c.genKey(key)).Uint64()
+	if err != nil && !errors.Is(err, goredis.Nil) {
+		return 0
+	}
+	return gen
+}
+
+func (c *CacheOrder) Fill(key string, value model.Order, gen uint64) bool {
+	b, err := codec.EncodeOrder(value)
+	if err != nil {
+		return false
+	}
+	ok, err := c.set(key, b, time.Duration(c.ttl.Load()), strconv.FormatUint(gen, 10))
+	return err == nil && ok
+}
+
+func (c *CacheOrder) set(key string, b []byte, ttl time.Duration, gen string) (bool, error) {
+	ctx, cancel := c.ctx()
+	defer cancel()
+
+	n, err := c.client.Eval(ctx, setScript, []string{c.key(key), c.genKey(key)},
+		b, ttl.Milliseconds(), gen).Int()
+	return n == 1, err
+}
@@ -0,0 +1,91 @@
+package tiered
+
+import (
+	"app/internal/cache"
+	"app/internal/model"
+	"errors"
+	"time"
+)
+
+// Cache — двухуровневый кэш: локальный L1 перед общим L2.
+// Промах в L1 добирается из L2 с заполнением L1.
+type Cache[K comparable, V any] struct {
+	l1 cache.Cache[K, V]
+	l2 cache.Cache[K, V]
+}
+
+func New[K comparable, V any](l1, l2 cache.Cache[K, V]) *Cache[K, V] {
+	return &Cache[K, V]{l1: l1, l2: l2}
+}
+
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	if v, err := c.l1.Get(key); err == nil || errors.Is(err, model.ErrStale) {
+		return v, err
+	}
+
+	v, err := c.l2.Get(key)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	_ = c.l1.Set(key, v)
+	return v, nil
+}
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	_ = c.l1.Set(key, value)
+	return c.l2.Set(key, value)
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	_ = c.l1.SetWithTTL(key, value, ttl)
+	return c.l2.SetWithTTL(key, value, ttl)
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.l1.Delete(key)
+	c.l2.Delete(key)
+}
+
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	return c.l1.GetOrLoad(key, func() (V, error) {
+		if v, err := c.l2.Get(key); err == nil {
+			return v, nil
+		}
+		v, err := load()
+		if err != nil {
+			return v, err
+		}
+		_ = c.l2.Set(key, v)
+		return v, nil
+	})
+}
+
+// Generation — поколение ключа в L2: он общий для реплик, и через него
+// проходят все записи и удаления.
+func (c *Cache[K, V]) Generation(key K) uint64 {
+	return c.l2.Generation(key)
+}
+
+func (c *Cache[K, V]) Fill(key K, value V, gen uint64) bool {
+	if !c.l2.Fill(key, value, gen) {
+		return false
+	}
+	_ = c.l1.Set(key, value)
+	return true
+}
+
+// Len и Range работают по L2: он общий для всех реплик и полнее L1.
+func (c *Cache[K, V]) Len() int {
+	return c.l2.Len()
+}
+
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.l2.Range(fn)
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.l1.Clear()
+	c.l2.Clear()
+}
@@ -0,0 +1,70 @@
+package tiered
+
+import (
+	"errors"
+	"testing"
+
+	"app/internal/mocks"
+	"app/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_Get_L1Hit(t *testing.T) {
+	l1, l2 := mocks.NewMockCache[string, model.Order](t), mocks.NewMockCache[string, model.Order](t)
+	c := New(l1, l2)
+
+	want := model.Order{OrderUUID: "uid-1"}
+	l1.On("Get", "k1").Return(want, nil).Once()
+
+	got, err := c.Get("k1")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestCache_Get_L1Miss_L2Hit_FillsL1(t *testing.T) {
+	l1, l2 := mocks.NewMockCache[string, model.Order](t), mocks.NewMockCache[string, model.Order](t)
+	c := New(l1, l2)
+
+	want := model.Order{OrderUUID: "uid-1"}
+	l1.On("Get", "k1").Return(model.Order{}, model.ErrNotFound).Once()
+	l2.On("Get", "k1").Return(want, nil).Once()
+	l1.On("Set", "k1", want).Return(nil).Once()
+
+	got, err := c.Get("k1")
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
+
+func TestCache_Get_BothMiss(t *testing.T) {
+	l1, l2 := mocks.NewMockCache[string, model.Order](t), mocks.NewMockCache[string, model.Order](t)
+	c := New(l1, l2)
+
+	l1.On("Get", "k1").Return(model.Order{}, model.ErrNotFound).Once()
+	l2.On("Get", "k1").Return(model.Order{}, model.ErrCacheMiss).Once()
+
+	_, err := c.Get("k1")
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+}
+
+func TestCache_Set_WritesBothLevels(t *testing.T) {
+	l1, l2 := mocks.NewMockCache[string, model.Order](t), mocks.NewMockCache[string, model.Order](t)
+	c := New(l1, l2)
+
+	v := model.Order{OrderUUID: "uid-1"}
+	errL2 := errors.New("l2 down")
+	l1.On("Set", "k1", v).Return(nil).Once()
+	l2.On("Set", "k1", v).Return(errL2).Once()
+
+	require.ErrorIs(t, c.Set("k1", v), errL2)
+}
+
+func TestCache_Delete_BothLevels(t *testing.T) {
+	l1, l2 := mocks.NewMockCache[string, model.Order](t), mocks.NewMockCache[string, model.Order](t)
+	c := New(l1, l2)
+
+	l1.On("Delete", "k1").Return().Once()
+	l2.On("Delete", "k1").Return().Once()
+
+	c.Delete("k1")
+}
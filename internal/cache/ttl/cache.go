@@ -0,0 +1,119 @@
+package ttl
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type entry[V any] struct {
+	val       V
+	staleAt   time.Time
+	expiresAt time.Time
+
+	// gen — поколение ключа (см. Generation). deleted — надгробие после
+	// Delete: хранит поколение, пока не истечёт, и не отдаётся читателям.
+	gen     uint64
+	deleted bool
+}
+
+type call[V any] struct {
+	wg  sync.WaitGroup
+	val V
+	err error
+}
+
+// Cache — потокобезопасный in-memory кэш с soft/hard TTL и фоновой очисткой.
+type Cache[K comparable, V any] struct {
+	mu      sync.Mutex
+	cache   map[K]entry[V]
+	loads   map[K]*call[V]
+	ttl     time.Duration
+	softTTL time.Duration
+	seq     uint64
+
+	cancel func()
+	wg     sync.WaitGroup
+}
+
+func New[K comparable, V any](ttl time.Duration) *Cache[K, V] {
+	return NewWithSoftTTL[K, V](ttl, ttl)
+}
+
+// NewWithSoftTTL создаёт кэш, в котором запись считается свежей softTTL,
+// после чего до истечения hardTTL отдаётся как устаревшая (model.ErrStale).
+// Если softTTL <= 0 или softTTL >= hardTTL, режим stale-while-revalidate выключен.
+func NewWithSoftTTL[K comparable, V any](softTTL, hardTTL time.Duration) *Cache[K, V] {
+	c := &Cache[K, V]{
+		cache: make(map[K]entry[V]),
+		loads: make(map[K]*call[V]),
+	}
+	c.SetTTL(softTTL, hardTTL)
+	return c
+}
+
+// SetTTL меняет TTL для новых записей; уже сохранённые записи доживают
+// со старыми сроками.
+func (c *Cache[K, V]) SetTTL(softTTL, hardTTL time.Duration) {
+	if softTTL <= 0 || softTTL > hardTTL {
+		softTTL = hardTTL
+	}
+
+	c.mu.Lock()
+	c.ttl = hardTTL
+	c.softTTL = softTTL
+	c.mu.Unlock()
+}
+
+func (c *Cache[K, V]) StartWorker(interval time.Duration) {
+	c.mu.Lock()
+	if c.cancel != nil {
+		c.mu.Unlock()
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.mu.Unlock()
+
+	t := time.NewTicker(interval)
+	c.wg.Add(1)
+
+	go func() {
+		defer c.wg.Done()
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				c.cleanupExpired()
+			}
+		}
+	}()
+}
+
+func (c *Cache[K, V]) Close() {
+	c.mu.Lock()
+	cancel := c.cancel
+	c.cancel = nil
+	c.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+
+	c.wg.Wait()
+}
+
+func (c *Cache[K, V]) cleanupExpired() {
+	now := time.Now()
+
+	c.mu.Lock()
+	for k, v := range c.cache {
+		if now.After(v.expiresAt) {
+			delete(c.cache, k)
+		}
+	}
+	c.mu.Unlock()
+}
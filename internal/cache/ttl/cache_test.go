@@ -0,0 +1,213 @@
+package ttl
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCache_SetWithTTL_OverridesDefault(t *testing.T) {
+	c := New[string, int](time.Minute)
+
+	require.NoError(t, c.SetWithTTL("short", 1, 20*time.Millisecond))
+	require.NoError(t, c.Set("long", 2))
+
+	time.Sleep(35 * time.Millisecond)
+
+	_, err := c.Get("short")
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+
+	v, err := c.Get("long")
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+}
+
+func TestCache_GetOrLoad_LoadsOnceForConcurrentMisses(t *testing.T) {
+	c := New[int, string](time.Minute)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			v, err := c.GetOrLoad(1, func() (string, error) {
+				calls.Add(1)
+				<-release
+				return "loaded", nil
+			})
+			require.NoError(t, err)
+			require.Equal(t, "loaded", v)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load())
+
+	v, err := c.Get(1)
+	require.NoError(t, err)
+	require.Equal(t, "loaded", v)
+}
+
+func TestCache_GetOrLoad_ErrorNotCached(t *testing.T) {
+	c := New[string, int](time.Minute)
+	wantErr := errors.New("load failed")
+
+	_, err := c.GetOrLoad("k", func() (int, error) { return 0, wantErr })
+	require.ErrorIs(t, err, wantErr)
+
+	_, err = c.Get("k")
+	require.ErrorIs(t, err, model.ErrNotFound)
+}
+
+func TestCache_GetOrLoad_PanicReleasesWaiters(t *testing.T) {
+	c := New[string, int](time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, err := c.GetOrLoad("k", func() (int, error) {
+			close(started)
+			<-release
+			panic("boom")
+		})
+		require.ErrorContains(t, err, "boom")
+	}()
+
+	<-started
+	waiterErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetOrLoad("k", func() (int, error) { return 1, nil })
+		waiterErr <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	select {
+	case err := <-waiterErr:
+		require.ErrorContains(t, err, "boom")
+	case <-time.After(time.Second):
+		t.Fatal("waiter is still blocked after load panic")
+	}
+
+	v, err := c.GetOrLoad("k", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+}
+
+func TestCache_GetOrLoad_DeleteDuringLoadIsNotOverwritten(t *testing.T) {
+	c := New[string, int](time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	done := make(chan int, 1)
+	go func() {
+		v, _ := c.GetOrLoad("k", func() (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		done <- v
+	}()
+
+	<-started
+	c.Delete("k")
+	close(release)
+	require.Equal(t, 1, <-done)
+
+	_, err := c.Get("k")
+	require.ErrorIs(t, err, model.ErrNotFound)
+	require.Zero(t, c.Len())
+
+	// Новая загрузка после удаления заполняет кэш.
+	v, err := c.GetOrLoad("k", func() (int, error) { return 2, nil })
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+	v, err = c.Get("k")
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+}
+
+func TestCache_Fill_SkipsNewerSet(t *testing.T) {
+	c := New[string, int](time.Minute)
+
+	gen := c.Generation("k")
+	require.NoError(t, c.Set("k", 2))
+	require.False(t, c.Fill("k", 1, gen))
+
+	v, err := c.Get("k")
+	require.NoError(t, err)
+	require.Equal(t, 2, v)
+	require.True(t, c.Fill("k", 3, c.Generation("k")))
+}
+
+func TestCache_GetOrLoad_StaleDoesNotLoad(t *testing.T) {
+	c := NewWithSoftTTL[string, int](10*time.Millisecond, time.Minute)
+	require.NoError(t, c.Set("k", 1))
+
+	time.Sleep(20 * time.Millisecond)
+
+	v, err := c.GetOrLoad("k", func() (int, error) {
+		t.Fatal("load must not be called for stale entry")
+		return 0, nil
+	})
+	require.ErrorIs(t, err, model.ErrStale)
+	require.Equal(t, 1, v)
+}
+
+func TestCache_LenRangeClear(t *testing.T) {
+	c := New[string, int](time.Minute)
+
+	require.NoError(t, c.Set("a", 1))
+	require.NoError(t, c.Set("b", 2))
+	require.NoError(t, c.SetWithTTL("expired", 3, time.Nanosecond))
+	time.Sleep(time.Millisecond)
+
+	require.Equal(t, 2, c.Len())
+
+	got := map[string]int{}
+	c.Range(func(k string, v int) bool {
+		got[k] = v
+		return true
+	})
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, got)
+
+	c.Clear()
+	require.Equal(t, 0, c.Len())
+}
+
+func TestCache_SetTTL_AppliesToNewEntries(t *testing.T) {
+	c := New[string, int](time.Minute)
+	require.NoError(t, c.Set("old", 1))
+
+	c.SetTTL(10*time.Millisecond, 30*time.Millisecond)
+	require.NoError(t, c.Set("new", 2))
+
+	time.Sleep(15 * time.Millisecond)
+	v, err := c.Get("new")
+	require.ErrorIs(t, err, model.ErrStale)
+	require.Equal(t, 2, v)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err = c.Get("new")
+	require.ErrorIs(t, err, model.ErrCacheMiss)
+
+	v, err = c.Get("old")
+	require.NoError(t, err)
+	require.Equal(t, 1, v)
+}
@@ -0,0 +1,82 @@
+package ttl
+
+import (
+	"fmt"
+	"time"
+
+	"app/internal/model"
+)
+
+func (c *Cache[K, V]) Get(key K) (V, error) {
+	var zero V
+	now := time.Now()
+
+	c.mu.Lock()
+	e, ok := c.cache[key]
+	c.mu.Unlock()
+
+	if !ok || e.deleted {
+		return zero, model.ErrNotFound
+	}
+
+	if now.After(e.expiresAt) {
+		c.mu.Lock()
+
+		if e2, ok2 := c.cache[key]; ok2 && now.After(e2.expiresAt) {
+			delete(c.cache, key)
+		}
+		c.mu.Unlock()
+		return zero, model.ErrCacheMiss
+	}
+
+	if now.After(e.staleAt) {
+		return e.val, model.ErrStale
+	}
+
+	return e.val, nil
+}
+
+// GetOrLoad отдаёт устаревшую запись вместе с model.ErrStale, не вызывая load:
+// решение о фоновом обновлении остаётся за вызывающим.
+func (c *Cache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	if v, err := c.Get(key); err == nil || err == model.ErrStale {
+		return v, err
+	}
+
+	c.mu.Lock()
+	if cl, ok := c.loads[key]; ok {
+		c.mu.Unlock()
+		cl.wg.Wait()
+		return cl.val, cl.err
+	}
+	cl := &call[V]{}
+	cl.wg.Add(1)
+	c.loads[key] = cl
+	gen := c.generation(key)
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.loads, key)
+		c.mu.Unlock()
+		cl.wg.Done()
+	}()
+
+	cl.val, cl.err = c.load(load)
+	if cl.err == nil {
+		c.Fill(key, cl.val, gen)
+	}
+
+	return cl.val, cl.err
+}
+
+// load вызывает загрузчик, превращая его панику в ошибку: иначе ожидающие
+// того же ключа получили бы нулевое значение без ошибки.
+func (c *Cache[K, V]) load(load func() (V, error)) (v V, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("cache load panic: %v", r)
+		}
+	}()
+	return load()
+}
@@ -0,0 +1,65 @@
+package ttl
+
+import "time"
+
+func (c *Cache[K, V]) Len() int {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+	for _, e := range c.cache {
+		if !e.deleted && !now.After(e.expiresAt) {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *Cache[K, V]) Range(fn func(key K, value V) bool) {
+	c.RangeEntries(func(key K, value V, _, _ time.Time) bool {
+		return fn(key, value)
+	})
+}
+
+// RangeEntries обходит живые записи вместе с их сроками. fn вызывается
+// вне блокировки, поэтому может обращаться к кэшу.
+func (c *Cache[K, V]) RangeEntries(fn func(key K, value V, staleAt, expiresAt time.Time) bool) {
+	now := time.Now()
+
+	type kv struct {
+		key K
+		e   entry[V]
+	}
+
+	c.mu.Lock()
+	live := make([]kv, 0, len(c.cache))
+	for k, e := range c.cache {
+		if !e.deleted && !now.After(e.expiresAt) {
+			live = append(live, kv{key: k, e: e})
+		}
+	}
+	c.mu.Unlock()
+
+	for _, it := range live {
+		if !fn(it.key, it.e.val, it.e.staleAt, it.e.expiresAt) {
+			return
+		}
+	}
+}
+
+// Restore кладёт запись с заданными сроками, если ключа ещё нет в кэше.
+// Надгробие тоже считается: ключ, удалённый во время восстановления
+// снапшота, не возвращается.
+func (c *Cache[K, V]) Restore(key K, value V, staleAt, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.cache[key]; ok {
+		return false
+	}
+	c.seq++
+	c.cache[key] = entry[V]{val: value, staleAt: staleAt, expiresAt: expiresAt, gen: c.seq}
+	return true
+}
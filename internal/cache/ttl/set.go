@@ -0,0 +1,74 @@
+package ttl
+
+import "time"
+
+func (c *Cache[K, V]) Set(key K, value V) error {
+	c.mu.Lock()
+	ttl := c.ttl
+	c.mu.Unlock()
+
+	return c.SetWithTTL(key, value, ttl)
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.put(key, value, ttl, now)
+	return nil
+}
+
+// put вызывается под mu.
+func (c *Cache[K, V]) put(key K, value V, ttl time.Duration, now time.Time) {
+	c.seq++
+	c.cache[key] = entry[V]{
+		val:       value,
+		staleAt:   now.Add(min(c.softTTL, ttl)),
+		expiresAt: now.Add(ttl),
+		gen:       c.seq,
+	}
+}
+
+// Delete оставляет надгробие на TTL кэша: загрузка, начатая до удаления,
+// не вернёт ключ через Fill.
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	c.seq++
+	c.cache[key] = entry[V]{expiresAt: time.Now().Add(c.ttl), gen: c.seq, deleted: true}
+	c.mu.Unlock()
+}
+
+// Generation возвращает поколение ключа; оно меняется при каждом Set и Delete.
+func (c *Cache[K, V]) Generation(key K) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation(key)
+}
+
+// generation вызывается под mu.
+func (c *Cache[K, V]) generation(key K) uint64 {
+	return c.cache[key].gen
+}
+
+// Fill сохраняет загруженное значение, только если поколение ключа всё ещё
+// gen: Set или Delete во время загрузки не перезаписываются старым значением.
+func (c *Cache[K, V]) Fill(key K, value V, gen uint64) bool {
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation(key) != gen {
+		return false
+	}
+	c.put(key, value, c.ttl, now)
+	return true
+}
+
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	c.cache = make(map[K]entry[V])
+	c.mu.Unlock()
+}
@@ -22,6 +22,7 @@ type Closer struct {
 	mu     sync.Mutex
 	once   sync.Once
 	done   chan struct{}
+	first  []func(ctx context.Context) error
 	funcs  []func(ctx context.Context) error
 	logger Logger
 }
@@ -36,6 +37,10 @@ func Add(f func(ctx context.Context) error) {
 	globalCloser.Add(f)
 }
 
+func AddFirstNamed(name string, f func(ctx context.Context) error) {
+	globalCloser.AddFirstNamed(name, f)
+}
+
 func SetLogger(logger Logger) {
 	globalCloser.SetLogger(logger)
 }
@@ -93,10 +98,16 @@ func (c *Closer) CloseAll(ctx context.Context) error {
 		defer close(c.done)
 
 		c.mu.Lock()
-		funcs := c.funcs
-		c.funcs = nil
+		first, funcs := c.first, c.funcs
+		c.first, c.funcs = nil, nil
 		c.mu.Unlock()
 
+		for _, f := range first {
+			if err := f(ctx); err != nil && result == nil {
+				result = err
+			}
+		}
+
 		if len(funcs) == 0 {
 			c.logger.Info(ctx, "Нет функций для закрытия ")
 			return
@@ -152,7 +163,19 @@ func (c *Closer) CloseAll(ctx context.Context) error {
 }
 
 func (c *Closer) AddNamed(name string, f func(ctx context.Context) error) {
-	c.Add(func(ctx context.Context) error {
+	c.Add(c.named(name, f))
+}
+
+// AddFirstNamed регистрирует функцию, которая выполняется до всех остальных,
+// последовательно и в порядке регистрации (например, перевод в not-ready).
+func (c *Closer) AddFirstNamed(name string, f func(ctx context.Context) error) {
+	c.mu.Lock()
+	c.first = append(c.first, c.named(name, f))
+	c.mu.Unlock()
+}
+
+func (c *Closer) named(name string, f func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
 		start := time.Now()
 		c.logger.Info(ctx, "Закрываем"+name)
 		err := f(ctx)
@@ -163,7 +186,7 @@ func (c *Closer) AddNamed(name string, f func(ctx context.Context) error) {
 		}
 		c.logger.Info(ctx, fmt.Sprintf("✅ %s закрыт (заняло %s)", name, d))
 		return nil
-	})
+	}
 }
 
 func (c *Closer) Add(f func(ctx context.Context) error) {
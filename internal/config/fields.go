@@ -0,0 +1,143 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Field — одна настройка (лист структуры Config).
+type Field struct {
+	Key    string
+	Env    string
+	Desc   string
+	Secret string
+	Type   string
+	// Reload — настройку можно поменять без рестарта (см. Reload).
+	Reload bool
+
+	value reflect.Value
+}
+
+// fields обходит c и возвращает листья в порядке объявления полей.
+func fields(c *Config) []Field {
+	var out []Field
+	walk(reflect.ValueOf(c).Elem(), "", &out)
+	return out
+}
+
+func walk(v reflect.Value, prefix string, out *[]Field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		key := sf.Tag.Get("key")
+		if key == "" {
+			continue
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Struct {
+			walk(fv, key, out)
+			continue
+		}
+
+		*out = append(*out, Field{
+			Key:    key,
+			Env:    sf.Tag.Get("env"),
+			Desc:   sf.Tag.Get("desc"),
+			Secret: sf.Tag.Get("secret"),
+			Type:   typeName(fv),
+			Reload: sf.Tag.Get("reload") == "true",
+			value:  fv,
+		})
+	}
+}
+
+func typeName(v reflect.Value) string {
+	if v.Type() == durationType {
+		return "duration"
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		return "list"
+	case reflect.Int:
+		return "int"
+	case reflect.Bool:
+		return "bool"
+	default:
+		return "string"
+	}
+}
+
+// set разбирает строковое значение из файла, env или флага. Ошибки разбора
+// не глотаются: неверное значение не должно молча превращаться в дефолт.
+func (f Field) set(s string) error {
+	s = strings.TrimSpace(s)
+
+	switch f.Type {
+	case "duration":
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", s)
+		}
+		f.value.SetInt(int64(d))
+	case "int":
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid int %q", s)
+		}
+		f.value.SetInt(int64(n))
+	case "bool":
+		b, err := parseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value.SetBool(b)
+	case "list":
+		f.value.Set(reflect.ValueOf(splitAndTrim(s)))
+	default:
+		f.value.SetString(s)
+	}
+	return nil
+}
+
+// String возвращает значение в том же виде, в каком его принимает set.
+func (f Field) String() string {
+	switch f.Type {
+	case "duration":
+		return time.Duration(f.value.Int()).String()
+	case "list":
+		return strings.Join(f.value.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(f.value.Interface())
+	}
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "true", "1", "yes", "y", "on":
+		return true, nil
+	case "false", "0", "no", "n", "off":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid bool %q", s)
+}
+
+func splitAndTrim(val string) []string {
+	parts := strings.Split(val, ",")
+	out := make([]string, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
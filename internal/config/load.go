@@ -0,0 +1,133 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// ValidationError перечисляет все проблемы конфига сразу, а не первую.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid config:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Load собирает конфиг: значения по умолчанию, затем файл, env и флаги.
+func Load(s Sources) (Config, error) {
+	c := Defaults()
+
+	byKey := make(map[string]Field)
+	fs := fields(&c)
+	for _, f := range fs {
+		byKey[f.Key] = f
+	}
+
+	var problems []string
+	apply := func(source string, vals map[string]string) {
+		keys := make([]string, 0, len(vals))
+		for k := range vals {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			f, ok := byKey[k]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown key (%s)", k, source))
+				continue
+			}
+			if err := f.set(vals[k]); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v (%s)", k, err, source))
+			}
+		}
+	}
+
+	path := s.File
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		vals, err := readFile(path)
+		if err != nil {
+			return c, err
+		}
+		apply("file "+path, vals)
+	}
+
+	for _, f := range fs {
+		v, ok := os.LookupEnv(f.Env)
+		if !ok || v == "" {
+			continue
+		}
+		if err := f.set(v); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v (env %s)", f.Key, err, f.Env))
+		}
+	}
+
+	apply("flag --set", s.Overrides)
+
+	problems = append(problems, c.Validate()...)
+	for _, v := range s.Validators {
+		problems = append(problems, v(&c)...)
+	}
+	if len(problems) > 0 {
+		return c, &ValidationError{Problems: problems}
+	}
+	return c, nil
+}
+
+func readFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	raw := make(map[string]any)
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &raw)
+	case ".toml":
+		_, err = toml.Decode(string(b), &raw)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported extension %q (want .yaml, .yml or .toml)", path, ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config file %s: %w", path, err)
+	}
+
+	out := make(map[string]string)
+	flatten("", raw, out)
+	return out, nil
+}
+
+func flatten(prefix string, m map[string]any, out map[string]string) {
+	for k, v := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		switch val := v.(type) {
+		case map[string]any:
+			flatten(key, val, out)
+		case []any:
+			items := make([]string, len(val))
+			for i, it := range val {
+				items[i] = fmt.Sprint(it)
+			}
+			out[key] = strings.Join(items, ",")
+		case nil:
+			out[key] = ""
+		default:
+			out[key] = fmt.Sprint(val)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr), "want ValidationError, got %v", err)
+	return verr.Problems
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	c, err := Load(Sources{})
+	require.NoError(t, err)
+	require.Equal(t, RoleAll, c.Role)
+	require.Equal(t, 5*time.Minute, c.Cache.TTL)
+	require.Equal(t, []string{"localhost:9092"}, c.Kafka.Brokers)
+}
+
+func TestLoad_YAMLFile(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+role: api
+postgres:
+  dsn: postgres://u:p@db:5432/wb
+  max_conns: 20
+kafka:
+  brokers: [k1:9092, k2:9092]
+  max_retries: 3
+cache:
+  ttl: 10m
+  soft_ttl: 1m
+`)
+
+	c, err := Load(Sources{File: path})
+	require.NoError(t, err)
+	require.Equal(t, RoleAPI, c.Role)
+	require.Equal(t, "postgres://u:p@db:5432/wb", c.Postgres.DSN)
+	require.Equal(t, 20, c.Postgres.MaxConns)
+	require.Equal(t, []string{"k1:9092", "k2:9092"}, c.Kafka.Brokers)
+	require.Equal(t, 3, c.Kafka.MaxRetries)
+	require.Equal(t, 10*time.Minute, c.Cache.TTL)
+	require.Equal(t, time.Minute, c.Cache.SoftTTL)
+}
+
+func TestLoad_TOMLFile(t *testing.T) {
+	path := writeFile(t, "config.toml", `
+role = "ingester"
+
+[kafka]
+brokers = ["k1:9092"]
+retry_base_backoff = "100ms"
+
+[redis]
+password = "secret"
+`)
+
+	c, err := Load(Sources{File: path})
+	require.NoError(t, err)
+	require.Equal(t, RoleIngester, c.Role)
+	require.Equal(t, []string{"k1:9092"}, c.Kafka.Brokers)
+	require.Equal(t, 100*time.Millisecond, c.Kafka.RetryBaseBackoff)
+	require.Equal(t, "secret", c.Redis.Password)
+}
+
+func TestLoad_Precedence_FileEnvFlag(t *testing.T) {
+	path := writeFile(t, "config.yaml", "kafka:\n  topic: from-file\n  group_id: from-file\n  dlq_topic: from-file\n")
+	t.Setenv("KAFKA_GROUP_ID", "from-env")
+	t.Setenv("KAFKA_DLQ_TOPIC", "from-env")
+
+	c, err := Load(Sources{File: path, Overrides: map[string]string{"kafka.dlq_topic": "from-flag"}})
+	require.NoError(t, err)
+	require.Equal(t, "from-file", c.Kafka.Topic)
+	require.Equal(t, "from-env", c.Kafka.GroupID)
+	require.Equal(t, "from-flag", c.Kafka.DLQTopic)
+}
+
+func TestLoad_ConfigFileFromEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", writeFile(t, "c.yml", "env: prod\n"))
+
+	c, err := Load(Sources{})
+	require.NoError(t, err)
+	require.Equal(t, "prod", c.Env)
+}
+
+func TestLoad_BadValuesAreNotSilentlyDefaulted(t *testing.T) {
+	t.Setenv("CACHE_TTL", "five minutes")
+
+	_, err := Load(Sources{})
+	require.Equal(t, []string{`cache.ttl: invalid duration "five minutes" (env CACHE_TTL)`}, problems(t, err))
+}
+
+func TestLoad_ListsAllProblems(t *testing.T) {
+	path := writeFile(t, "config.yaml", "postgres:\n  max_conns: 0\n  dsnn: typo\n")
+	t.Setenv("REDIS_DB", "x")
+	t.Setenv("APP_ROLE", "worker")
+
+	_, err := Load(Sources{
+		File:      path,
+		Overrides: map[string]string{"cache.soft_ttl": "10m", "nope": "1"},
+	})
+
+	require.ElementsMatch(t, []string{
+		"postgres.dsnn: unknown key (file " + path + ")",
+		`redis.db: invalid int "x" (env REDIS_DB)`,
+		"nope: unknown key (flag --set)",
+		`role: must be one of api, ingester, all, got "worker"`,
+		"postgres.max_conns: must be at least 1, got 0",
+		"cache.soft_ttl: must be 0 or less than ttl (5m0s), got 10m0s",
+	}, problems(t, err))
+}
+
+func TestLoad_ArchiveMustRunBeforeDetach(t *testing.T) {
+	_, err := Load(Sources{Overrides: map[string]string{
+		"archive.enabled":             "true",
+		"archive.after_months":        "6",
+		"partitions.retention_months": "6",
+		"archive.store":               "s3",
+	}})
+
+	require.ElementsMatch(t, []string{
+		"archive.after_months: must be less than partitions.retention_months (6), got 6",
+		`archive.s3_bucket: must not be empty for store "s3"`,
+		`archive.s3_access_key: s3_access_key and s3_secret_key must be set for store "s3"`,
+	}, problems(t, err))
+}
+
+func TestLoad_AuthNeedsAMethod(t *testing.T) {
+	_, err := Load(Sources{Overrides: map[string]string{"auth.api_keys": "false"}})
+	require.Equal(t, []string{"auth.api_keys: must be true when neither jwks_url nor jwks_file is set"}, problems(t, err))
+
+	c, err := Load(Sources{Overrides: map[string]string{"auth.api_keys": "false", "auth.jwks_file": "jwks.json"}})
+	require.NoError(t, err)
+	require.True(t, c.Auth.JWTEnabled())
+}
+
+func TestLoad_EncryptionNeedsKeys(t *testing.T) {
+	_, err := Load(Sources{Overrides: map[string]string{"encryption.enabled": "true"}})
+	require.ElementsMatch(t, []string{
+		"encryption.keys: keys or keys_file must be set when encryption is enabled",
+		"encryption.active_key_id: must not be empty when encryption is enabled",
+		"encryption.blind_index_key: must not be empty when encryption is enabled",
+	}, problems(t, err))
+}
+
+func TestLoad_Validators(t *testing.T) {
+	_, err := Load(Sources{
+		Overrides: map[string]string{"ratelimit.default": "fast"},
+		Validators: []Validator{func(c *Config) []string {
+			return []string{"ratelimit.default: got " + c.RateLimit.Default}
+		}},
+	})
+	require.Equal(t, []string{"ratelimit.default: got fast"}, problems(t, err))
+}
+
+func TestLoad_UnsupportedExtension(t *testing.T) {
+	_, err := Load(Sources{File: writeFile(t, "config.json", "{}")})
+	require.ErrorContains(t, err, "unsupported extension")
+}
+
+func TestConfig_YAML_RoundTrip(t *testing.T) {
+	c := Defaults()
+	c.Kafka.Brokers = []string{"a:1", "b:2"}
+	c.Cache.SoftTTL = 30 * time.Second
+	c.Redis.Password = "p"
+
+	b, err := c.YAML()
+	require.NoError(t, err)
+	require.Contains(t, string(b), "# Hard TTL")
+
+	got, err := Load(Sources{File: writeFile(t, "c.yaml", string(b))})
+	require.NoError(t, err)
+	require.Equal(t, c, got)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"net/url"
+	"regexp"
+)
+
+// secretMask совпадает с тем, что подставляет url.URL.Redacted.
+const secretMask = "xxxxx"
+
+var dsnPasswordRe = regexp.MustCompile(`(?i)(password\s*=\s*)('[^']*'|\S+)`)
+
+// Masked возвращает копию конфига, в которой поля с тегом secret замаскированы.
+func (c Config) Masked() Config {
+	c.Kafka.Brokers = append([]string(nil), c.Kafka.Brokers...)
+
+	for _, f := range fields(&c) {
+		if f.Secret != "" {
+			f.value.SetString(maskSecret(f.Secret, f.value.String()))
+		}
+	}
+	return c
+}
+
+// maskSecret маскирует значение поля с тегом secret:kind.
+func maskSecret(kind, v string) string {
+	switch {
+	case v == "":
+		return ""
+	case kind == "dsn":
+		return MaskDSN(v)
+	default:
+		return secretMask
+	}
+}
+
+// MaskDSN скрывает пароль в DSN: как в URL-форме (postgres://u:p@h/db),
+// так и в key=value форме (password=p).
+func MaskDSN(dsn string) string {
+	if u, err := url.Parse(dsn); err == nil && u.Scheme != "" && u.Host != "" {
+		return u.Redacted()
+	}
+	return dsnPasswordRe.ReplaceAllString(dsn, "${1}"+secretMask)
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskDSN(t *testing.T) {
+	cases := map[string]string{
+		"postgres://user:secret@db:5432/wb?sslmode=disable": "postgres://user:xxxxx@db:5432/wb?sslmode=disable",
+		"postgres://user@db:5432/wb":                        "postgres://user@db:5432/wb",
+		"host=db user=u password=secret dbname=wb":          "host=db user=u password=xxxxx dbname=wb",
+		"host=db password='se cret' dbname=wb":              "host=db password=xxxxx dbname=wb",
+	}
+	for in, want := range cases {
+		require.Equal(t, want, MaskDSN(in), in)
+	}
+}
+
+func TestConfig_Masked(t *testing.T) {
+	c := Config{
+		Postgres: PostgresConfig{DSN: "postgres://user:secret@db/wb"},
+		Redis:    RedisConfig{Addr: "redis:6379", Password: "hunter2"},
+	}
+
+	m := c.Masked()
+
+	require.Equal(t, "postgres://user:xxxxx@db/wb", m.Postgres.DSN)
+	require.Equal(t, "xxxxx", m.Redis.Password)
+	require.Equal(t, "redis:6379", m.Redis.Addr)
+	require.Equal(t, "hunter2", c.Redis.Password)
+}
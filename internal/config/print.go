@@ -0,0 +1,118 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaField описывает настройку для документации.
+type SchemaField struct {
+	Key     string
+	Env     string
+	Type    string
+	Default string
+	Desc    string
+	Secret  bool
+	Reload  bool
+}
+
+// Schema перечисляет все настройки с их значениями по умолчанию.
+func Schema() []SchemaField {
+	def := Defaults()
+	def.InstanceID = "hostname-pid"
+
+	fs := fields(&def)
+	out := make([]SchemaField, len(fs))
+	for i, f := range fs {
+		out[i] = SchemaField{
+			Key:     f.Key,
+			Env:     f.Env,
+			Type:    f.Type,
+			Default: f.String(),
+			Desc:    f.Desc,
+			Secret:  f.Secret != "",
+			Reload:  f.Reload,
+		}
+	}
+	return out
+}
+
+// SchemaMarkdown возвращает таблицу настроек для README.
+func SchemaMarkdown() string {
+	var b strings.Builder
+	b.WriteString("| Ключ | Env | Тип | По умолчанию | Описание |\n")
+	b.WriteString("| ---- | --- | --- | ------------ | -------- |\n")
+	for _, f := range Schema() {
+		def := "`" + f.Default + "`"
+		if f.Default == "" {
+			def = "—"
+		}
+		desc := f.Desc
+		if f.Secret {
+			desc += " (секрет)"
+		}
+		if f.Reload {
+			desc += " (hot reload)"
+		}
+		fmt.Fprintf(&b, "| `%s` | `%s` | %s | %s | %s |\n", f.Key, f.Env, f.Type, def, desc)
+	}
+	return b.String()
+}
+
+// YAML сериализует конфиг в формате файла конфигурации с описанием каждого
+// поля в комментарии. Секреты не маскируются: для вывода используйте Masked.
+func (c Config) YAML() ([]byte, error) {
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	sections := make(map[string]*yaml.Node)
+
+	for _, f := range fields(&c) {
+		parent := root
+		name := f.Key
+		if i := strings.IndexByte(f.Key, '.'); i >= 0 {
+			section := f.Key[:i]
+			name = f.Key[i+1:]
+			parent = sections[section]
+			if parent == nil {
+				parent = &yaml.Node{Kind: yaml.MappingNode}
+				sections[section] = parent
+				root.Content = append(root.Content,
+					&yaml.Node{Kind: yaml.ScalarNode, Value: section},
+					parent,
+				)
+			}
+		}
+
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: name, HeadComment: f.Desc + " (env " + f.Env + ")"}
+		parent.Content = append(parent.Content, key, valueNode(f))
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func valueNode(f Field) *yaml.Node {
+	if f.Type == "list" {
+		seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+		for _, item := range f.value.Interface().([]string) {
+			seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item})
+		}
+		return seq
+	}
+
+	n := &yaml.Node{Kind: yaml.ScalarNode, Value: f.String()}
+	if f.Type == "string" || f.Type == "duration" {
+		n.Tag = "!!str"
+	}
+	return n
+}
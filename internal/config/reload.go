@@ -0,0 +1,222 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// Change — изменившаяся настройка; значения секретов замаскированы.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+type Changes []Change
+
+// Has сообщает, изменилась ли хотя бы одна из настроек keys.
+func (cs Changes) Has(keys ...string) bool {
+	for _, c := range cs {
+		for _, k := range keys {
+			if c.Key == k {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cs Changes) String() string {
+	parts := make([]string, len(cs))
+	for i, c := range cs {
+		parts[i] = fmt.Sprintf("%s: %q -> %q", c.Key, c.Old, c.New)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RestartRequiredError — в новом конфиге поменялись настройки, которые
+// не применяются на лету. Такой reload отклоняется целиком.
+type RestartRequiredError struct {
+	Keys []string
+}
+
+func (e *RestartRequiredError) Error() string {
+	return "restart required to change: " + strings.Join(e.Keys, ", ")
+}
+
+// Diff возвращает настройки, значения которых отличаются в old и new.
+// Сравниваются исходные значения, маскируются только Old и New: смена
+// пароля видна, даже если маска у старого и нового значения одинакова.
+func Diff(old, new Config) Changes {
+	of, nf := fields(&old), fields(&new)
+
+	var out Changes
+	for i := range of {
+		o, n := of[i].String(), nf[i].String()
+		if o == n {
+			continue
+		}
+		if kind := of[i].Secret; kind != "" {
+			o, n = maskSecret(kind, o), maskSecret(kind, n)
+		}
+		out = append(out, Change{Key: of[i].Key, Old: o, New: n})
+	}
+	return out
+}
+
+// Reloader перечитывает конфиг из тех же источников, что и при старте,
+// и раздаёт изменения подписчикам. Меняться могут только поля с тегом
+// reload; невалидный конфиг или изменение остальных полей отклоняется,
+// и текущий конфиг остаётся прежним.
+type Reloader struct {
+	sources Sources
+	current atomic.Pointer[Config]
+
+	mu   sync.Mutex
+	subs []func(c *Config, changed Changes)
+}
+
+func NewReloader(s Sources, c Config) *Reloader {
+	r := &Reloader{sources: s}
+	r.current.Store(&c)
+	return r
+}
+
+// Current возвращает последний применённый конфиг.
+func (r *Reloader) Current() *Config {
+	return r.current.Load()
+}
+
+// Subscribe регистрирует fn; fn вызывается после каждого успешного reload,
+// который что-то поменял. Вызовы fn последовательны.
+func (r *Reloader) Subscribe(fn func(c *Config, changed Changes)) {
+	r.mu.Lock()
+	r.subs = append(r.subs, fn)
+	r.mu.Unlock()
+}
+
+// Reload перечитывает конфиг и применяет его, если он валиден и меняет
+// только настройки с тегом reload.
+func (r *Reloader) Reload() (Changes, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := Load(r.sources)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := r.current.Load()
+	changes := Diff(*cur, next)
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	reloadable := make(map[string]bool)
+	for _, f := range fields(&next) {
+		reloadable[f.Key] = f.Reload
+	}
+	var restart []string
+	for _, c := range changes {
+		if !reloadable[c.Key] {
+			restart = append(restart, c.Key)
+		}
+	}
+	if len(restart) > 0 {
+		return changes, &RestartRequiredError{Keys: restart}
+	}
+
+	r.current.Store(&next)
+	for _, fn := range r.subs {
+		fn(&next, changes)
+	}
+	return changes, nil
+}
+
+// Watch перечитывает конфиг по SIGHUP и при изменении содержимого файла
+// (проверяется раз в reload_interval) до отмены ctx.
+func (r *Reloader) Watch(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	path := r.path()
+	sum := fileSum(path)
+
+	for {
+		var (
+			timer *time.Timer
+			tick  <-chan time.Time
+		)
+		if interval := r.Current().ReloadInterval; path != "" && interval > 0 {
+			timer = time.NewTimer(interval)
+			tick = timer.C
+		}
+		stop := func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stop()
+			return
+		case <-hup:
+			stop()
+			log.Printf("[config] SIGHUP received, reloading")
+			sum = fileSum(path)
+		case <-tick:
+			s := fileSum(path)
+			if s == nil || bytes.Equal(s, sum) {
+				continue
+			}
+			sum = s
+			log.Printf("[config] %s changed, reloading", path)
+		}
+
+		changes, err := r.Reload()
+		switch {
+		case err != nil && len(changes) > 0:
+			log.Printf("[config] reload rejected, keeping current config: %v (diff: %s)", err, changes)
+		case err != nil:
+			log.Printf("[config] reload rejected, keeping current config: %v", err)
+		case len(changes) == 0:
+			log.Printf("[config] reload: nothing changed")
+		default:
+			for _, c := range changes {
+				log.Printf("[config] reload: %s %q -> %q", c.Key, c.Old, c.New)
+			}
+		}
+	}
+}
+
+func (r *Reloader) path() string {
+	if r.sources.File != "" {
+		return r.sources.File
+	}
+	return os.Getenv("CONFIG_FILE")
+}
+
+// fileSum возвращает хэш содержимого файла или nil, если файл не читается.
+func fileSum(path string) []byte {
+	if path == "" {
+		return nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	s := sha256.Sum256(b)
+	return s[:]
+}
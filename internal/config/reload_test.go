@@ -0,0 +1,88 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReloader(t *testing.T, content string) (*Reloader, string) {
+	t.Helper()
+
+	path := writeFile(t, "config.yaml", content)
+	c, err := Load(Sources{File: path})
+	require.NoError(t, err)
+	return NewReloader(Sources{File: path}, c), path
+}
+
+func TestReloader_AppliesReloadableChanges(t *testing.T) {
+	r, path := newTestReloader(t, "cache:\n  ttl: 5m\nlogger:\n  level: info\n")
+
+	var got Changes
+	r.Subscribe(func(c *Config, changed Changes) {
+		got = changed
+		require.Equal(t, 10*time.Minute, c.Cache.TTL)
+	})
+
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  ttl: 10m\nlogger:\n  level: debug\n"), 0o600))
+	changes, err := r.Reload()
+	require.NoError(t, err)
+	require.Equal(t, Changes{
+		{Key: "logger.level", Old: "info", New: "debug"},
+		{Key: "cache.ttl", Old: "5m0s", New: "10m0s"},
+	}, changes)
+	require.Equal(t, changes, got)
+	require.True(t, got.Has("cache.ttl", "cache.soft_ttl"))
+	require.False(t, got.Has("kafka.max_retries"))
+	require.Equal(t, "debug", r.Current().Logger.Level)
+}
+
+func TestReloader_RejectsInvalidConfig(t *testing.T) {
+	r, path := newTestReloader(t, "cache:\n  ttl: 5m\n")
+	r.Subscribe(func(*Config, Changes) { t.Fatal("subscriber must not be called") })
+
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  ttl: 1m\n  soft_ttl: 2m\n"), 0o600))
+	_, err := r.Reload()
+	var verr *ValidationError
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, 5*time.Minute, r.Current().Cache.TTL)
+}
+
+func TestReloader_RejectsRestartOnlyChanges(t *testing.T) {
+	r, path := newTestReloader(t, "cache:\n  ttl: 5m\nkafka:\n  topic: orders\n")
+	r.Subscribe(func(*Config, Changes) { t.Fatal("subscriber must not be called") })
+
+	require.NoError(t, os.WriteFile(path, []byte("cache:\n  ttl: 10m\nkafka:\n  topic: orders-v2\n"), 0o600))
+	_, err := r.Reload()
+	var rerr *RestartRequiredError
+	require.True(t, errors.As(err, &rerr))
+	require.Equal(t, []string{"kafka.topic"}, rerr.Keys)
+	require.Equal(t, 5*time.Minute, r.Current().Cache.TTL, "reload is all-or-nothing")
+}
+
+func TestDiff_MasksSecrets(t *testing.T) {
+	a, b := Defaults(), Defaults()
+	b.Postgres.DSN = "postgres://user:secret@db:5432/wb"
+
+	changes := Diff(a, b)
+	require.Len(t, changes, 1)
+	require.NotContains(t, changes[0].New, "secret")
+}
+
+func TestDiff_SecretChangeWithSameMask(t *testing.T) {
+	a, b := Defaults(), Defaults()
+	a.Postgres.DSN = "postgres://user:old@db:5432/wb"
+	b.Postgres.DSN = "postgres://user:new@db:5432/wb"
+	a.Redis.Password, b.Redis.Password = "old", "new"
+
+	changes := Diff(a, b)
+	require.True(t, changes.Has("postgres.dsn", "redis.password"))
+	require.Len(t, changes, 2)
+	for _, c := range changes {
+		require.Equal(t, c.Old, c.New, "both sides are masked")
+		require.NotContains(t, c.New, "new")
+	}
+}
@@ -0,0 +1,272 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// shutdownTimeout — таймаут closer'а на graceful shutdown.
+const shutdownTimeout = 5 * time.Second
+
+var logLevels = map[string]bool{
+	"debug": true, "info": true, "warn": true, "error": true, "panic": true, "fatal": true,
+}
+
+// Validate возвращает список всех проблем конфига.
+func (c *Config) Validate() []string {
+	var p []string
+	add := func(key, format string, args ...any) {
+		p = append(p, key+": "+fmt.Sprintf(format, args...))
+	}
+	positive := func(key string, d time.Duration) {
+		if d <= 0 {
+			add(key, "must be positive, got %s", d)
+		}
+	}
+
+	if c.Env == "" {
+		add("env", "must not be empty")
+	}
+	if c.InstanceID == "" {
+		add("instance_id", "must not be empty")
+	}
+	switch c.Role {
+	case RoleAPI, RoleIngester, RoleAll:
+	default:
+		add("role", "must be one of %s, %s, %s, got %q", RoleAPI, RoleIngester, RoleAll, c.Role)
+	}
+	if c.ReloadInterval < 0 {
+		add("reload_interval", "must not be negative, got %s", c.ReloadInterval)
+	}
+
+	if c.Postgres.DSN == "" {
+		add("postgres.dsn", "must not be empty")
+	}
+	if c.Postgres.MaxConns < 1 {
+		add("postgres.max_conns", "must be at least 1, got %d", c.Postgres.MaxConns)
+	}
+	if c.Postgres.MinConns < 0 || c.Postgres.MinConns > c.Postgres.MaxConns {
+		add("postgres.min_conns", "must be between 0 and max_conns (%d), got %d", c.Postgres.MaxConns, c.Postgres.MinConns)
+	}
+	positive("postgres.max_conn_lifetime", c.Postgres.MaxConnLifetime)
+	positive("postgres.max_conn_idle_time", c.Postgres.MaxConnIdleTime)
+	positive("postgres.health_check_period", c.Postgres.HealthCheckPeriod)
+	if c.Postgres.AcquireTimeout < 0 {
+		add("postgres.acquire_timeout", "must not be negative, got %s", c.Postgres.AcquireTimeout)
+	}
+	if c.Postgres.StatementTimeout < 0 {
+		add("postgres.statement_timeout", "must not be negative, got %s", c.Postgres.StatementTimeout)
+	}
+	if c.Postgres.ReplicaDSN != "" {
+		if c.Postgres.ReplicaLagWindow < 0 {
+			add("postgres.replica_lag_window", "must not be negative, got %s", c.Postgres.ReplicaLagWindow)
+		}
+		positive("postgres.replica_check_interval", c.Postgres.ReplicaCheckInterval)
+	}
+
+	if c.HTTP.Addr == "" {
+		add("http.addr", "must not be empty")
+	}
+	if c.HTTP.OpsAddr == "" {
+		add("http.ops_addr", "must not be empty")
+	}
+	positive("http.read_header_timeout", c.HTTP.ReadHeaderTimeout)
+	positive("http.read_timeout", c.HTTP.ReadTimeout)
+	positive("http.write_timeout", c.HTTP.WriteTimeout)
+	positive("http.idle_timeout", c.HTTP.IdleTimeout)
+	positive("http.ready_timeout", c.HTTP.ReadyTimeout)
+	if c.HTTP.ShutdownDelay < 0 || c.HTTP.ShutdownDelay >= shutdownTimeout {
+		add("http.shutdown_delay", "must be in [0, %s), got %s", shutdownTimeout, c.HTTP.ShutdownDelay)
+	}
+	if c.HTTP.CompressMinSize < 0 {
+		add("http.compress_min_size", "must not be negative, got %d", c.HTTP.CompressMinSize)
+	}
+	if c.HTTP.OrderMaxAge < 0 {
+		add("http.order_max_age", "must not be negative, got %s", c.HTTP.OrderMaxAge)
+	}
+
+	if c.GRPC.Enabled && c.GRPC.Addr == "" {
+		add("grpc.addr", "must not be empty when grpc.enabled")
+	}
+	if c.GRPC.MaxBatch < 1 {
+		add("grpc.max_batch", "must be positive, got %d", c.GRPC.MaxBatch)
+	}
+
+	if c.Feed.RingSize < 0 {
+		add("feed.ring_size", "must not be negative, got %d", c.Feed.RingSize)
+	}
+	if c.Feed.Buffer < 1 {
+		add("feed.buffer", "must be positive, got %d", c.Feed.Buffer)
+	}
+	if c.Feed.MaxSubscribers < 1 {
+		add("feed.max_subscribers", "must be positive, got %d", c.Feed.MaxSubscribers)
+	}
+	positive("feed.keepalive", c.Feed.Keepalive)
+
+	if c.GraphQL.MaxDepth < 1 {
+		add("graphql.max_depth", "must be positive, got %d", c.GraphQL.MaxDepth)
+	}
+	if c.GraphQL.MaxComplexity < 1 {
+		add("graphql.max_complexity", "must be positive, got %d", c.GraphQL.MaxComplexity)
+	}
+
+	if c.RateLimit.ProxyHops < 1 {
+		add("ratelimit.proxy_hops", "must be positive, got %d", c.RateLimit.ProxyHops)
+	}
+	switch c.RateLimit.Backend {
+	case RateLimitBackendMemory:
+	case RateLimitBackendRedis:
+		if c.Redis.Addr == "" {
+			add("redis.addr", "must not be empty for ratelimit backend %q", RateLimitBackendRedis)
+		}
+	default:
+		add("ratelimit.backend", "must be one of %s, %s, got %q", RateLimitBackendMemory, RateLimitBackendRedis, c.RateLimit.Backend)
+	}
+
+	if !logLevels[c.Logger.Level] {
+		add("logger.level", "unknown level %q", c.Logger.Level)
+	}
+
+	if len(c.Kafka.Brokers) == 0 {
+		add("kafka.brokers", "must not be empty")
+	}
+	if c.Kafka.Topic == "" {
+		add("kafka.topic", "must not be empty")
+	}
+	if c.Kafka.GroupID == "" {
+		add("kafka.group_id", "must not be empty")
+	}
+	if c.Kafka.DLQTopic == "" {
+		add("kafka.dlq_topic", "must not be empty")
+	}
+	if c.Kafka.MaxRetries < 0 {
+		add("kafka.max_retries", "must not be negative, got %d", c.Kafka.MaxRetries)
+	}
+	positive("kafka.retry_base_backoff", c.Kafka.RetryBaseBackoff)
+	positive("kafka.retry_max_backoff", c.Kafka.RetryMaxBackoff)
+	if c.Kafka.RetryBaseBackoff > c.Kafka.RetryMaxBackoff {
+		add("kafka.retry_base_backoff", "must not exceed retry_max_backoff (%s)", c.Kafka.RetryMaxBackoff)
+	}
+	positive("kafka.worker_stall_timeout", c.Kafka.WorkerStallTimeout)
+	if c.Kafka.InvalidationTopic != "" {
+		if c.Kafka.InvalidationReplication < 1 {
+			add("kafka.invalidation_replication", "must be at least 1, got %d", c.Kafka.InvalidationReplication)
+		}
+	}
+	if c.Kafka.FeedTopic != "" {
+		if c.Kafka.FeedReplication < 1 {
+			add("kafka.feed_replication", "must be at least 1, got %d", c.Kafka.FeedReplication)
+		}
+		positive("kafka.feed_retention", c.Kafka.FeedRetention)
+	}
+
+	switch c.Cache.Backend {
+	case CacheBackendMemory, CacheBackendRedis, CacheBackendTiered:
+	default:
+		add("cache.backend", "must be one of %s, %s, %s, got %q",
+			CacheBackendMemory, CacheBackendRedis, CacheBackendTiered, c.Cache.Backend)
+	}
+	positive("cache.ttl", c.Cache.TTL)
+	if c.Cache.SoftTTL < 0 || (c.Cache.SoftTTL > 0 && c.Cache.SoftTTL >= c.Cache.TTL) {
+		add("cache.soft_ttl", "must be 0 or less than ttl (%s), got %s", c.Cache.TTL, c.Cache.SoftTTL)
+	}
+	positive("cache.refresh_timeout", c.Cache.RefreshTimeout)
+	positive("cache.snapshot_interval", c.Cache.SnapshotInterval)
+
+	if c.Cache.Backend == CacheBackendRedis || c.Cache.Backend == CacheBackendTiered {
+		if c.Redis.Addr == "" {
+			add("redis.addr", "must not be empty for cache backend %q", c.Cache.Backend)
+		}
+		if c.Redis.DB < 0 {
+			add("redis.db", "must not be negative, got %d", c.Redis.DB)
+		}
+		positive("redis.timeout", c.Redis.Timeout)
+	}
+
+	if !c.Auth.APIKeys && !c.Auth.JWTEnabled() {
+		add("auth.api_keys", "must be true when neither jwks_url nor jwks_file is set")
+	}
+	if c.Auth.JWKSURL != "" && c.Auth.JWKSFile != "" {
+		add("auth.jwks_file", "must be empty when jwks_url is set")
+	}
+	if c.Auth.APIKeyCacheTTL < 0 {
+		add("auth.api_key_cache_ttl", "must not be negative, got %s", c.Auth.APIKeyCacheTTL)
+	}
+	if c.Auth.JWKSURL != "" {
+		positive("auth.jwks_refresh", c.Auth.JWKSRefresh)
+	}
+	if c.Auth.JWTLeeway < 0 {
+		add("auth.jwt_leeway", "must not be negative, got %s", c.Auth.JWTLeeway)
+	}
+
+	if c.Encryption.Enabled {
+		if c.Encryption.Keys == "" && c.Encryption.KeysFile == "" {
+			add("encryption.keys", "keys or keys_file must be set when encryption is enabled")
+		}
+		if c.Encryption.Keys != "" && c.Encryption.KeysFile != "" {
+			add("encryption.keys_file", "must be empty when keys is set")
+		}
+		if c.Encryption.ActiveKeyID == "" {
+			add("encryption.active_key_id", "must not be empty when encryption is enabled")
+		}
+		if c.Encryption.BlindIndexKey == "" {
+			add("encryption.blind_index_key", "must not be empty when encryption is enabled")
+		}
+	}
+	if c.Encryption.ReencryptInterval < 0 {
+		add("encryption.reencrypt_interval", "must not be negative, got %s", c.Encryption.ReencryptInterval)
+	}
+	if c.Encryption.ReencryptBatch < 1 {
+		add("encryption.reencrypt_batch", "must be at least 1, got %d", c.Encryption.ReencryptBatch)
+	}
+
+	if c.Partitions.Enabled {
+		positive("partitions.interval", c.Partitions.Interval)
+	}
+	if c.Partitions.PremakeMonths < 1 {
+		add("partitions.premake_months", "must be at least 1, got %d", c.Partitions.PremakeMonths)
+	}
+	if c.Partitions.RetentionMonths < 0 {
+		add("partitions.retention_months", "must not be negative, got %d", c.Partitions.RetentionMonths)
+	}
+
+	if c.Archive.Enabled {
+		positive("archive.interval", c.Archive.Interval)
+	}
+	if c.Archive.AfterMonths < 1 {
+		add("archive.after_months", "must be at least 1, got %d", c.Archive.AfterMonths)
+	}
+	// Секция старше retention удаляется, только когда её месяц выгружен
+	// в архив; без архива она так и осталась бы в БД.
+	if c.Partitions.Enabled && c.Partitions.RetentionMonths > 0 {
+		if !c.Archive.Enabled {
+			add("partitions.retention_months", "requires archive.enabled: partitions are dropped only after archiving")
+		} else if c.Archive.AfterMonths >= c.Partitions.RetentionMonths {
+			add("archive.after_months", "must be less than partitions.retention_months (%d), got %d",
+				c.Partitions.RetentionMonths, c.Archive.AfterMonths)
+		}
+	}
+	if c.Archive.BatchSize < 1 {
+		add("archive.batch_size", "must be at least 1, got %d", c.Archive.BatchSize)
+	}
+	switch c.Archive.Store {
+	case ArchiveStoreFS:
+		if c.Archive.FSDir == "" {
+			add("archive.fs_dir", "must not be empty for store %q", ArchiveStoreFS)
+		}
+	case ArchiveStoreS3:
+		if c.Archive.S3Bucket == "" {
+			add("archive.s3_bucket", "must not be empty for store %q", ArchiveStoreS3)
+		}
+		if c.Archive.S3Region == "" {
+			add("archive.s3_region", "must not be empty for store %q", ArchiveStoreS3)
+		}
+		if c.Archive.S3AccessKey == "" || c.Archive.S3SecretKey == "" {
+			add("archive.s3_access_key", "s3_access_key and s3_secret_key must be set for store %q", ArchiveStoreS3)
+		}
+	default:
+		add("archive.store", "must be one of %s, %s, got %q", ArchiveStoreFS, ArchiveStoreS3, c.Archive.Store)
+	}
+
+	return p
+}
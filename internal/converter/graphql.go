@@ -0,0 +1,75 @@
+package converter
+
+import (
+	gql "app/internal/api/graphql"
+	"app/internal/model"
+	"app/internal/pii"
+)
+
+// ModelOrderToGraphQL — то же, что ModelOrderToGen, для GraphQL-представления.
+func ModelOrderToGraphQL(o model.Order, v pii.View) *gql.Order {
+	items := make([]*gql.Item, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = ModelItemToGraphQL(it)
+	}
+
+	return &gql.Order{
+		OrderUID:          o.OrderUUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Locale:            o.Locale,
+		InternalSignature: redactOptional(v, pii.FieldInternalSignature, o.InternalSignature),
+		CustomerID:        redactOptional(v, pii.FieldCustomerID, o.CustomerID),
+		DeliveryService:   o.DeliveryService,
+		ShardKey:          o.ShardKEy,
+		SmID:              o.SmID,
+		DateCreated:       o.DateCreated,
+		OffShard:          o.OffShard,
+		Delivery:          ModelDeliveryToGraphQL(o.Delivery, v),
+		Payment:           ModelPaymentToGraphQL(o.Payment, v),
+		Items:             items,
+	}
+}
+
+func ModelDeliveryToGraphQL(d model.Delivery, v pii.View) *gql.Delivery {
+	return &gql.Delivery{
+		Name:    redactOptional(v, pii.FieldName, d.Name),
+		Phone:   redactOptional(v, pii.FieldPhone, d.Phone),
+		Zip:     redactOptional(v, pii.FieldZip, d.Zip),
+		City:    redactOptional(v, pii.FieldCity, d.City),
+		Address: redactOptional(v, pii.FieldAddress, d.Address),
+		Region:  d.Region,
+		Email:   redactOptional(v, pii.FieldEmail, d.Email),
+	}
+}
+
+func ModelPaymentToGraphQL(p model.Payment, v pii.View) *gql.Payment {
+	return &gql.Payment{
+		Transaction:  redactOptional(v, pii.FieldTransaction, p.Transaction),
+		Request:      redactOptional(v, pii.FieldRequestID, p.RequestID),
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       p.Amount,
+		PaymentDt:    p.PaymentDT,
+		Bank:         p.Bank,
+		DeliveryCost: p.DeliveryCost,
+		GoodsTotal:   p.GoodsTotal,
+		CustomFee:    p.CustomFee,
+	}
+}
+
+func ModelItemToGraphQL(it model.Item) *gql.Item {
+	return &gql.Item{
+		ChrtID:      int(it.ChrtID),
+		TrackNumber: it.TrackNumber,
+		Price:       it.Price,
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        it.Sale,
+		Size:        it.Size,
+		TotalPrice:  it.TotalPrice,
+		NmID:        it.NmID,
+		Brand:       it.Brand,
+		Status:      it.Status,
+	}
+}
@@ -3,13 +3,16 @@ package converter
 import (
 	gen "app/internal/api/v1"
 	"app/internal/model"
+	"app/internal/pii"
 )
 
 //
 // model -> gen
 //
 
-func ModelOrderToGen(o model.Order) gen.Order {
+// ModelOrderToGen применяет к персональным полям политику v: поле
+// отдаётся целиком, маскируется или не попадает в ответ.
+func ModelOrderToGen(o model.Order, v pii.View) gen.Order {
 	items := make([]gen.Item, len(o.Items))
 	for i, it := range o.Items {
 		items[i] = ModelItemToGen(it)
@@ -20,35 +23,35 @@ func ModelOrderToGen(o model.Order) gen.Order {
 		TrackNumber:       o.TrackNumber,
 		Entry:             o.Entry,
 		Locale:            o.Locale,
-		InternalSignature: o.InternalSignature,
-		CustomerID:        o.CustomerID,
+		InternalSignature: redact(v, pii.FieldInternalSignature, o.InternalSignature),
+		CustomerID:        redact(v, pii.FieldCustomerID, o.CustomerID),
 		DeliveryService:   o.DeliveryService,
 		ShardKey:          o.ShardKEy,
 		SmID:              int32(o.SmID),
 		DateCreated:       o.DateCreated,
 		OffShard:          o.OffShard,
-		Delivery:          ModelDeliveryToGen(o.Delivery),
-		Payment:           ModelPaymentToGen(o.Payment),
+		Delivery:          ModelDeliveryToGen(o.Delivery, v),
+		Payment:           ModelPaymentToGen(o.Payment, v),
 		Items:             items,
 	}
 }
 
-func ModelDeliveryToGen(d model.Delivery) gen.Delivery {
+func ModelDeliveryToGen(d model.Delivery, v pii.View) gen.Delivery {
 	return gen.Delivery{
-		Name:    d.Name,
-		Phone:   d.Phone,
-		Zip:     d.Zip,
-		City:    d.City,
-		Address: d.Address,
+		Name:    redact(v, pii.FieldName, d.Name),
+		Phone:   redact(v, pii.FieldPhone, d.Phone),
+		Zip:     redact(v, pii.FieldZip, d.Zip),
+		City:    redact(v, pii.FieldCity, d.City),
+		Address: redact(v, pii.FieldAddress, d.Address),
 		Region:  d.Region,
-		Email:   d.Email,
+		Email:   redact(v, pii.FieldEmail, d.Email),
 	}
 }
 
-func ModelPaymentToGen(p model.Payment) gen.Payment {
+func ModelPaymentToGen(p model.Payment, v pii.View) gen.Payment {
 	return gen.Payment{
-		Transaction:  p.Transaction,
-		Request:      p.RequestID,
+		Transaction:  redact(v, pii.FieldTransaction, p.Transaction),
+		Request:      redact(v, pii.FieldRequestID, p.RequestID),
 		Currency:     p.Currency,
 		Provider:     p.Provider,
 		Amount:       int32(p.Amount),
@@ -76,6 +79,13 @@ func ModelItemToGen(it model.Item) gen.Item {
 	}
 }
 
+func redact(v pii.View, field, value string) gen.OptString {
+	if out, ok := v.Apply(field, value); ok {
+		return gen.NewOptString(out)
+	}
+	return gen.OptString{}
+}
+
 //
 // gen -> model
 //
@@ -91,8 +101,8 @@ func GenOrderToModel(o gen.Order) model.Order {
 		TrackNumber:       o.TrackNumber,
 		Entry:             o.Entry,
 		Locale:            o.Locale,
-		InternalSignature: o.InternalSignature,
-		CustomerID:        o.CustomerID,
+		InternalSignature: o.InternalSignature.Value,
+		CustomerID:        o.CustomerID.Value,
 		DeliveryService:   o.DeliveryService,
 		ShardKEy:          o.ShardKey,
 		SmID:              int(o.SmID),
@@ -106,20 +116,20 @@ func GenOrderToModel(o gen.Order) model.Order {
 
 func GenDeliveryToModel(d gen.Delivery) model.Delivery {
 	return model.Delivery{
-		Name:    d.Name,
-		Phone:   d.Phone,
-		Zip:     d.Zip,
-		City:    d.City,
-		Address: d.Address,
+		Name:    d.Name.Value,
+		Phone:   d.Phone.Value,
+		Zip:     d.Zip.Value,
+		City:    d.City.Value,
+		Address: d.Address.Value,
 		Region:  d.Region,
-		Email:   d.Email,
+		Email:   d.Email.Value,
 	}
 }
 
 func GenPaymentToModel(p gen.Payment) model.Payment {
 	return model.Payment{
-		Transaction:  p.Transaction,
-		RequestID:    p.Request,
+		Transaction:  p.Transaction.Value,
+		RequestID:    p.Request.Value,
 		Currency:     p.Currency,
 		Provider:     p.Provider,
 		Amount:       int(p.Amount),
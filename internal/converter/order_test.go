@@ -0,0 +1,86 @@
+package converter
+
+import (
+	gen "app/internal/api/v1"
+	"app/internal/model"
+	"app/internal/pii"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testOrder() model.Order {
+	return model.Order{
+		OrderUUID:         "uid-1",
+		TrackNumber:       "WBILMTESTTRACK",
+		Entry:             "WBIL",
+		Locale:            "en",
+		InternalSignature: "internal-sig-1",
+		CustomerID:        "customer-042",
+		DeliveryService:   "meest",
+		DateCreated:       time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		Delivery: model.Delivery{
+			Name: "Test Testov", Phone: "+79720000000", Zip: "2639809", City: "Kiryat Mozkin",
+			Address: "Ploshad Mira 15", Region: "Kraiot", Email: "test@gmail.com",
+		},
+		Payment: model.Payment{Transaction: "b563feb7b2b84b6test", RequestID: "req-0000042", Currency: "USD", Amount: 1817},
+		Items:   []model.Item{{ChrtID: 9934930, Name: "Mascaras", Price: 453}},
+	}
+}
+
+func TestModelOrderToGen_Full(t *testing.T) {
+	o := testOrder()
+	got := ModelOrderToGen(o, pii.DefaultPolicy.For(pii.RoleFull))
+
+	require.Equal(t, gen.NewOptString("internal-sig-1"), got.InternalSignature)
+	require.Equal(t, gen.NewOptString("customer-042"), got.CustomerID)
+	require.Equal(t, gen.NewOptString("+79720000000"), got.Delivery.Phone)
+	require.Equal(t, gen.NewOptString("test@gmail.com"), got.Delivery.Email)
+	require.Equal(t, gen.NewOptString("b563feb7b2b84b6test"), got.Payment.Transaction)
+	require.Equal(t, o, GenOrderToModel(got))
+}
+
+func TestModelOrderToGen_Support(t *testing.T) {
+	got := ModelOrderToGen(testOrder(), pii.DefaultPolicy.For(pii.RoleSupport))
+
+	require.False(t, got.InternalSignature.IsSet())
+	require.False(t, got.Payment.Transaction.IsSet())
+	require.False(t, got.Payment.Request.IsSet())
+	require.Equal(t, gen.NewOptString("+7***0000"), got.Delivery.Phone)
+	require.Equal(t, gen.NewOptString("t***@gmail.com"), got.Delivery.Email)
+	require.Equal(t, gen.NewOptString("Test Testov"), got.Delivery.Name)
+	require.Equal(t, gen.NewOptString("Ploshad Mira 15"), got.Delivery.Address)
+	require.Equal(t, gen.NewOptString("customer-042"), got.CustomerID)
+	require.Equal(t, "USD", got.Payment.Currency)
+}
+
+func TestModelOrderToGen_Analyst(t *testing.T) {
+	got := ModelOrderToGen(testOrder(), pii.DefaultPolicy.For(pii.RoleAnalyst))
+
+	require.False(t, got.InternalSignature.IsSet())
+	require.Equal(t, gen.NewOptString("***-042"), got.CustomerID)
+	for name, f := range map[string]gen.OptString{
+		"name": got.Delivery.Name, "phone": got.Delivery.Phone, "zip": got.Delivery.Zip,
+		"address": got.Delivery.Address, "email": got.Delivery.Email,
+		"transaction": got.Payment.Transaction, "request": got.Payment.Request,
+	} {
+		require.False(t, f.IsSet(), name)
+	}
+	require.Equal(t, gen.NewOptString("Kiryat Mozkin"), got.Delivery.City)
+	require.Equal(t, "Kraiot", got.Delivery.Region)
+}
+
+func TestModelOrderToProto(t *testing.T) {
+	got := ModelOrderToProto(testOrder(), pii.DefaultPolicy.For(pii.RoleSupport))
+
+	require.Equal(t, "uid-1", got.GetOrderUid())
+	require.Nil(t, got.InternalSignature)
+	require.Nil(t, got.GetPayment().Transaction)
+	require.Equal(t, "+7***0000", got.GetDelivery().GetPhone())
+	require.Equal(t, "customer-042", got.GetCustomerId())
+	require.Equal(t, int64(1817), got.GetPayment().GetAmount())
+	require.Equal(t, testOrder().DateCreated, got.GetDateCreated().AsTime())
+	require.Len(t, got.GetItems(), 1)
+	require.Equal(t, int64(9934930), got.GetItems()[0].GetChrtId())
+}
@@ -0,0 +1,168 @@
+package converter
+
+import (
+	"app/internal/api/pb/orderv1"
+	"app/internal/model"
+	"app/internal/pii"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+//
+// model -> protobuf
+//
+
+// ModelOrderToProto — то же, что ModelOrderToGen, для protobuf-представления.
+func ModelOrderToProto(o model.Order, v pii.View) *orderv1.Order {
+	items := make([]*orderv1.Item, len(o.Items))
+	for i, it := range o.Items {
+		items[i] = ModelItemToProto(it)
+	}
+
+	return &orderv1.Order{
+		OrderUid:          o.OrderUUID,
+		TrackNumber:       o.TrackNumber,
+		Entry:             o.Entry,
+		Locale:            o.Locale,
+		InternalSignature: redactOptional(v, pii.FieldInternalSignature, o.InternalSignature),
+		CustomerId:        redactOptional(v, pii.FieldCustomerID, o.CustomerID),
+		DeliveryService:   o.DeliveryService,
+		ShardKey:          o.ShardKEy,
+		SmId:              int32(o.SmID),
+		DateCreated:       timestamppb.New(o.DateCreated),
+		OffShard:          o.OffShard,
+		Delivery:          ModelDeliveryToProto(o.Delivery, v),
+		Payment:           ModelPaymentToProto(o.Payment, v),
+		Items:             items,
+	}
+}
+
+func ModelDeliveryToProto(d model.Delivery, v pii.View) *orderv1.Delivery {
+	return &orderv1.Delivery{
+		Name:    redactOptional(v, pii.FieldName, d.Name),
+		Phone:   redactOptional(v, pii.FieldPhone, d.Phone),
+		Zip:     redactOptional(v, pii.FieldZip, d.Zip),
+		City:    redactOptional(v, pii.FieldCity, d.City),
+		Address: redactOptional(v, pii.FieldAddress, d.Address),
+		Region:  d.Region,
+		Email:   redactOptional(v, pii.FieldEmail, d.Email),
+	}
+}
+
+func ModelPaymentToProto(p model.Payment, v pii.View) *orderv1.Payment {
+	return &orderv1.Payment{
+		Transaction:  redactOptional(v, pii.FieldTransaction, p.Transaction),
+		Request:      redactOptional(v, pii.FieldRequestID, p.RequestID),
+		Currency:     p.Currency,
+		Provider:     p.Provider,
+		Amount:       int64(p.Amount),
+		PaymentDt:    int64(p.PaymentDT),
+		Bank:         p.Bank,
+		DeliveryCost: int64(p.DeliveryCost),
+		GoodsTotal:   int64(p.GoodsTotal),
+		CustomFee:    int64(p.CustomFee),
+	}
+}
+
+func ModelItemToProto(it model.Item) *orderv1.Item {
+	return &orderv1.Item{
+		ChrtId:      it.ChrtID,
+		TrackNumber: it.TrackNumber,
+		Price:       int64(it.Price),
+		Rid:         it.Rid,
+		Name:        it.Name,
+		Sale:        int32(it.Sale),
+		Size:        it.Size,
+		TotalPrice:  int64(it.TotalPrice),
+		NmId:        int64(it.NmID),
+		Brand:       it.Brand,
+		Status:      int32(it.Status),
+	}
+}
+
+// redactOptional — значение optional-поля: nil, если роль его не видит.
+func redactOptional(v pii.View, field, value string) *string {
+	if out, ok := v.Apply(field, value); ok {
+		return &out
+	}
+	return nil
+}
+
+//
+// protobuf -> model
+//
+
+// ProtoOrderToModel переводит заказ из SubmitOrder в модель. Отсутствующие
+// сообщения и поля становятся нулевыми значениями: их отсекает валидация.
+func ProtoOrderToModel(o *orderv1.Order) model.Order {
+	items := make([]model.Item, len(o.GetItems()))
+	for i, it := range o.GetItems() {
+		items[i] = ProtoItemToModel(it)
+	}
+
+	var created time.Time
+	if o.GetDateCreated() != nil {
+		created = o.GetDateCreated().AsTime()
+	}
+
+	return model.Order{
+		OrderUUID:         o.GetOrderUid(),
+		TrackNumber:       o.GetTrackNumber(),
+		Entry:             o.GetEntry(),
+		Locale:            o.GetLocale(),
+		InternalSignature: o.GetInternalSignature(),
+		CustomerID:        o.GetCustomerId(),
+		DeliveryService:   o.GetDeliveryService(),
+		ShardKEy:          o.GetShardKey(),
+		SmID:              int(o.GetSmId()),
+		DateCreated:       created,
+		OffShard:          o.GetOffShard(),
+		Delivery:          ProtoDeliveryToModel(o.GetDelivery()),
+		Payment:           ProtoPaymentToModel(o.GetPayment()),
+		Items:             items,
+	}
+}
+
+func ProtoDeliveryToModel(d *orderv1.Delivery) model.Delivery {
+	return model.Delivery{
+		Name:    d.GetName(),
+		Phone:   d.GetPhone(),
+		Zip:     d.GetZip(),
+		City:    d.GetCity(),
+		Address: d.GetAddress(),
+		Region:  d.GetRegion(),
+		Email:   d.GetEmail(),
+	}
+}
+
+func ProtoPaymentToModel(p *orderv1.Payment) model.Payment {
+	return model.Payment{
+		Transaction:  p.GetTransaction(),
+		RequestID:    p.GetRequest(),
+		Currency:     p.GetCurrency(),
+		Provider:     p.GetProvider(),
+		Amount:       int(p.GetAmount()),
+		PaymentDT:    int(p.GetPaymentDt()),
+		Bank:         p.GetBank(),
+		DeliveryCost: int(p.GetDeliveryCost()),
+		GoodsTotal:   int(p.GetGoodsTotal()),
+		CustomFee:    int(p.GetCustomFee()),
+	}
+}
+
+func ProtoItemToModel(it *orderv1.Item) model.Item {
+	return model.Item{
+		ChrtID:      it.GetChrtId(),
+		TrackNumber: it.GetTrackNumber(),
+		Price:       int(it.GetPrice()),
+		Rid:         it.GetRid(),
+		Name:        it.GetName(),
+		Sale:        int(it.GetSale()),
+		Size:        it.GetSize(),
+		TotalPrice:  int(it.GetTotalPrice()),
+		NmID:        int(it.GetNmId()),
+		Brand:       it.GetBrand(),
+		Status:      int(it.GetStatus()),
+	}
+}
@@ -0,0 +1,69 @@
+package erasure
+
+import (
+	"app/internal/cache"
+	"app/internal/logger"
+	"app/internal/model"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Store обезличивает доставки субъекта и пишет запись аудита.
+type Store interface {
+	EraseCustomer(ctx context.Context, req model.ErasureRequest) (model.ErasureReport, error)
+}
+
+// Service выполняет удаление персональных данных по запросу субъекта:
+// обезличивает доставки в БД и убирает затронутые заказы из кэша.
+type Service struct {
+	store       Store
+	cache       cache.OrderCache
+	invalidator cache.Invalidator
+}
+
+type Option func(*Service)
+
+// WithInvalidator рассылает удаление ключей кэша другим репликам, чтобы
+// они не отдавали стёртые данные до истечения TTL.
+func WithInvalidator(inv cache.Invalidator) Option {
+	return func(s *Service) {
+		s.invalidator = inv
+	}
+}
+
+func New(store Store, c cache.OrderCache, opts ...Option) *Service {
+	s := &Service{store: store, cache: c}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Erase стирает данные и возвращает отчёт с затронутыми заказами. Ошибка
+// рассылки инвалидаций не отменяет удаление: она только логируется.
+func (s *Service) Erase(ctx context.Context, req model.ErasureRequest) (model.ErasureReport, error) {
+	rep, err := s.store.EraseCustomer(ctx, req)
+	if err != nil {
+		return rep, err
+	}
+
+	for _, uid := range rep.OrderUIDs {
+		key := "order:" + uid
+		s.cache.Delete(key)
+		if s.invalidator == nil {
+			continue
+		}
+		if err := s.invalidator.Invalidate(ctx, key); err != nil {
+			logger.Warn(ctx, "cache invalidation publish failed", zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	// Сами email, телефон и customer_id в лог не пишутся.
+	logger.Info(ctx, "customer data erased",
+		zap.Int64("erasure_id", rep.ID),
+		zap.String("requested_by", rep.RequestedBy),
+		zap.Int("orders", len(rep.OrderUIDs)),
+	)
+	return rep, nil
+}
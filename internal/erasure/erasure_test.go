@@ -0,0 +1,60 @@
+package erasure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"app/internal/logger"
+	"app/internal/mocks"
+	"app/internal/model"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeStore struct {
+	rep model.ErasureReport
+	err error
+	got model.ErasureRequest
+}
+
+func (f *fakeStore) EraseCustomer(ctx context.Context, req model.ErasureRequest) (model.ErasureReport, error) {
+	f.got = req
+	return f.rep, f.err
+}
+
+type fakeInvalidator struct {
+	keys []string
+}
+
+func (f *fakeInvalidator) Invalidate(ctx context.Context, key string) error {
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func TestService_Erase_EvictsAffectedOrders(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	store := &fakeStore{rep: model.ErasureReport{ID: 1, OrderUIDs: []string{"uid-1", "uid-2"}}}
+	c := new(mocks.MockCache[string, model.Order])
+	c.On("Delete", "order:uid-1").Return().Once()
+	c.On("Delete", "order:uid-2").Return().Once()
+	inv := &fakeInvalidator{}
+
+	req := model.ErasureRequest{CustomerID: "cust-1", RequestedBy: "admin"}
+	rep, err := New(store, c, WithInvalidator(inv)).Erase(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, store.rep, rep)
+	require.Equal(t, req, store.got)
+	require.Equal(t, []string{"order:uid-1", "order:uid-2"}, inv.keys)
+	c.AssertExpectations(t)
+}
+
+func TestService_Erase_StoreError(t *testing.T) {
+	store := &fakeStore{err: errors.New("db down")}
+	c := new(mocks.MockCache[string, model.Order])
+
+	_, err := New(store, c).Erase(context.Background(), model.ErasureRequest{CustomerID: "cust-1"})
+	require.ErrorIs(t, err, store.err)
+	c.AssertNotCalled(t, "Delete")
+}
@@ -0,0 +1,335 @@
+package feed
+
+import (
+	"app/internal/model"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	DefaultRingSize       = 1000
+	DefaultBuffer         = 64
+	DefaultMaxSubscribers = 1000
+)
+
+// SlowPolicy — что делать с подписчиком, который не успевает читать.
+type SlowPolicy string
+
+const (
+	// Drop пропускает события, не поместившиеся в буфер подписчика.
+	Drop SlowPolicy = "drop"
+	// Disconnect отключает подписчика; он переподключится с Last-Event-ID
+	// и дочитает пропущенное из кольца.
+	Disconnect SlowPolicy = "disconnect"
+)
+
+// Причины отключения подписчика хабом.
+const (
+	ReasonSlow     = "slow"
+	ReasonShutdown = "shutdown"
+)
+
+var (
+	ErrTooManySubscribers = errors.New("feed: too many subscribers")
+	ErrClosed             = errors.New("feed: hub is closed")
+)
+
+// Event — новый заказ в ленте. ID растёт монотонно в пределах эпохи: процесса
+// или, с WithEpoch, общего источника вроде топика ленты.
+type Event struct {
+	ID    string
+	Order model.Order
+
+	seq uint64
+}
+
+// Filter отбирает заказы ленты; пустое поле совпадает с любым значением.
+type Filter struct {
+	DeliveryService string
+	CustomerID      string
+	Region          string
+}
+
+func (f Filter) Match(o model.Order) bool {
+	return (f.DeliveryService == "" || f.DeliveryService == o.DeliveryService) &&
+		(f.CustomerID == "" || f.CustomerID == o.CustomerID) &&
+		(f.Region == "" || f.Region == o.Delivery.Region)
+}
+
+// Subscriber получает события из C, пока хаб не закроет Done (медленный
+// подписчик или shutdown; причина — в Reason).
+type Subscriber struct {
+	// Backlog — события из кольца после Last-Event-ID, их нужно отправить
+	// до чтения C.
+	Backlog []Event
+	// Gap — часть событий после Last-Event-ID уже вытеснена из кольца или
+	// ID выдан другим процессом.
+	Gap bool
+
+	C <-chan Event
+
+	ch        chan Event
+	after     uint64
+	done      chan struct{}
+	reason    string
+	filter    Filter
+	transport string
+}
+
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Reason — почему хаб отключил подписчика; читать после закрытия Done.
+func (s *Subscriber) Reason() string {
+	return s.reason
+}
+
+// Hub раздаёт новые заказы подписчикам ленты и хранит последние события в
+// кольце для продолжения по Last-Event-ID. Публикация не блокируется на
+// подписчиках: медленные теряют события или отключаются по SlowPolicy.
+type Hub struct {
+	mu     sync.Mutex
+	ring   []Event
+	head   int
+	size   int
+	seq    uint64
+	epoch  string
+	subs   map[*Subscriber]struct{}
+	closed bool
+
+	buffer  int
+	maxSubs int
+	policy  SlowPolicy
+
+	subscribers metric.Int64UpDownCounter
+	published   metric.Int64Counter
+	dropped     metric.Int64Counter
+	disconnects metric.Int64Counter
+	resumes     metric.Int64Counter
+}
+
+type Option func(*Hub)
+
+// WithRingSize задаёт, сколько последних событий доступно для продолжения.
+func WithRingSize(n int) Option {
+	return func(h *Hub) { h.ring = make([]Event, n) }
+}
+
+// WithBuffer задаёт буфер событий подписчика.
+func WithBuffer(n int) Option {
+	return func(h *Hub) { h.buffer = n }
+}
+
+// WithMaxSubscribers ограничивает число одновременных подписчиков.
+func WithMaxSubscribers(n int) Option {
+	return func(h *Hub) { h.maxSubs = n }
+}
+
+func WithSlowPolicy(p SlowPolicy) Option {
+	return func(h *Hub) { h.policy = p }
+}
+
+// WithEpoch задаёт эпоху ID событий вместо случайной эпохи процесса. Хабы с
+// одной эпохой, которые получают события через PublishAt из общего
+// источника, выдают одинаковые ID, и Last-Event-ID работает на любой реплике.
+// Эпоха не должна содержать «-».
+func WithEpoch(epoch string) Option {
+	return func(h *Hub) { h.epoch = epoch }
+}
+
+func NewHub(opts ...Option) *Hub {
+	h := &Hub{
+		ring:    make([]Event, DefaultRingSize),
+		subs:    make(map[*Subscriber]struct{}),
+		buffer:  DefaultBuffer,
+		maxSubs: DefaultMaxSubscribers,
+		policy:  Disconnect,
+		// epoch отличает ID этого процесса от ID до рестарта.
+		epoch: strconv.FormatInt(time.Now().UnixMilli(), 36),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	m := otel.Meter("app/feed")
+	h.subscribers = upDownCounter(m, "feed_subscribers")
+	h.published = counter(m, "feed_events_published_total")
+	h.dropped = counter(m, "feed_events_dropped_total")
+	h.disconnects = counter(m, "feed_disconnects_total")
+	h.resumes = counter(m, "feed_resumes_total")
+	return h
+}
+
+// Publish добавляет заказ в кольцо и рассылает подписчикам, чей фильтр
+// совпал. Вызывается после успешной записи заказа.
+func (h *Hub) Publish(ctx context.Context, o model.Order) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.publish(ctx, h.seq+1, o)
+}
+
+// PublishAt публикует заказ с номером seq из общего источника (например,
+// смещение в топике ленты плюс один). Номера не больше уже опубликованных
+// пропускаются: это повтор после переподключения к источнику.
+func (h *Hub) PublishAt(ctx context.Context, seq uint64, o model.Order) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if seq <= h.seq {
+		return
+	}
+	h.publish(ctx, seq, o)
+}
+
+// publish вызывается под h.mu.
+func (h *Hub) publish(ctx context.Context, seq uint64, o model.Order) {
+	if h.closed {
+		return
+	}
+
+	h.seq = seq
+	e := Event{ID: h.epoch + "-" + strconv.FormatUint(seq, 10), Order: o, seq: seq}
+	if len(h.ring) > 0 {
+		h.ring[(h.head+h.size)%len(h.ring)] = e
+		if h.size < len(h.ring) {
+			h.size++
+		} else {
+			h.head = (h.head + 1) % len(h.ring)
+		}
+	}
+	h.published.Add(ctx, 1)
+
+	for s := range h.subs {
+		if seq <= s.after || !s.filter.Match(o) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+			continue
+		default:
+		}
+		if h.policy == Drop {
+			h.dropped.Add(ctx, 1, metric.WithAttributes(attribute.String("transport", s.transport)))
+			continue
+		}
+		h.remove(ctx, s, ReasonSlow)
+	}
+}
+
+// Subscribe регистрирует подписчика. Если задан lastEventID, события кольца
+// после него попадают в Backlog; регистрация и снимок кольца атомарны,
+// поэтому между Backlog и C нет ни пропусков, ни повторов. transport
+// (sse, ws) попадает в атрибуты метрик.
+func (h *Hub) Subscribe(ctx context.Context, f Filter, lastEventID, transport string) (*Subscriber, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrClosed
+	}
+	if len(h.subs) >= h.maxSubs {
+		return nil, ErrTooManySubscribers
+	}
+
+	ch := make(chan Event, h.buffer)
+	s := &Subscriber{C: ch, ch: ch, done: make(chan struct{}), filter: f, transport: transport}
+	if lastEventID != "" {
+		s.Backlog, s.after, s.Gap = h.since(lastEventID, f)
+		h.resumes.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("transport", transport),
+			attribute.Bool("gap", s.Gap),
+		))
+	}
+	h.subs[s] = struct{}{}
+	h.subscribers.Add(ctx, 1, metric.WithAttributes(attribute.String("transport", transport)))
+	return s, nil
+}
+
+// Unsubscribe снимает подписку; повторный вызов ничего не делает.
+func (h *Hub) Unsubscribe(ctx context.Context, s *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.subs[s]; !ok {
+		return
+	}
+	delete(h.subs, s)
+	h.subscribers.Add(ctx, -1, metric.WithAttributes(attribute.String("transport", s.transport)))
+}
+
+// Close отключает всех подписчиков, чтобы открытые потоки не держали
+// shutdown HTTP-сервера.
+func (h *Hub) Close(ctx context.Context) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for s := range h.subs {
+		h.remove(ctx, s, ReasonShutdown)
+	}
+	return nil
+}
+
+// remove отключает подписчика; вызывается под h.mu.
+func (h *Hub) remove(ctx context.Context, s *Subscriber, reason string) {
+	delete(h.subs, s)
+	s.reason = reason
+	close(s.done)
+	h.subscribers.Add(ctx, -1, metric.WithAttributes(attribute.String("transport", s.transport)))
+	h.disconnects.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("transport", s.transport),
+		attribute.String("reason", reason),
+	))
+}
+
+// since возвращает события кольца после id, подходящие под фильтр, и номер
+// последнего события, известного клиенту. Незнакомый ID (другой процесс,
+// мусор) — разрыв: отдаётся всё кольцо. ID впереди хаба (реплика ещё не
+// дочитала общий источник) разрывом не считается: события до него клиенту
+// не отправляются.
+func (h *Hub) since(id string, f Filter) ([]Event, uint64, bool) {
+	var after uint64
+	gap := true
+	if epoch, seq, ok := strings.Cut(id, "-"); ok && epoch == h.epoch {
+		if n, err := strconv.ParseUint(seq, 10, 64); err == nil {
+			after, gap = n, false
+		}
+	}
+
+	var out []Event
+	for i := 0; i < h.size; i++ {
+		e := h.ring[(h.head+i)%len(h.ring)]
+		if i == 0 && e.seq > after+1 {
+			gap = true
+		}
+		if e.seq > after && f.Match(e.Order) {
+			out = append(out, e)
+		}
+	}
+	if h.size == 0 && after < h.seq {
+		gap = true
+	}
+	return out, after, gap
+}
+
+func counter(m metric.Meter, name string) metric.Int64Counter {
+	c, err := m.Int64Counter(name)
+	if err != nil {
+		c, _ = noop.NewMeterProvider().Meter("noop").Int64Counter(name)
+	}
+	return c
+}
+
+func upDownCounter(m metric.Meter, name string) metric.Int64UpDownCounter {
+	c, err := m.Int64UpDownCounter(name)
+	if err != nil {
+		c, _ = noop.NewMeterProvider().Meter("noop").Int64UpDownCounter(name)
+	}
+	return c
+}
@@ -0,0 +1,105 @@
+package feed
+
+import (
+	"app/internal/model"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func order(uid, ds string) model.Order {
+	return model.Order{OrderUUID: uid, DeliveryService: ds, Delivery: model.Delivery{Region: "Kraiot"}}
+}
+
+func uids(events []Event) []string {
+	out := make([]string, len(events))
+	for i, e := range events {
+		out[i] = e.Order.OrderUUID
+	}
+	return out
+}
+
+func TestHub_FilterAndResume(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(WithRingSize(3))
+
+	s, err := h.Subscribe(ctx, Filter{DeliveryService: "meest"}, "", "sse")
+	require.NoError(t, err)
+	require.Empty(t, s.Backlog)
+
+	h.Publish(ctx, order("a", "meest"))
+	h.Publish(ctx, order("b", "dpd"))
+	first := <-s.C
+	require.Equal(t, "a", first.Order.OrderUUID)
+	require.Empty(t, s.C)
+
+	// Продолжение после "a": в кольце есть всё, что было после.
+	h.Publish(ctx, order("c", "meest"))
+	r, err := h.Subscribe(ctx, Filter{}, first.ID, "ws")
+	require.NoError(t, err)
+	require.False(t, r.Gap)
+	require.Equal(t, []string{"b", "c"}, uids(r.Backlog))
+
+	// Кольцо на 3 события: "a" вытеснено, продолжение с него — разрыв.
+	h.Publish(ctx, order("d", "meest"))
+	h.Publish(ctx, order("e", "meest"))
+	g, err := h.Subscribe(ctx, Filter{}, first.ID, "sse")
+	require.NoError(t, err)
+	require.True(t, g.Gap)
+	require.Equal(t, []string{"c", "d", "e"}, uids(g.Backlog))
+
+	// ID другого процесса — разрыв, отдаётся всё кольцо.
+	o, err := h.Subscribe(ctx, Filter{DeliveryService: "meest"}, "zzz-1", "sse")
+	require.NoError(t, err)
+	require.True(t, o.Gap)
+	require.Equal(t, []string{"c", "d", "e"}, uids(o.Backlog))
+
+	h.Unsubscribe(ctx, s)
+	h.Unsubscribe(ctx, s)
+	n := len(s.C)
+	h.Publish(ctx, order("f", "meest"))
+	require.Len(t, s.C, n)
+}
+
+func TestHub_SlowConsumers(t *testing.T) {
+	ctx := context.Background()
+
+	drop := NewHub(WithBuffer(1), WithSlowPolicy(Drop))
+	s, err := drop.Subscribe(ctx, Filter{}, "", "sse")
+	require.NoError(t, err)
+	drop.Publish(ctx, order("a", "meest"))
+	drop.Publish(ctx, order("b", "meest"))
+	require.Equal(t, "a", (<-s.C).Order.OrderUUID)
+	drop.Publish(ctx, order("c", "meest"))
+	require.Equal(t, "c", (<-s.C).Order.OrderUUID)
+	select {
+	case <-s.Done():
+		t.Fatal("drop policy must not disconnect")
+	default:
+	}
+
+	disc := NewHub(WithBuffer(1), WithMaxSubscribers(2))
+	slow, err := disc.Subscribe(ctx, Filter{}, "", "ws")
+	require.NoError(t, err)
+	fast, err := disc.Subscribe(ctx, Filter{}, "", "ws")
+	require.NoError(t, err)
+	_, err = disc.Subscribe(ctx, Filter{}, "", "ws")
+	require.ErrorIs(t, err, ErrTooManySubscribers)
+
+	disc.Publish(ctx, order("a", "meest"))
+	<-fast.C
+	disc.Publish(ctx, order("b", "meest"))
+	<-slow.Done()
+	require.Equal(t, ReasonSlow, slow.Reason())
+
+	// Место отключённого подписчика освободилось.
+	_, err = disc.Subscribe(ctx, Filter{}, "", "ws")
+	require.NoError(t, err)
+
+	require.NoError(t, disc.Close(ctx))
+	<-fast.Done()
+	require.Equal(t, ReasonShutdown, fast.Reason())
+	_, err = disc.Subscribe(ctx, Filter{}, "", "ws")
+	require.ErrorIs(t, err, ErrClosed)
+}
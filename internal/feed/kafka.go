@@ -0,0 +1,216 @@
+package feed
+
+import (
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/otelx"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.uber.org/zap"
+)
+
+const (
+	publishTimeout = 2 * time.Second
+	retryDelay     = time.Second
+)
+
+// EnsureTopic создаёт топик ленты, если его ещё нет. Партиция одна: смещение
+// в ней — общий для всех реплик порядок и номер события. Хранится топик
+// retention: ленте нужен только хвост, а в сообщениях есть персональные данные.
+func EnsureTopic(ctx context.Context, brokers []string, topic string, replicationFactor int, retention time.Duration) error {
+	if len(brokers) == 0 {
+		return errors.New("kafka brokers is empty")
+	}
+
+	conn, err := kafka.DialContext(ctx, "tcp", brokers[0])
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	controller, err := conn.Controller()
+	if err != nil {
+		return err
+	}
+
+	cc, err := kafka.DialContext(ctx, "tcp", net.JoinHostPort(controller.Host, strconv.Itoa(controller.Port)))
+	if err != nil {
+		return err
+	}
+	defer cc.Close()
+
+	err = cc.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     1,
+		ReplicationFactor: replicationFactor,
+		ConfigEntries: []kafka.ConfigEntry{
+			{ConfigName: "retention.ms", ConfigValue: strconv.FormatInt(retention.Milliseconds(), 10)},
+		},
+	})
+	if err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return err
+	}
+	return nil
+}
+
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// TopicPublisher пишет записанные заказы в топик ленты; его читают Relay
+// всех реплик API. Ошибка записи не откатывает заказ: он уже сохранён, и
+// лента его пропустит.
+type TopicPublisher struct {
+	writer Writer
+}
+
+func NewTopicPublisher(w Writer) *TopicPublisher {
+	return &TopicPublisher{writer: w}
+}
+
+func (p *TopicPublisher) Publish(ctx context.Context, o model.Order) {
+	b, err := json.Marshal(o)
+	if err != nil {
+		logger.Warn(ctx, "marshal feed event failed", zap.String("order_uid", o.OrderUUID), zap.Error(err))
+		return
+	}
+
+	msg := kafka.Message{Key: []byte(o.OrderUUID), Value: b}
+	otelx.InjectKafka(ctx, &msg)
+
+	writeCtx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	if err := p.writer.WriteMessages(writeCtx, msg); err != nil {
+		logger.Warn(ctx, "publish feed event failed", zap.String("order_uid", o.OrderUUID), zap.Error(err))
+	}
+}
+
+type Reader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+}
+
+// TailReader читает партицию топика ленты без consumer group, начиная с
+// последних tail сообщений: так кольцо хаба после старта совпадает с
+// кольцами других реплик.
+type TailReader struct {
+	brokers []string
+	topic   string
+	tail    int64
+
+	mu     sync.Mutex
+	reader *kafka.Reader
+	closed bool
+}
+
+// NewTailReader не обращается к брокеру: партиция открывается при первом
+// ReadMessage, поэтому недоступная Kafka не мешает старту процесса.
+func NewTailReader(brokers []string, topic string, tail int) *TailReader {
+	return &TailReader{brokers: brokers, topic: topic, tail: int64(tail)}
+}
+
+func (t *TailReader) start(ctx context.Context) (*kafka.Reader, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.reader != nil {
+		return t.reader, nil
+	}
+	if t.closed {
+		return nil, errors.New("feed reader is closed")
+	}
+	if len(t.brokers) == 0 {
+		return nil, errors.New("kafka brokers is empty")
+	}
+
+	conn, err := kafka.DialLeader(ctx, "tcp", t.brokers[0], t.topic, 0)
+	if err != nil {
+		return nil, err
+	}
+	first, last, err := conn.ReadOffsets()
+	conn.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	r := kafka.NewReader(kafka.ReaderConfig{Brokers: t.brokers, Topic: t.topic, Partition: 0})
+	if err := r.SetOffset(max(first, last-t.tail)); err != nil {
+		_ = r.Close()
+		return nil, err
+	}
+	t.reader = r
+	return r, nil
+}
+
+func (t *TailReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	r, err := t.start(ctx)
+	if err != nil {
+		return kafka.Message{}, err
+	}
+	return r.ReadMessage(ctx)
+}
+
+func (t *TailReader) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.reader == nil {
+		return nil
+	}
+	return t.reader.Close()
+}
+
+// Relay переносит события из топика ленты в хаб. Номер события — смещение
+// плюс один, поэтому хабы всех реплик с общей эпохой выдают одинаковые ID.
+type Relay struct {
+	reader Reader
+	hub    *Hub
+}
+
+func NewRelay(r Reader, h *Hub) *Relay {
+	return &Relay{reader: r, hub: h}
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	logger.Info(ctx, "feed relay started")
+
+	for {
+		msg, err := r.reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info(ctx, "feed relay stopped")
+				return ctx.Err()
+			}
+			logger.Warn(ctx, "feed read failed", zap.Error(err))
+			if err := sleepCtx(ctx, retryDelay); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var o model.Order
+		if err := json.Unmarshal(msg.Value, &o); err != nil {
+			logger.Warn(ctx, "bad feed event", zap.Int64("offset", msg.Offset), zap.Error(err))
+			continue
+		}
+		r.hub.PublishAt(ctx, uint64(msg.Offset)+1, o)
+	}
+}
+
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
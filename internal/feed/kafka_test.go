@@ -0,0 +1,147 @@
+package feed
+
+import (
+	"app/internal/logger"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTopic — однопартиционный топик в памяти: смещение — индекс сообщения.
+type fakeTopic struct {
+	mu   sync.Mutex
+	msgs []kafka.Message
+}
+
+func (f *fakeTopic) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range msgs {
+		m.Offset = int64(len(f.msgs))
+		f.msgs = append(f.msgs, m)
+	}
+	return nil
+}
+
+// reader читает топик с offset; limit ограничивает, сколько сообщений
+// реплика успела прочитать.
+func (f *fakeTopic) reader(offset int64) *fakeReader {
+	return &fakeReader{topic: f, next: offset, limit: -1}
+}
+
+type fakeReader struct {
+	topic *fakeTopic
+
+	mu    sync.Mutex
+	next  int64
+	limit int64
+}
+
+func (r *fakeReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.mu.Lock()
+		r.topic.mu.Lock()
+		if r.next < int64(len(r.topic.msgs)) && (r.limit < 0 || r.next < r.limit) {
+			m := r.topic.msgs[r.next]
+			r.next++
+			r.topic.mu.Unlock()
+			r.mu.Unlock()
+			return m, nil
+		}
+		r.topic.mu.Unlock()
+		r.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func (r *fakeReader) setLimit(n int64) {
+	r.mu.Lock()
+	r.limit = n
+	r.mu.Unlock()
+}
+
+func (h *Hub) lastSeq() uint64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.seq
+}
+
+// Ingester пишет заказы в топик, две реплики API читают его: ID событий
+// совпадают, и Last-Event-ID одной реплики продолжает поток на другой.
+func TestRelay_SplitRoles(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	topic := &fakeTopic{}
+	ingester := NewTopicPublisher(topic)
+
+	api1 := NewHub(WithEpoch("kafka"))
+	api2 := NewHub(WithEpoch("kafka"))
+	r2 := topic.reader(0)
+	go func() { _ = NewRelay(topic.reader(0), api1).Run(ctx) }()
+	go func() { _ = NewRelay(r2, api2).Run(ctx) }()
+
+	s1, err := api1.Subscribe(ctx, Filter{}, "", "sse")
+	require.NoError(t, err)
+
+	ingester.Publish(ctx, order("a", "meest"))
+	ingester.Publish(ctx, order("b", "meest"))
+	a, b := <-s1.C, <-s1.C
+	require.Equal(t, "a", a.Order.OrderUUID)
+	require.Equal(t, "kafka-1", a.ID)
+	require.Equal(t, "kafka-2", b.ID)
+	require.Eventually(t, func() bool { return api2.lastSeq() == 2 }, time.Second, time.Millisecond)
+
+	// Клиент переподключается к другой реплике.
+	ingester.Publish(ctx, order("c", "meest"))
+	require.Eventually(t, func() bool { return api2.lastSeq() == 3 }, time.Second, time.Millisecond)
+	s2, err := api2.Subscribe(ctx, Filter{}, a.ID, "sse")
+	require.NoError(t, err)
+	require.False(t, s2.Gap)
+	require.Equal(t, []string{"b", "c"}, uids(s2.Backlog))
+	require.Equal(t, "kafka-3", s2.Backlog[1].ID)
+
+	// Реплика отстаёт: ID клиента впереди неё — не разрыв, и уже
+	// полученные клиентом события не повторяются.
+	r2.setLimit(3)
+	ingester.Publish(ctx, order("d", "meest"))
+	ingester.Publish(ctx, order("e", "meest"))
+	require.Equal(t, "c", (<-s1.C).Order.OrderUUID)
+	d := <-s1.C
+	require.Equal(t, "d", d.Order.OrderUUID)
+
+	s3, err := api2.Subscribe(ctx, Filter{}, d.ID, "ws")
+	require.NoError(t, err)
+	require.False(t, s3.Gap)
+	require.Empty(t, s3.Backlog)
+
+	r2.setLimit(-1)
+	e := <-s3.C
+	require.Equal(t, "e", e.Order.OrderUUID)
+	require.Equal(t, "kafka-5", e.ID)
+}
+
+// Повтор сообщения после переподключения к топику не публикуется дважды.
+func TestHub_PublishAtSkipsRepeats(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(WithEpoch("kafka"))
+	s, err := h.Subscribe(ctx, Filter{}, "", "sse")
+	require.NoError(t, err)
+
+	h.PublishAt(ctx, 5, order("a", "meest"))
+	h.PublishAt(ctx, 5, order("a", "meest"))
+	h.PublishAt(ctx, 7, order("b", "meest"))
+	require.Equal(t, "kafka-5", (<-s.C).ID)
+	require.Equal(t, "kafka-7", (<-s.C).ID)
+	require.Empty(t, s.C)
+}
@@ -0,0 +1,234 @@
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Префикс зашифрованного значения в текстовой колонке: по нему видно
+// формат, если его придётся менять.
+const valuePrefix = "v1:"
+
+var (
+	ErrUnknownKey = errors.New("fieldcrypt: unknown key id")
+	ErrDecrypt    = errors.New("fieldcrypt: decryption failed")
+)
+
+// Cipher реализует envelope encryption: каждая строка шифруется своим
+// случайным ключом данных (DEK, AES-256-GCM), а DEK хранится рядом со
+// строкой, зашифрованный ключом шифрования ключей (KEK) с идентификатором
+// keyID. Ротация KEK перешифровывает только DEK, а не данные.
+type Cipher struct {
+	keks     map[string]cipher.AEAD
+	activeID string
+	bidxKey  []byte
+}
+
+// New принимает KEK по идентификаторам (по 32 байта), идентификатор ключа
+// для новых строк и ключ blind index (не меньше 32 байт).
+func New(keks map[string][]byte, activeID string, bidxKey []byte) (*Cipher, error) {
+	if _, ok := keks[activeID]; !ok {
+		return nil, fmt.Errorf("%w: active key %q", ErrUnknownKey, activeID)
+	}
+	if len(bidxKey) < 32 {
+		return nil, fmt.Errorf("fieldcrypt: blind index key must be at least 32 bytes, got %d", len(bidxKey))
+	}
+
+	c := &Cipher{keks: make(map[string]cipher.AEAD, len(keks)), activeID: activeID, bidxKey: bidxKey}
+	for id, key := range keks {
+		if len(key) != 32 {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be 32 bytes, got %d", id, len(key))
+		}
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, err
+		}
+		c.keks[id] = aead
+	}
+	return c, nil
+}
+
+// ParseKeys разбирает список "id=base64" через запятую или перевод строки;
+// пустые строки и строки с # пропускаются.
+func ParseKeys(s string) (map[string][]byte, error) {
+	keys := map[string][]byte{}
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		id, b64, ok := strings.Cut(entry, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("fieldcrypt: bad key entry %q: want id=base64", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+		if err != nil {
+			return nil, fmt.Errorf("fieldcrypt: key %q: %w", id, err)
+		}
+		if _, dup := keys[id]; dup {
+			return nil, fmt.Errorf("fieldcrypt: duplicate key %q", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// KeyIDs возвращает идентификаторы загруженных KEK.
+func (c *Cipher) KeyIDs() []string {
+	ids := make([]string, 0, len(c.keks))
+	for id := range c.keks {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ActiveKeyID — KEK, которым шифруются новые строки.
+func (c *Cipher) ActiveKeyID() string {
+	return c.activeID
+}
+
+// DataKey — ключ данных одной строки.
+type DataKey struct {
+	KeyID   string
+	Wrapped []byte
+	aead    cipher.AEAD
+}
+
+// NewDataKey создаёт DEK и шифрует его активным KEK.
+func (c *Cipher) NewDataKey() (*DataKey, error) {
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := seal(c.keks[c.activeID], dek, []byte(c.activeID))
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{KeyID: c.activeID, Wrapped: wrapped, aead: aead}, nil
+}
+
+// OpenDataKey расшифровывает DEK строки KEK'ом keyID.
+func (c *Cipher) OpenDataKey(keyID string, wrapped []byte) (*DataKey, error) {
+	dek, err := c.unwrap(keyID, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+	return &DataKey{KeyID: keyID, Wrapped: wrapped, aead: aead}, nil
+}
+
+// Rewrap перешифровывает DEK активным KEK. Данные строки не меняются.
+func (c *Cipher) Rewrap(keyID string, wrapped []byte) (string, []byte, error) {
+	dek, err := c.unwrap(keyID, wrapped)
+	if err != nil {
+		return "", nil, err
+	}
+	out, err := seal(c.keks[c.activeID], dek, []byte(c.activeID))
+	return c.activeID, out, err
+}
+
+func (c *Cipher) unwrap(keyID string, wrapped []byte) ([]byte, error) {
+	kek, ok := c.keks[keyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, keyID)
+	}
+	return open(kek, wrapped, []byte(keyID))
+}
+
+// Encrypt шифрует значение. aad привязывает шифротекст к месту хранения
+// (например, order_uid и колонке): перенесённое в другую строку значение
+// не расшифруется.
+func (k *DataKey) Encrypt(value, aad string) (string, error) {
+	ct, err := seal(k.aead, []byte(value), []byte(aad))
+	if err != nil {
+		return "", err
+	}
+	return valuePrefix + base64.RawStdEncoding.EncodeToString(ct), nil
+}
+
+func (k *DataKey) Decrypt(value, aad string) (string, error) {
+	b64, ok := strings.CutPrefix(value, valuePrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: unknown value format", ErrDecrypt)
+	}
+	ct, err := base64.RawStdEncoding.DecodeString(b64)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	pt, err := open(k.aead, ct, []byte(aad))
+	return string(pt), err
+}
+
+// BlindIndex — детерминированный HMAC-SHA256 нормализованного значения:
+// по нему ищут строку, не расшифровывая колонку. kind разделяет индексы
+// разных полей, чтобы одинаковые значения в них не совпадали.
+func (c *Cipher) BlindIndex(kind, normalized string) []byte {
+	mac := hmac.New(sha256.New, c.bidxKey)
+	mac.Write([]byte(kind))
+	mac.Write([]byte{0})
+	mac.Write([]byte(normalized))
+	return mac.Sum(nil)
+}
+
+// NormalizeEmail приводит email к виду для blind index.
+func NormalizeEmail(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// NormalizePhone оставляет только цифры: "+7 (999) 123-45-67" и "79991234567"
+// дают один индекс.
+func NormalizePhone(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal возвращает nonce || ciphertext.
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plaintext)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, data, aad []byte) ([]byte, error) {
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("%w: short ciphertext", ErrDecrypt)
+	}
+	nonce, ct := data[:aead.NonceSize()], data[aead.NonceSize():]
+	pt, err := aead.Open(nil, nonce, ct, aad)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrDecrypt, err)
+	}
+	return pt, nil
+}
@@ -0,0 +1,139 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func newTestCipher(t *testing.T, active string) *Cipher {
+	t.Helper()
+
+	c, err := New(map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, active, testKey(9))
+	require.NoError(t, err)
+	return c
+}
+
+func TestDataKey_RoundTrip(t *testing.T) {
+	c := newTestCipher(t, "k1")
+
+	dk, err := c.NewDataKey()
+	require.NoError(t, err)
+	require.Equal(t, "k1", dk.KeyID)
+
+	enc, err := dk.Encrypt("+79991234567", "uid-1/phone")
+	require.NoError(t, err)
+	require.NotContains(t, enc, "79991234567")
+
+	opened, err := c.OpenDataKey(dk.KeyID, dk.Wrapped)
+	require.NoError(t, err)
+	got, err := opened.Decrypt(enc, "uid-1/phone")
+	require.NoError(t, err)
+	require.Equal(t, "+79991234567", got)
+
+	// Значение, перенесённое в другую строку или колонку, не расшифровывается.
+	_, err = opened.Decrypt(enc, "uid-2/phone")
+	require.ErrorIs(t, err, ErrDecrypt)
+	_, err = opened.Decrypt("plaintext", "uid-1/phone")
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestCipher_Rewrap(t *testing.T) {
+	old := newTestCipher(t, "k1")
+	dk, err := old.NewDataKey()
+	require.NoError(t, err)
+	enc, err := dk.Encrypt("secret", "aad")
+	require.NoError(t, err)
+
+	rotated := newTestCipher(t, "k2")
+	keyID, wrapped, err := rotated.Rewrap(dk.KeyID, dk.Wrapped)
+	require.NoError(t, err)
+	require.Equal(t, "k2", keyID)
+
+	// После ротации строка читается без k1.
+	onlyNew, err := New(map[string][]byte{"k2": testKey(2)}, "k2", testKey(9))
+	require.NoError(t, err)
+	opened, err := onlyNew.OpenDataKey(keyID, wrapped)
+	require.NoError(t, err)
+	got, err := opened.Decrypt(enc, "aad")
+	require.NoError(t, err)
+	require.Equal(t, "secret", got)
+
+	_, err = onlyNew.OpenDataKey("k1", dk.Wrapped)
+	require.True(t, errors.Is(err, ErrUnknownKey))
+	// DEK, обёрнутый под одним id, не открывается как обёрнутый под другим.
+	_, err = rotated.OpenDataKey("k2", dk.Wrapped)
+	require.ErrorIs(t, err, ErrDecrypt)
+}
+
+func TestCipher_BlindIndex(t *testing.T) {
+	c := newTestCipher(t, "k1")
+
+	require.Equal(t,
+		c.BlindIndex("phone", NormalizePhone("+7 (999) 123-45-67")),
+		c.BlindIndex("phone", NormalizePhone("79991234567")))
+	require.Equal(t,
+		c.BlindIndex("email", NormalizeEmail(" Ivan@Example.com")),
+		c.BlindIndex("email", NormalizeEmail("ivan@example.com")))
+	require.NotEqual(t, c.BlindIndex("email", "x"), c.BlindIndex("phone", "x"))
+
+	other, err := New(map[string][]byte{"k1": testKey(1)}, "k1", testKey(8))
+	require.NoError(t, err)
+	require.NotEqual(t, c.BlindIndex("email", "x"), other.BlindIndex("email", "x"))
+}
+
+func TestParseKeys(t *testing.T) {
+	k1 := base64.StdEncoding.EncodeToString(testKey(1))
+	k2 := base64.StdEncoding.EncodeToString(testKey(2))
+
+	keys, err := ParseKeys("# ротация 2026-10\nk1=" + k1 + "\n\nk2 = " + k2 + "\n")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]byte{"k1": testKey(1), "k2": testKey(2)}, keys)
+
+	keys, err = ParseKeys("k1=" + k1 + ",k2=" + k2)
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+
+	for _, bad := range []string{"k1", "=abc", "k1=!!!", "k1=" + k1 + ",k1=" + k2} {
+		_, err := ParseKeys(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestNew_Validates(t *testing.T) {
+	_, err := New(map[string][]byte{"k1": testKey(1)}, "k2", testKey(9))
+	require.ErrorIs(t, err, ErrUnknownKey)
+	_, err = New(map[string][]byte{"k1": []byte("short")}, "k1", testKey(9))
+	require.Error(t, err)
+	_, err = New(map[string][]byte{"k1": testKey(1)}, "k1", []byte("short"))
+	require.Error(t, err)
+}
+
+type fakeReencrypter struct {
+	left  int
+	calls int
+}
+
+func (f *fakeReencrypter) ReencryptDeliveries(_ context.Context, batch int) (int, error) {
+	f.calls++
+	n := min(batch, f.left)
+	f.left -= n
+	return n, nil
+}
+
+func TestReencrypt_DrainsInBatches(t *testing.T) {
+	f := &fakeReencrypter{left: 25}
+
+	n, err := Reencrypt(context.Background(), f, 10)
+	require.NoError(t, err)
+	require.Equal(t, 25, n)
+	require.Equal(t, 3, f.calls)
+}
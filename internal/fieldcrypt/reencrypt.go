@@ -0,0 +1,50 @@
+package fieldcrypt
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Reencrypter доводит не больше batch строк до активного KEK и возвращает
+// число обработанных.
+type Reencrypter interface {
+	ReencryptDeliveries(ctx context.Context, batch int) (int, error)
+}
+
+// Reencrypt вызывает r пачками, пока строки на перешифровку не кончатся.
+// Возвращает общее число обработанных строк.
+func Reencrypt(ctx context.Context, r Reencrypter, batch int) (int, error) {
+	total := 0
+	for {
+		n, err := r.ReencryptDeliveries(ctx, batch)
+		total += n
+		if err != nil || n < batch {
+			return total, err
+		}
+	}
+}
+
+// RunReencrypt запускает Reencrypt каждые interval до отмены ctx: так
+// после смены active_key_id или включения шифрования все строки со
+// временем переходят на активный KEK.
+func RunReencrypt(ctx context.Context, r Reencrypter, batch int, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		n, err := Reencrypt(ctx, r, batch)
+		if n > 0 {
+			log.Printf("[fieldcrypt] reencrypted %d deliveries", n)
+		}
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[fieldcrypt] reencrypt failed: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package graphql
+
+import (
+	gql "app/internal/api/graphql"
+	"app/internal/logger"
+	"app/internal/pii"
+	"app/internal/service"
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 5000
+	// MaxBatch — наибольшее число UID в orders(uids:), как в GET /orders.
+	MaxBatch = 100
+	// MaxLimit — наибольший limit в orderList.
+	MaxLimit = 100
+)
+
+type options struct {
+	maxDepth      int
+	maxComplexity int
+	introspection bool
+}
+
+type Option func(*options)
+
+// WithMaxDepth ограничивает вложенность полей запроса.
+func WithMaxDepth(n int) Option {
+	return func(o *options) { o.maxDepth = n }
+}
+
+// WithMaxComplexity ограничивает сложность запроса: каждое поле стоит 1,
+// списки orders и orderList умножают стоимость вложенных полей на число
+// UID и limit.
+func WithMaxComplexity(n int) Option {
+	return func(o *options) { o.maxComplexity = n }
+}
+
+// WithIntrospection разрешает запросы __schema и __type.
+func WithIntrospection(on bool) Option {
+	return func(o *options) { o.introspection = on }
+}
+
+// NewHandler возвращает HTTP-обработчик GraphQL (GET и POST). Аутентификация —
+// снаружи: резолверы берут роль вызывающего из auth.Identity в контексте.
+func NewHandler(svc service.Service, policy pii.Policy, opts ...Option) http.Handler {
+	o := options{maxDepth: DefaultMaxDepth, maxComplexity: DefaultMaxComplexity, introspection: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cfg := gql.Config{Resolvers: &Resolver{svc: svc, pii: policy}}
+	cfg.Complexity.Query.Orders = func(child int, uids []string) int {
+		return 1 + child*len(uids)
+	}
+	cfg.Complexity.Query.OrderList = func(child int, _ *gql.OrderFilter, limit int, _ *string) int {
+		return 1 + child*max(limit, 1)
+	}
+
+	srv := handler.New(gql.NewExecutableSchema(cfg))
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	if o.introspection {
+		srv.Use(extension.Introspection{})
+	}
+	srv.Use(depthLimit(o.maxDepth))
+	srv.Use(extension.FixedComplexityLimit(o.maxComplexity))
+	srv.Use(tracing{})
+	srv.SetErrorPresenter(presentError)
+
+	return withLoader(svc, srv)
+}
+
+// presentError отдаёт клиенту ошибки запроса как есть, а внутренние
+// (БД, кэш) — без подробностей.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	var gerr *gqlerror.Error
+	if errors.As(err, &gerr) && gerr.Err == nil {
+		return graphql.DefaultErrorPresenter(ctx, err)
+	}
+	logger.Error(ctx, "graphql resolver failed", zap.Error(err))
+	return &gqlerror.Error{Message: "internal error", Path: graphql.GetPath(ctx)}
+}
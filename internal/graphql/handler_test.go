@@ -0,0 +1,185 @@
+package graphql
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// orderService — service.Service поверх map; запоминает вызовы GetMany.
+type orderService struct {
+	orders map[string]model.Order
+
+	mu    sync.Mutex
+	calls [][]string
+}
+
+func (s *orderService) ProcessOrder(context.Context, model.Order) error { return nil }
+
+func (s *orderService) Get(_ context.Context, uid string) (model.Order, error) {
+	o, ok := s.orders[uid]
+	if !ok {
+		return model.Order{}, model.ErrNotFound
+	}
+	return o, nil
+}
+
+func (s *orderService) GetMany(ctx context.Context, uids []string) ([]model.Order, error) {
+	s.mu.Lock()
+	s.calls = append(s.calls, uids)
+	s.mu.Unlock()
+
+	var out []model.Order
+	for _, uid := range uids {
+		if o, err := s.Get(ctx, uid); err == nil {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+// List отдаёт заказы по убыванию (date_created, uid) после курсора.
+func (s *orderService) List(_ context.Context, f model.OrderFilter) ([]model.Order, error) {
+	var all []model.Order
+	for _, o := range s.orders {
+		if f.DeliveryService != "" && o.DeliveryService != f.DeliveryService {
+			continue
+		}
+		if c := f.After; c != nil && !(o.DateCreated.Before(c.DateCreated) ||
+			o.DateCreated.Equal(c.DateCreated) && o.OrderUUID < c.OrderUID) {
+			continue
+		}
+		all = append(all, o)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].DateCreated.Equal(all[j].DateCreated) {
+			return all[i].DateCreated.After(all[j].DateCreated)
+		}
+		return all[i].OrderUUID > all[j].OrderUUID
+	})
+	return all[:min(len(all), f.Limit)], nil
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+func TestHandler(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := &orderService{orders: map[string]model.Order{}}
+	for i, uid := range []string{"a", "b", "c"} {
+		svc.orders[uid] = model.Order{
+			OrderUUID:       uid,
+			DeliveryService: "meest",
+			DateCreated:     base.Add(time.Duration(i) * time.Minute),
+			Delivery:        model.Delivery{Phone: "+79991234567", Region: "Kraiot"},
+			Payment:         model.Payment{Transaction: "tx-" + uid, Amount: 100 * (i + 1)},
+			Items:           []model.Item{{ChrtID: int64(i), Price: 10}},
+		}
+	}
+
+	h := NewHandler(svc, pii.DefaultPolicy, WithMaxDepth(4), WithMaxComplexity(200))
+	do := func(scopes []string, query string) response {
+		t.Helper()
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(auth.WithIdentity(req.Context(), auth.Identity{Scopes: scopes}))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var res response
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res), rec.Body.String())
+		return res
+	}
+
+	// Поля из разных корневых полей и фрагментов — один вызов GetMany.
+	res := do([]string{"orders:read"}, `
+		query {
+			first: order(uid: "a") { ...o }
+			missing: order(uid: "zzz") { orderUid }
+			orders(uids: ["c", "a", "c"]) { orderUid payment { amount transaction } }
+		}
+		fragment o on Order { orderUid delivery { phone region } items { price } }`)
+	require.Empty(t, res.Errors)
+	require.JSONEq(t, `{
+		"first": {"orderUid": "a", "delivery": {"phone": "`+pii.MaskPhone("+79991234567")+`", "region": "Kraiot"}, "items": [{"price": 10}]},
+		"missing": null,
+		"orders": [
+			{"orderUid": "c", "payment": {"amount": 300, "transaction": null}},
+			{"orderUid": "a", "payment": {"amount": 100, "transaction": null}}
+		]
+	}`, string(res.Data))
+	require.Len(t, svc.calls, 1)
+	require.ElementsMatch(t, []string{"a", "zzz", "c"}, svc.calls[0])
+
+	// Роль с orders:pii видит поля без маски.
+	res = do([]string{"orders:read", pii.ScopeFull}, `{ order(uid: "b") { payment { transaction } } }`)
+	require.JSONEq(t, `{"order": {"payment": {"transaction": "tx-b"}}}`, string(res.Data))
+
+	// Страницы orderList по курсору.
+	res = do(nil, `{ orderList(filter: {deliveryService: "meest"}, limit: 2) { orders { orderUid } endCursor } }`)
+	require.Empty(t, res.Errors)
+	var page struct {
+		OrderList struct {
+			Orders    []struct{ OrderUID string } `json:"orders"`
+			EndCursor string                      `json:"endCursor"`
+		} `json:"orderList"`
+	}
+	require.NoError(t, json.Unmarshal(res.Data, &page))
+	require.Len(t, page.OrderList.Orders, 2)
+	require.Equal(t, "c", page.OrderList.Orders[0].OrderUID)
+	res = do(nil, `{ orderList(limit: 2, after: "`+page.OrderList.EndCursor+`") { orders { orderUid } } }`)
+	require.JSONEq(t, `{"orderList": {"orders": [{"orderUid": "a"}]}}`, string(res.Data))
+
+	res = do(nil, `{ orderList(limit: 2, after: "garbage") { endCursor } }`)
+	require.Len(t, res.Errors, 1)
+	require.Contains(t, res.Errors[0].Message, "invalid cursor")
+
+	// Сложность: orderList умножает стоимость вложенных полей на limit.
+	res = do(nil, `{ orderList { orders { orderUid items { price } } } }`)
+	require.Empty(t, res.Errors)
+	res = do(nil, `{ orderList(limit: 100) { orders { orderUid items { price } } } }`)
+	require.Len(t, res.Errors, 1)
+	require.Contains(t, res.Errors[0].Message, "complexity 401, which exceeds the limit of 200")
+}
+
+func TestDepthLimit(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	h := NewHandler(&orderService{}, pii.DefaultPolicy, WithMaxDepth(3))
+	for query, ok := range map[string]bool{
+		`{ order(uid: "a") { delivery { region } } }`:                               true,
+		`{ orderList { orders { delivery { region } } } }`:                          false,
+		`{ orderList { orders { ...o } } } fragment o on Order { items { price } }`: false,
+		`{ __schema { types { fields { type { ofType { ofType { name } } } } } } }`: true,
+		`{ order(uid: "a") { ... on Order { payment { amount } } } }`:               true,
+	} {
+		body, _ := json.Marshal(map[string]string{"query": query})
+		req := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if ok {
+			require.NotContains(t, rec.Body.String(), "exceeds the limit", query)
+		} else {
+			require.Contains(t, rec.Body.String(), "depth 4, which exceeds the limit of 3", query)
+		}
+	}
+}
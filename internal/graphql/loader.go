@@ -0,0 +1,42 @@
+package graphql
+
+import (
+	"app/internal/model"
+	"app/internal/service"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/vikstrous/dataloadgen"
+)
+
+// loaderWait — сколько загрузчик копит UID перед походом в сервис: резолверы
+// одного запроса успевают попасть в одну пачку.
+const loaderWait = 2 * time.Millisecond
+
+type loaderKey struct{}
+
+// withLoader даёт каждому запросу свой загрузчик заказов: order(uid:) из
+// разных полей и фрагментов собираются в один service.GetMany, а тот
+// догружает промахи кэша одним запросом к репозиторию. Загрузчик живёт
+// только в пределах запроса, поэтому не отдаёт устаревших заказов.
+func withLoader(svc service.Service, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := dataloadgen.NewMappedLoader(func(ctx context.Context, uids []string) (map[string]model.Order, error) {
+			orders, err := svc.GetMany(ctx, uids)
+			if err != nil {
+				return nil, err
+			}
+			out := make(map[string]model.Order, len(orders))
+			for _, o := range orders {
+				out[o.OrderUUID] = o
+			}
+			return out, nil
+		}, dataloadgen.WithWait(loaderWait), dataloadgen.WithBatchCapacity(MaxBatch))
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), loaderKey{}, l)))
+	})
+}
+
+func loaderFrom(ctx context.Context) *dataloadgen.Loader[string, model.Order] {
+	return ctx.Value(loaderKey{}).(*dataloadgen.Loader[string, model.Order])
+}
@@ -0,0 +1,161 @@
+package graphql
+
+import (
+	gql "app/internal/api/graphql"
+	"app/internal/auth"
+	"app/internal/converter"
+	"app/internal/model"
+	"app/internal/pii"
+	"app/internal/service"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vikstrous/dataloadgen"
+)
+
+// Resolver реализует корневые поля схемы api/graphql/schema.graphqls.
+// Заказы по UID читаются через загрузчик запроса (см. withLoader).
+type Resolver struct {
+	svc service.Service
+	pii pii.Policy
+}
+
+func (r *Resolver) Query() gql.QueryResolver {
+	return (*queryResolver)(r)
+}
+
+type queryResolver Resolver
+
+func (r *queryResolver) Order(ctx context.Context, uid string) (*gql.Order, error) {
+	o, err := loaderFrom(ctx).Load(ctx, uid)
+	if errors.Is(err, dataloadgen.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return converter.ModelOrderToGraphQL(o, r.view(ctx)), nil
+}
+
+func (r *queryResolver) Orders(ctx context.Context, uids []string) ([]*gql.Order, error) {
+	if len(uids) > MaxBatch {
+		return nil, gqlerror.Errorf("uids: at most %d UIDs, got %d", MaxBatch, len(uids))
+	}
+
+	seen := make(map[string]struct{}, len(uids))
+	uniq := make([]string, 0, len(uids))
+	for _, uid := range uids {
+		if _, ok := seen[uid]; !ok {
+			seen[uid] = struct{}{}
+			uniq = append(uniq, uid)
+		}
+	}
+
+	view := r.view(ctx)
+	orders, err := loaderFrom(ctx).LoadAll(ctx, uniq)
+	res := make([]*gql.Order, 0, len(orders))
+	for i, o := range orders {
+		switch err := errAt(err, i); {
+		case errors.Is(err, dataloadgen.ErrNotFound):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		res = append(res, converter.ModelOrderToGraphQL(o, view))
+	}
+	return res, nil
+}
+
+func (r *queryResolver) OrderList(ctx context.Context, filter *gql.OrderFilter, limit int, after *string) (*gql.OrderPage, error) {
+	if limit < 1 || limit > MaxLimit {
+		return nil, gqlerror.Errorf("limit: want 1 to %d, got %d", MaxLimit, limit)
+	}
+
+	f := model.OrderFilter{Limit: limit}
+	if filter != nil {
+		f.DeliveryService = deref(filter.DeliveryService)
+		f.CustomerID = deref(filter.CustomerID)
+		f.Region = deref(filter.Region)
+		if filter.CreatedFrom != nil {
+			f.CreatedFrom = *filter.CreatedFrom
+		}
+		if filter.CreatedTo != nil {
+			f.CreatedTo = *filter.CreatedTo
+		}
+	}
+	if after != nil {
+		c, err := decodeCursor(*after)
+		if err != nil {
+			return nil, gqlerror.Errorf("after: invalid cursor")
+		}
+		f.After = c
+	}
+
+	orders, err := r.svc.List(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+
+	view := r.view(ctx)
+	loader := loaderFrom(ctx)
+	page := &gql.OrderPage{Orders: make([]*gql.Order, len(orders))}
+	for i, o := range orders {
+		// Следующий order(uid:) этого же запроса не пойдёт в сервис.
+		loader.Prime(o.OrderUUID, o)
+		page.Orders[i] = converter.ModelOrderToGraphQL(o, view)
+	}
+	// Заказы, удалённые между чтением UID и заказа, сервис пропускает, поэтому
+	// короткая страница ещё не конец выборки: конец — только пустая.
+	if len(orders) > 0 {
+		c := encodeCursor(model.CursorOf(orders[len(orders)-1]))
+		page.EndCursor = &c
+	}
+	return page, nil
+}
+
+func (r *queryResolver) view(ctx context.Context) pii.View {
+	id, _ := auth.FromContext(ctx)
+	return r.pii.For(pii.RoleFromScopes(id.Scopes))
+}
+
+// errAt возвращает ошибку i-го ключа из ошибки LoadAll.
+func errAt(err error, i int) error {
+	var errs dataloadgen.ErrorSlice
+	if errors.As(err, &errs) {
+		return errs[i]
+	}
+	return err
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Курсор — base64url от "<date_created RFC3339Nano>|<order_uid>"; клиенты
+// считают его непрозрачным.
+func encodeCursor(c *model.OrderCursor) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(c.DateCreated.UTC().Format(time.RFC3339Nano) + "|" + c.OrderUID))
+}
+
+func decodeCursor(s string) (*model.OrderCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	ts, uid, ok := strings.Cut(string(raw), "|")
+	if !ok || uid == "" {
+		return nil, errors.New("malformed cursor")
+	}
+	created, err := time.Parse(time.RFC3339Nano, ts)
+	if err != nil {
+		return nil, err
+	}
+	return &model.OrderCursor{DateCreated: created, OrderUID: uid}, nil
+}
@@ -0,0 +1,79 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var gqlTracer = otel.Tracer("app/graphql")
+
+// tracing открывает span на операцию и на каждый резолвер. Поля, которые
+// просто читают структуру заказа, span не получают: их сотни на запрос.
+type tracing struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+	graphql.FieldInterceptor
+} = tracing{}
+
+func (tracing) ExtensionName() string {
+	return "Tracing"
+}
+
+func (tracing) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (tracing) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	oc := graphql.GetOperationContext(ctx)
+	name := oc.OperationName
+	if name == "" && oc.Operation != nil {
+		name = oc.Operation.Name
+	}
+	ctx, span := gqlTracer.Start(ctx, "graphql.operation", trace.WithAttributes(
+		attribute.String("graphql.operation.name", name),
+	))
+	if oc.Operation != nil {
+		span.SetAttributes(attribute.String("graphql.operation.type", string(oc.Operation.Operation)))
+	}
+
+	rh := next(ctx)
+	return func(ctx context.Context) *graphql.Response {
+		res := rh(ctx)
+		if res == nil {
+			span.End()
+			return nil
+		}
+		if len(res.Errors) > 0 {
+			span.SetStatus(codes.Error, res.Errors.Error())
+		}
+		span.End()
+		return res
+	}
+}
+
+func (tracing) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := gqlTracer.Start(ctx, "graphql."+fc.Object+"."+fc.Field.Name, trace.WithAttributes(
+		attribute.String("graphql.field.path", fc.Path().String()),
+		attribute.String("graphql.field.alias", fc.Field.Alias),
+	))
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "resolver error")
+	}
+	return res, err
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"app/internal/api/pb/orderv1"
+	"app/internal/auth"
+	"app/internal/logger"
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodScopes — scopes методов OrderService, те же, что у HTTP API.
+var methodScopes = map[string][]string{
+	orderv1.OrderService_GetOrder_FullMethodName:       {"orders:read"},
+	orderv1.OrderService_BatchGetOrders_FullMethodName: {"orders:read"},
+	orderv1.OrderService_ListOrders_FullMethodName:     {"orders:read"},
+	orderv1.OrderService_SubmitOrder_FullMethodName:    {"orders:write"},
+}
+
+// public — служебные сервисы без аутентификации: health нужен пробам,
+// reflection — grpcurl.
+var public = []string{"/grpc.health.v1.Health/", "/grpc.reflection."}
+
+// Security проверяет учётные данные из metadata: x-api-key или
+// authorization: Bearer <JWT>. nil-проверяльщик означает, что схема
+// выключена в конфиге: такие учётные данные отклоняются.
+type Security struct {
+	keys *auth.APIKeys
+	jwt  *auth.JWT
+}
+
+func NewSecurity(keys *auth.APIKeys, jwt *auth.JWT) *Security {
+	return &Security{keys: keys, jwt: jwt}
+}
+
+func (s *Security) UnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := s.authenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Security) StreamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := s.authenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// authenticate кладёт Identity в ctx вызова, в поля логов и в атрибуты
+// span'а. Метод без записи в methodScopes отклоняется: новый RPC не должен
+// оказаться открытым по забывчивости.
+func (s *Security) authenticate(ctx context.Context, method string) (context.Context, error) {
+	for _, p := range public {
+		if strings.HasPrefix(method, p) {
+			return ctx, nil
+		}
+	}
+	scopes, ok := methodScopes[method]
+	if !ok {
+		logger.Warn(ctx, "authorization failed: unknown method", zap.String("method", method))
+		return ctx, status.Error(codes.PermissionDenied, "insufficient scope")
+	}
+
+	id, err := s.verify(ctx)
+	if errors.Is(err, auth.ErrUnavailable) {
+		logger.Error(ctx, "authentication unavailable", zap.String("method", method), zap.Error(err))
+		return ctx, status.Error(codes.Unavailable, "authentication unavailable")
+	}
+	if err != nil {
+		logger.Warn(ctx, "authentication failed", zap.String("method", method), zap.Error(err))
+		return ctx, status.Error(codes.Unauthenticated, "missing or invalid credentials")
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("enduser.id", id.Subject),
+		attribute.String("auth.method", id.Method),
+	)
+	ctx = logger.WithFields(ctx, zap.String("caller", id.Subject), zap.String("auth_method", id.Method))
+
+	if !id.HasScopes(scopes...) {
+		logger.Warn(ctx, "authorization failed", zap.String("method", method), zap.Strings("required_scopes", scopes))
+		return ctx, status.Error(codes.PermissionDenied, "insufficient scope")
+	}
+	return auth.WithIdentity(ctx, id), nil
+}
+
+func (s *Security) verify(ctx context.Context) (auth.Identity, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if key := first(md, "x-api-key"); key != "" {
+		if s.keys == nil {
+			return auth.Identity{}, auth.ErrUnauthenticated
+		}
+		return s.keys.Verify(ctx, key)
+	}
+	if tok, ok := strings.CutPrefix(first(md, "authorization"), "Bearer "); ok && tok != "" {
+		if s.jwt == nil {
+			return auth.Identity{}, auth.ErrUnauthenticated
+		}
+		return s.jwt.Verify(ctx, tok)
+	}
+	return auth.Identity{}, errors.New("no credentials")
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// serverStream подменяет ctx потока на ctx с Identity.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,280 @@
+package v1
+
+import (
+	adapterconv "app/internal/adapter/converter"
+	"app/internal/api/pb/orderv1"
+	"app/internal/auth"
+	"app/internal/converter"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"app/internal/service"
+	"context"
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+var grpcTracer = otel.Tracer("app/grpc/v1")
+
+const (
+	// DefaultMaxBatch — наибольшее число UID в BatchGetOrders по умолчанию.
+	DefaultMaxBatch = 100
+	// listPage — сколько заказов ListOrders читает из сервиса за раз.
+	listPage = 100
+)
+
+// Handler реализует order.v1.OrderService поверх service.Service.
+type Handler struct {
+	orderv1.UnimplementedOrderServiceServer
+
+	orderService service.Service
+	pii          pii.Policy
+	validate     *validator.Validate
+	maxBatch     int
+}
+
+func NewHandler(orderService service.Service, policy pii.Policy) *Handler {
+	return &Handler{
+		orderService: orderService,
+		pii:          policy,
+		validate:     validator.New(),
+		maxBatch:     DefaultMaxBatch,
+	}
+}
+
+type serverOptions struct {
+	maxBatch   int
+	reflection bool
+}
+
+type ServerOption func(*serverOptions)
+
+// WithMaxBatch ограничивает число UID в BatchGetOrders.
+func WithMaxBatch(n int) ServerOption {
+	return func(o *serverOptions) { o.maxBatch = n }
+}
+
+// WithReflection регистрирует gRPC reflection для grpcurl и Postman.
+func WithReflection(on bool) ServerOption {
+	return func(o *serverOptions) { o.reflection = on }
+}
+
+// NewServer собирает gRPC-сервер с OrderService, health и (по опции)
+// reflection. Вызовы трассируются otelgrpc так же, как HTTP — otelhttp.
+// Health возвращается, чтобы при shutdown перевести сервис в NOT_SERVING.
+func NewServer(svc service.Service, sec *Security, policy pii.Policy, opts ...ServerOption) (*grpc.Server, *health.Server) {
+	o := serverOptions{maxBatch: DefaultMaxBatch}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	h := NewHandler(svc, policy)
+	h.maxBatch = o.maxBatch
+
+	srv := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(sec.UnaryInterceptor),
+		grpc.ChainStreamInterceptor(sec.StreamInterceptor),
+	)
+	orderv1.RegisterOrderServiceServer(srv, h)
+
+	hs := health.NewServer()
+	hs.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	hs.SetServingStatus(orderv1.OrderService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, hs)
+
+	if o.reflection {
+		reflection.Register(srv)
+	}
+	return srv, hs
+}
+
+func (h *Handler) GetOrder(ctx context.Context, req *orderv1.GetOrderRequest) (*orderv1.Order, error) {
+	ctx, span := grpcTracer.Start(ctx, "grpc.v1.GetOrder",
+		trace.WithAttributes(attribute.String("order.uid", req.GetOrderUid())),
+	)
+	defer span.End()
+
+	if req.GetOrderUid() == "" {
+		span.SetStatus(otelcodes.Error, "invalid argument")
+		return nil, status.Error(codes.InvalidArgument, "order_uid is required")
+	}
+
+	order, err := h.orderService.Get(ctx, req.GetOrderUid())
+	if err != nil {
+		return nil, toStatus(ctx, span, err)
+	}
+
+	view := h.view(ctx)
+	span.SetAttributes(attribute.String("pii.role", string(view.Role())))
+	span.SetStatus(otelcodes.Ok, "ok")
+	return converter.ModelOrderToProto(order, view), nil
+}
+
+func (h *Handler) BatchGetOrders(ctx context.Context, req *orderv1.BatchGetOrdersRequest) (*orderv1.BatchGetOrdersResponse, error) {
+	uids := req.GetOrderUids()
+	ctx, span := grpcTracer.Start(ctx, "grpc.v1.BatchGetOrders",
+		trace.WithAttributes(attribute.Int("orders.requested", len(uids))),
+	)
+	defer span.End()
+
+	if len(uids) == 0 || len(uids) > h.maxBatch {
+		span.SetStatus(otelcodes.Error, "invalid argument")
+		return nil, status.Errorf(codes.InvalidArgument, "order_uids: want 1 to %d UIDs, got %d", h.maxBatch, len(uids))
+	}
+
+	orders, err := h.orderService.GetMany(ctx, uids)
+	if err != nil {
+		return nil, toStatus(ctx, span, err)
+	}
+
+	view := h.view(ctx)
+	res := &orderv1.BatchGetOrdersResponse{Orders: make([]*orderv1.Order, len(orders))}
+	found := make(map[string]struct{}, len(orders))
+	for i, o := range orders {
+		res.Orders[i] = converter.ModelOrderToProto(o, view)
+		found[o.OrderUUID] = struct{}{}
+	}
+	// found заодно не даёт повторить UID в missing.
+	for _, uid := range uids {
+		if _, ok := found[uid]; !ok {
+			res.Missing = append(res.Missing, uid)
+			found[uid] = struct{}{}
+		}
+	}
+
+	span.SetAttributes(
+		attribute.Int("orders.found", len(res.Orders)),
+		attribute.String("pii.role", string(view.Role())),
+	)
+	span.SetStatus(otelcodes.Ok, "ok")
+	return res, nil
+}
+
+// ListOrders отдаёт заказы страницами по listPage, продолжая с курсора
+// последнего отправленного заказа, пока не наберётся limit или не кончатся
+// подходящие заказы.
+func (h *Handler) ListOrders(req *orderv1.ListOrdersRequest, stream grpc.ServerStreamingServer[orderv1.Order]) error {
+	ctx, span := grpcTracer.Start(stream.Context(), "grpc.v1.ListOrders",
+		trace.WithAttributes(
+			attribute.String("filter.delivery_service", req.GetDeliveryService()),
+			attribute.String("filter.region", req.GetRegion()),
+			attribute.Int("orders.limit", int(req.GetLimit())),
+		),
+	)
+	defer span.End()
+
+	if req.GetLimit() < 0 {
+		span.SetStatus(otelcodes.Error, "invalid argument")
+		return status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+
+	f := model.OrderFilter{
+		DeliveryService: req.GetDeliveryService(),
+		CustomerID:      req.GetCustomerId(),
+		Region:          req.GetRegion(),
+	}
+	if req.GetCreatedFrom() != nil {
+		f.CreatedFrom = req.GetCreatedFrom().AsTime()
+	}
+	if req.GetCreatedTo() != nil {
+		f.CreatedTo = req.GetCreatedTo().AsTime()
+	}
+
+	view := h.view(ctx)
+	limit, sent := int(req.GetLimit()), 0
+	for {
+		f.Limit = listPage
+		if limit > 0 {
+			f.Limit = min(listPage, limit-sent)
+		}
+		page, err := h.orderService.List(ctx, f)
+		if err != nil {
+			return toStatus(ctx, span, err)
+		}
+		// Заказы, удалённые между чтением UID и заказа, сервис пропускает,
+		// поэтому конец выборки — только пустая страница.
+		if len(page) == 0 {
+			break
+		}
+		for _, o := range page {
+			if err := stream.Send(converter.ModelOrderToProto(o, view)); err != nil {
+				span.RecordError(err)
+				span.SetStatus(otelcodes.Error, "send failed")
+				return err
+			}
+		}
+		sent += len(page)
+		if limit > 0 && sent >= limit {
+			break
+		}
+		f.After = model.CursorOf(page[len(page)-1])
+	}
+
+	span.SetAttributes(attribute.Int("orders.sent", sent), attribute.String("pii.role", string(view.Role())))
+	span.SetStatus(otelcodes.Ok, "ok")
+	return nil
+}
+
+// SubmitOrder проверяет заказ теми же правилами, что сообщение из Kafka,
+// и сохраняет его.
+func (h *Handler) SubmitOrder(ctx context.Context, req *orderv1.SubmitOrderRequest) (*orderv1.SubmitOrderResponse, error) {
+	ctx, span := grpcTracer.Start(ctx, "grpc.v1.SubmitOrder",
+		trace.WithAttributes(attribute.String("order.uid", req.GetOrder().GetOrderUid())),
+	)
+	defer span.End()
+
+	if req.GetOrder() == nil {
+		span.SetStatus(otelcodes.Error, "invalid argument")
+		return nil, status.Error(codes.InvalidArgument, "order is required")
+	}
+	order := converter.ProtoOrderToModel(req.GetOrder())
+	if err := h.validate.Struct(adapterconv.OrderModelToDTO(order)); err != nil {
+		span.SetStatus(otelcodes.Error, "invalid argument")
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := h.orderService.ProcessOrder(ctx, order); err != nil {
+		return nil, toStatus(ctx, span, err)
+	}
+	span.SetStatus(otelcodes.Ok, "ok")
+	return &orderv1.SubmitOrderResponse{OrderUid: order.OrderUUID}, nil
+}
+
+func (h *Handler) view(ctx context.Context) pii.View {
+	id, _ := auth.FromContext(ctx)
+	return h.pii.For(pii.RoleFromScopes(id.Scopes))
+}
+
+// toStatus переводит ошибку сервиса в gRPC-статус. Внутренние ошибки
+// клиенту не раскрываются, только пишутся в лог и span.
+func toStatus(ctx context.Context, span trace.Span, err error) error {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		span.SetStatus(otelcodes.Error, "not found")
+		return status.Error(codes.NotFound, "order not found")
+	case errors.Is(err, model.ErrAlreadyExists):
+		span.SetStatus(otelcodes.Error, "already exists")
+		return status.Error(codes.AlreadyExists, "order already exists")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	span.RecordError(err)
+	span.SetStatus(otelcodes.Error, "service error")
+	logger.Error(ctx, "grpc call failed", zap.Error(err))
+	return status.Error(codes.Internal, "internal error")
+}
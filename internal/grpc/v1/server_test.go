@@ -0,0 +1,253 @@
+package v1
+
+import (
+	"app/internal/api/pb/orderv1"
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// orderService — service.Service поверх map; List отдаёт заказы по
+// убыванию (date_created, uid) с учётом курсора, как репозиторий.
+type orderService struct {
+	orders    map[string]model.Order
+	listCalls int
+	processed []model.Order
+}
+
+func (s *orderService) Get(_ context.Context, uid string) (model.Order, error) {
+	o, ok := s.orders[uid]
+	if !ok {
+		return model.Order{}, model.ErrNotFound
+	}
+	return o, nil
+}
+
+func (s *orderService) GetMany(ctx context.Context, uids []string) ([]model.Order, error) {
+	var out []model.Order
+	seen := map[string]bool{}
+	for _, uid := range uids {
+		if o, err := s.Get(ctx, uid); err == nil && !seen[uid] {
+			out = append(out, o)
+		}
+		seen[uid] = true
+	}
+	return out, nil
+}
+
+func (s *orderService) List(_ context.Context, f model.OrderFilter) ([]model.Order, error) {
+	s.listCalls++
+	var all []model.Order
+	for _, o := range s.orders {
+		if f.DeliveryService != "" && o.DeliveryService != f.DeliveryService {
+			continue
+		}
+		all = append(all, o)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if !all[i].DateCreated.Equal(all[j].DateCreated) {
+			return all[i].DateCreated.After(all[j].DateCreated)
+		}
+		return all[i].OrderUUID > all[j].OrderUUID
+	})
+
+	var page []model.Order
+	for _, o := range all {
+		if c := f.After; c != nil && !(o.DateCreated.Before(c.DateCreated) ||
+			o.DateCreated.Equal(c.DateCreated) && o.OrderUUID < c.OrderUID) {
+			continue
+		}
+		if len(page) == f.Limit {
+			break
+		}
+		page = append(page, o)
+	}
+	return page, nil
+}
+
+func (s *orderService) ProcessOrder(_ context.Context, o model.Order) error {
+	if _, ok := s.orders[o.OrderUUID]; ok {
+		return fmt.Errorf("order %s: %w", o.OrderUUID, model.ErrAlreadyExists)
+	}
+	s.processed = append(s.processed, o)
+	return nil
+}
+
+type fakeKeyStore map[string]model.APIKey
+
+func (f fakeKeyStore) Lookup(_ context.Context, hash []byte) (model.APIKey, error) {
+	k, ok := f[string(hash)]
+	if !ok {
+		return model.APIKey{}, model.ErrNotFound
+	}
+	return k, nil
+}
+
+func TestServer(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	reader, readerHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	writer, writerHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	keys := auth.NewAPIKeys(fakeKeyStore{
+		string(readerHash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}},
+		string(writerHash): {ID: 2, Name: "writer", Scopes: []string{"orders:write"}},
+	}, time.Minute)
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	svc := &orderService{orders: map[string]model.Order{}}
+	for i := range 250 {
+		uid := fmt.Sprintf("uid-%03d", i)
+		ds := "meest"
+		if i%2 == 1 {
+			ds = "dpd"
+		}
+		svc.orders[uid] = model.Order{
+			OrderUUID:       uid,
+			DeliveryService: ds,
+			DateCreated:     base.Add(time.Duration(i) * time.Minute),
+			Delivery:        model.Delivery{Phone: "+79991234567"},
+		}
+	}
+
+	lis := bufconn.Listen(1 << 20)
+	srv, hs := NewServer(svc, NewSecurity(keys, nil), pii.DefaultPolicy, WithMaxBatch(3), WithReflection(true))
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	client := orderv1.NewOrderServiceClient(conn)
+
+	ctx := context.Background()
+	as := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, "x-api-key", key)
+	}
+	code := func(err error) codes.Code { return status.Code(err) }
+
+	// Аутентификация и scopes.
+	_, err = client.GetOrder(ctx, &orderv1.GetOrderRequest{OrderUid: "uid-001"})
+	require.Equal(t, codes.Unauthenticated, code(err))
+	_, err = client.GetOrder(metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer x"), &orderv1.GetOrderRequest{OrderUid: "uid-001"})
+	require.Equal(t, codes.Unauthenticated, code(err))
+	_, err = client.GetOrder(as(writer), &orderv1.GetOrderRequest{OrderUid: "uid-001"})
+	require.Equal(t, codes.PermissionDenied, code(err))
+
+	// GetOrder: PII маскируется по роли.
+	o, err := client.GetOrder(as(reader), &orderv1.GetOrderRequest{OrderUid: "uid-001"})
+	require.NoError(t, err)
+	require.Equal(t, "uid-001", o.GetOrderUid())
+	require.Equal(t, pii.MaskPhone("+79991234567"), o.GetDelivery().GetPhone())
+	_, err = client.GetOrder(as(reader), &orderv1.GetOrderRequest{OrderUid: "nope"})
+	require.Equal(t, codes.NotFound, code(err))
+
+	// BatchGetOrders: порядок запроса, дубли схлопываются, лимит батча.
+	batch, err := client.BatchGetOrders(as(reader), &orderv1.BatchGetOrdersRequest{OrderUids: []string{"uid-002", "nope", "uid-002"}})
+	require.NoError(t, err)
+	require.Len(t, batch.GetOrders(), 1)
+	require.Equal(t, []string{"nope"}, batch.GetMissing())
+	_, err = client.BatchGetOrders(as(reader), &orderv1.BatchGetOrdersRequest{OrderUids: []string{"a", "b", "c", "d"}})
+	require.Equal(t, codes.InvalidArgument, code(err))
+
+	// ListOrders: все подходящие заказы страницами, новые первыми.
+	recv := func(req *orderv1.ListOrdersRequest) []string {
+		stream, err := client.ListOrders(as(reader), req)
+		require.NoError(t, err)
+		var uids []string
+		for {
+			o, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return uids
+			}
+			require.NoError(t, err)
+			uids = append(uids, o.GetOrderUid())
+		}
+	}
+	svc.listCalls = 0
+	uids := recv(&orderv1.ListOrdersRequest{DeliveryService: "meest"})
+	require.Len(t, uids, 125)
+	require.Equal(t, "uid-248", uids[0])
+	require.True(t, slices.IsSortedFunc(uids, func(a, b string) int { return strings.Compare(b, a) }))
+	require.Equal(t, 3, svc.listCalls)
+
+	require.Equal(t, []string{"uid-249", "uid-248", "uid-247"}, recv(&orderv1.ListOrdersRequest{Limit: 3}))
+
+	// SubmitOrder: валидация и конфликт.
+	wctx := as(writer)
+	_, err = client.SubmitOrder(as(reader), &orderv1.SubmitOrderRequest{Order: &orderv1.Order{OrderUid: "new"}})
+	require.Equal(t, codes.PermissionDenied, code(err))
+	_, err = client.SubmitOrder(wctx, &orderv1.SubmitOrderRequest{Order: &orderv1.Order{OrderUid: "new"}})
+	require.Equal(t, codes.InvalidArgument, code(err))
+	_, err = client.SubmitOrder(wctx, &orderv1.SubmitOrderRequest{Order: validOrder("uid-001")})
+	require.Equal(t, codes.AlreadyExists, code(err))
+	res, err := client.SubmitOrder(wctx, &orderv1.SubmitOrderRequest{Order: validOrder("new")})
+	require.NoError(t, err)
+	require.Equal(t, "new", res.GetOrderUid())
+	require.Len(t, svc.processed, 1)
+	require.Equal(t, "+79991234567", svc.processed[0].Delivery.Phone)
+
+	// Health не требует учётных данных.
+	hc := healthpb.NewHealthClient(conn)
+	h, err := hc.Check(ctx, &healthpb.HealthCheckRequest{Service: orderv1.OrderService_ServiceDesc.ServiceName})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, h.GetStatus())
+	hs.Shutdown()
+	h, err = hc.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, h.GetStatus())
+}
+
+func validOrder(uid string) *orderv1.Order {
+	str := func(s string) *string { return &s }
+	return &orderv1.Order{
+		OrderUid:          uid,
+		TrackNumber:       "WBILMTESTTRACK",
+		Entry:             "WBIL",
+		Locale:            "en",
+		InternalSignature: str("sig"),
+		CustomerId:        str("test"),
+		DeliveryService:   "meest",
+		ShardKey:          "9",
+		SmId:              99,
+		DateCreated:       timestamppb.New(time.Date(2021, 11, 26, 6, 22, 19, 0, time.UTC)),
+		OffShard:          "1",
+		Delivery: &orderv1.Delivery{
+			Name: str("Test Testov"), Phone: str("+79991234567"), Zip: str("2639809"),
+			City: str("Kiryat Mozkin"), Address: str("Ploshad Mira 15"), Region: "Kraiot", Email: str("test@gmail.com"),
+		},
+		Payment: &orderv1.Payment{
+			Transaction: str(uid), Request: str("req-1"), Currency: "USD", Provider: "wbpay", Amount: 1817,
+			PaymentDt: 1637907727, Bank: "alpha", DeliveryCost: 1500, GoodsTotal: 317,
+		},
+		Items: []*orderv1.Item{{
+			ChrtId: 9934930, TrackNumber: "WBILMTESTTRACK", Price: 453, Rid: "ab4219087a764ae0btest",
+			Name: "Mascaras", Sale: 30, Size: "0", TotalPrice: 317, NmId: 2389212, Brand: "Vivienne Sabo", Status: 202,
+		}},
+	}
+}
@@ -0,0 +1,168 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"app/internal/logger"
+
+	"go.uber.org/zap"
+)
+
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+
+	ErrorTimeout     = "timeout"
+	ErrorUnavailable = "unavailable"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type Result struct {
+	Name     string
+	Err      error
+	Duration time.Duration
+}
+
+type ComponentReport struct {
+	Status     string  `json:"status"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type Report struct {
+	Status     string                     `json:"status"`
+	Components map[string]ComponentReport `json:"components,omitempty"`
+}
+
+// Checker — набор именованных проверок готовности. Набор зависит от роли
+// процесса: API не проверяет Kafka, ingester проверяет.
+type Checker struct {
+	mu      sync.RWMutex
+	names   []string
+	checks  map[string]CheckFunc
+	timeout time.Duration
+
+	shuttingDown atomic.Bool
+}
+
+func New(timeout time.Duration) *Checker {
+	return &Checker{
+		checks:  make(map[string]CheckFunc),
+		timeout: timeout,
+	}
+}
+
+func (c *Checker) Add(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.checks[name]; !ok {
+		c.names = append(c.names, name)
+	}
+	c.checks[name] = fn
+}
+
+// SetShuttingDown переводит процесс в not-ready независимо от проверок:
+// балансировщик перестаёт слать трафик до того, как закроются ресурсы.
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check выполняет все проверки параллельно, каждую со своим таймаутом,
+// и возвращает результаты в порядке регистрации.
+func (c *Checker) Check(ctx context.Context) []Result {
+	c.mu.RLock()
+	names := append([]string(nil), c.names...)
+	checks := make([]CheckFunc, len(names))
+	for i, n := range names {
+		checks[i] = c.checks[n]
+	}
+	c.mu.RUnlock()
+
+	out := make([]Result, len(names))
+	var wg sync.WaitGroup
+	for i := range names {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := checks[i](checkCtx)
+			out[i] = Result{Name: names[i], Err: err, Duration: time.Since(start)}
+		}(i)
+	}
+	wg.Wait()
+
+	return out
+}
+
+func (c *Checker) Report(ctx context.Context) Report {
+	if c.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown}
+	}
+
+	rep := Report{Status: StatusReady, Components: make(map[string]ComponentReport)}
+	for _, res := range c.Check(ctx) {
+		comp := ComponentReport{
+			Status:     StatusUp,
+			DurationMs: float64(res.Duration.Microseconds()) / 1000,
+		}
+		if res.Err != nil {
+			comp.Status = StatusDown
+			comp.Error = errorClass(res.Err)
+			rep.Status = StatusNotReady
+			logger.Warn(ctx, "readiness check failed", zap.String("component", res.Name), zap.Error(res.Err))
+		}
+		rep.Components[res.Name] = comp
+	}
+	return rep
+}
+
+// errorClass — обобщённая причина сбоя для публичного ответа: текст ошибки
+// может содержать адреса, имена пользователей и детали драйверов.
+func errorClass(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorTimeout
+	}
+	return ErrorUnavailable
+}
+
+// ReadyHandler отвечает 200, если все проверки прошли, иначе 503.
+// В теле — состояние каждого компонента.
+func (c *Checker) ReadyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rep := c.Report(r.Context())
+
+		code := http.StatusOK
+		if rep.Status != StatusReady {
+			code = http.StatusServiceUnavailable
+		}
+		writeJSON(w, code, rep)
+	})
+}
+
+// LiveHandler отвечает 200, пока процесс способен обслуживать HTTP.
+func LiveHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, Report{Status: "alive"})
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChecker_Check_OrderAndTimeout(t *testing.T) {
+	c := New(20 * time.Millisecond)
+	c.Add("postgres", func(ctx context.Context) error { return nil })
+	c.Add("kafka", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	res := c.Check(context.Background())
+
+	require.Len(t, res, 2)
+	require.Equal(t, "postgres", res[0].Name)
+	require.NoError(t, res[0].Err)
+	require.Equal(t, "kafka", res[1].Name)
+	require.ErrorIs(t, res[1].Err, context.DeadlineExceeded)
+}
+
+func serveReady(t *testing.T, c *Checker) (int, Report) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c.ReadyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var rep Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &rep))
+	return rec.Code, rep
+}
+
+func TestChecker_ReadyHandler(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+	c := New(time.Second)
+	c.Add("postgres", func(ctx context.Context) error { return nil })
+
+	code, rep := serveReady(t, c)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, StatusReady, rep.Status)
+	require.Equal(t, StatusUp, rep.Components["postgres"].Status)
+
+	c.Add("kafka", func(ctx context.Context) error { return errors.New("no brokers") })
+
+	code, rep = serveReady(t, c)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusNotReady, rep.Status)
+	require.Equal(t, StatusUp, rep.Components["postgres"].Status)
+	require.Equal(t, ComponentReport{Status: StatusDown, Error: ErrorUnavailable, DurationMs: rep.Components["kafka"].DurationMs}, rep.Components["kafka"])
+}
+
+func TestChecker_ShuttingDown_SkipsChecks(t *testing.T) {
+	c := New(time.Second)
+	called := false
+	c.Add("postgres", func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	c.SetShuttingDown()
+
+	code, rep := serveReady(t, c)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, StatusShuttingDown, rep.Status)
+	require.False(t, called)
+}
+
+func TestLiveHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LiveHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status":"alive"}`, rec.Body.String())
+}
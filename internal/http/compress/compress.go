@@ -0,0 +1,278 @@
+package compress
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+)
+
+// Поддерживаемые кодировки (значения Content-Encoding).
+const (
+	Zstd   = "zstd"
+	Brotli = "br"
+	Gzip   = "gzip"
+)
+
+// Encodings — кодировки по умолчанию в порядке предпочтения сервера.
+var Encodings = []string{Zstd, Brotli, Gzip}
+
+// DefaultMinSize — ответы меньше этого размера не сжимаются: заголовки
+// и кадры кодировки съедят выигрыш.
+const DefaultMinSize = 1024
+
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// zstdEncoder приводит Reset к общей сигнатуре.
+type zstdEncoder struct{ *zstd.Encoder }
+
+func (z zstdEncoder) Reset(w io.Writer) { z.Encoder.Reset(w) }
+
+// pools — переиспользуемые кодировщики: создать zstd или brotli на каждый
+// ответ заметно дороже самого сжатия.
+var pools = map[string]*sync.Pool{
+	Zstd: {New: func() any {
+		e, _ := zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault), zstd.WithEncoderConcurrency(1))
+		return zstdEncoder{e}
+	}},
+	Brotli: {New: func() any { return brotli.NewWriterLevel(nil, 5) }},
+	Gzip: {New: func() any {
+		w, _ := gzip.NewWriterLevel(nil, gzip.DefaultCompression)
+		return w
+	}},
+}
+
+// Compressor — HTTP middleware, сжимающее ответы по Accept-Encoding.
+type Compressor struct {
+	encodings []string
+	minSize   int
+}
+
+type Option func(*Compressor)
+
+// MinSize задаёт порог: ответы короче n байт отдаются как есть.
+func MinSize(n int) Option {
+	return func(c *Compressor) { c.minSize = n }
+}
+
+// New принимает кодировки в порядке предпочтения; при равных q клиента
+// выбирается более ранняя.
+func New(encodings []string, opts ...Option) (*Compressor, error) {
+	for _, e := range encodings {
+		if _, ok := pools[e]; !ok {
+			return nil, fmt.Errorf("compress: unknown encoding %q, want one of %s", e, strings.Join(Encodings, ", "))
+		}
+	}
+	c := &Compressor{encodings: encodings, minSize: DefaultMinSize}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Handler сжимает ответ, если клиент принимает одну из кодировок, тип
+// содержимого сжимаем, а тело не короче порога. Тело до порога
+// буферизуется, чтобы решить это до отправки заголовков.
+func (c *Compressor) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		enc := c.negotiate(r.Header.Get("Accept-Encoding"))
+		if enc == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &writer{ResponseWriter: w, encoding: enc, minSize: c.minSize}
+		defer cw.close()
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiate выбирает кодировку с наибольшим q из Accept-Encoding.
+func (c *Compressor) negotiate(header string) string {
+	if header == "" {
+		return ""
+	}
+	accepted := make(map[string]float64)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, e := range c.encodings {
+		q, ok := accepted[e]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// compressible — типы, которые имеет смысл сжимать; изображения и архивы
+// уже сжаты.
+func compressible(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(mt, "text/") || strings.HasSuffix(mt, "+json") || strings.HasSuffix(mt, "+xml") {
+		return true
+	}
+	switch mt {
+	case "application/json", "application/x-ndjson", "application/yaml", "application/javascript",
+		"application/msgpack", "application/protobuf", "image/svg+xml":
+		return true
+	}
+	return false
+}
+
+// writer копит тело до minSize, затем решает: сжимать или отдать как есть.
+type writer struct {
+	http.ResponseWriter
+	encoding string
+	minSize  int
+
+	code    int
+	buf     []byte
+	decided bool
+	enc     encoder
+}
+
+func (w *writer) WriteHeader(code int) {
+	if w.code != 0 {
+		return
+	}
+	w.code = code
+	// У этих ответов нет тела — сжимать нечего.
+	if code < http.StatusOK || code == http.StatusNoContent || code == http.StatusNotModified {
+		w.passthrough()
+	}
+}
+
+func (w *writer) Write(b []byte) (int, error) {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.decided {
+		if w.enc != nil {
+			return w.enc.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buf = append(w.buf, b...)
+	if len(w.buf) < w.minSize {
+		return len(b), nil
+	}
+	if err := w.decide(); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// decide начинает сжатие, если тип содержимого это позволяет, и
+// сбрасывает накопленный буфер.
+func (w *writer) decide() error {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" || !compressible(h.Get("Content-Type")) {
+		return w.passthrough()
+	}
+
+	h.Set("Content-Encoding", w.encoding)
+	h.Del("Content-Length")
+	// Сжатое представление отличается побайтно, поэтому строгий ETag
+	// становится слабым.
+	if etag := h.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		h.Set("ETag", "W/"+etag)
+	}
+	w.ResponseWriter.WriteHeader(w.code)
+
+	w.decided = true
+	w.enc = pools[w.encoding].Get().(encoder)
+	w.enc.Reset(w.ResponseWriter)
+	buf := w.buf
+	w.buf = nil
+	_, err := w.enc.Write(buf)
+	return err
+}
+
+// passthrough отправляет заголовки и буфер без сжатия.
+func (w *writer) passthrough() error {
+	if w.decided {
+		return nil
+	}
+	w.decided = true
+	w.ResponseWriter.WriteHeader(w.code)
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// Flush отдаёт клиенту уже записанное. Потоковые ответы, начатые до
+// порога, не сжимаются.
+func (w *writer) Flush() {
+	if w.code == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		_ = w.passthrough()
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap даёт http.ResponseController доступ к исходному writer.
+func (w *writer) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *writer) close() {
+	if w.code == 0 {
+		// Обработчик ничего не записал.
+		return
+	}
+	if !w.decided {
+		_ = w.passthrough()
+		return
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(nil)
+		pools[w.encoding].Put(w.enc)
+		w.enc = nil
+	}
+}
@@ -0,0 +1,123 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/gzip"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNegotiate(t *testing.T) {
+	c, err := New(Encodings)
+	require.NoError(t, err)
+
+	require.Equal(t, "", c.negotiate(""))
+	require.Equal(t, "", c.negotiate("identity"))
+	require.Equal(t, Gzip, c.negotiate("gzip, deflate"))
+	// При равных q — порядок сервера.
+	require.Equal(t, Zstd, c.negotiate("gzip, br, zstd"))
+	require.Equal(t, Brotli, c.negotiate("gzip;q=0.5, br;q=0.8, zstd;q=0.1"))
+	require.Equal(t, Zstd, c.negotiate("*"))
+	require.Equal(t, Gzip, c.negotiate("*;q=0.1, zstd;q=0, br;q=0, gzip"))
+	require.Equal(t, "", c.negotiate("gzip;q=0"))
+
+	_, err = New([]string{"deflate"})
+	require.Error(t, err)
+}
+
+func decode(t *testing.T, enc string, body []byte) string {
+	t.Helper()
+	var r io.Reader
+	switch enc {
+	case Gzip:
+		gr, err := gzip.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		r = gr
+	case Zstd:
+		zr, err := zstd.NewReader(bytes.NewReader(body))
+		require.NoError(t, err)
+		defer zr.Close()
+		r = zr
+	case Brotli:
+		r = brotli.NewReader(bytes.NewReader(body))
+	}
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return string(b)
+}
+
+func TestHandler(t *testing.T) {
+	big := strings.Repeat(`{"order_uid":"b563feb7b2b84b6test"}`, 100)
+
+	c, err := New(Encodings, MinSize(512))
+	require.NoError(t, err)
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ct := r.URL.Query().Get("ct")
+		if ct == "" {
+			ct = "application/json"
+		}
+		w.Header().Set("Content-Type", ct)
+		w.Header().Set("ETag", `"v1"`)
+		if r.URL.Query().Has("small") {
+			_, _ = w.Write([]byte(`{}`))
+			return
+		}
+		// Пишем частями: порог считается по сумме.
+		_, _ = w.Write([]byte(big[:100]))
+		_, _ = w.Write([]byte(big[100:]))
+	}))
+
+	do := func(target, acceptEncoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	for _, enc := range Encodings {
+		// Дважды: второй раз кодировщик берётся из пула.
+		for i := 0; i < 2; i++ {
+			rec := do("/", enc)
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.Equal(t, enc, rec.Header().Get("Content-Encoding"))
+			require.Equal(t, `W/"v1"`, rec.Header().Get("ETag"))
+			require.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+			require.Less(t, rec.Body.Len(), len(big))
+			require.Equal(t, big, decode(t, enc, rec.Body.Bytes()))
+		}
+	}
+
+	// Ниже порога, без Accept-Encoding и для несжимаемых типов — как есть.
+	for _, target := range []string{"/?small", "/?ct=image/png"} {
+		rec := do(target, "gzip")
+		require.Empty(t, rec.Header().Get("Content-Encoding"), target)
+		require.Equal(t, `"v1"`, rec.Header().Get("ETag"), target)
+	}
+	rec := do("/", "")
+	require.Empty(t, rec.Header().Get("Content-Encoding"))
+	require.Equal(t, big, rec.Body.String())
+}
+
+func TestHandler_NoBody(t *testing.T) {
+	c, err := New(Encodings, MinSize(0))
+	require.NoError(t, err)
+	h := c.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Header().Get("Content-Encoding"))
+	require.Zero(t, rec.Body.Len())
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	gen "app/internal/api/v1"
+	"app/internal/auth"
+	"app/internal/model"
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// Eraser удаляет персональные данные субъекта (см. internal/erasure).
+type Eraser interface {
+	Erase(ctx context.Context, req model.ErasureRequest) (model.ErasureReport, error)
+}
+
+func (h *Handler) EraseCustomer(ctx context.Context, req *gen.ErasureRequest) (gen.EraseCustomerRes, error) {
+	ctx, span := httpTracer.Start(ctx, "v1.EraseCustomer")
+	defer span.End()
+
+	id, _ := auth.FromContext(ctx)
+	rep, err := h.eraser.Erase(ctx, model.ErasureRequest{
+		CustomerID:  req.CustomerID.Value,
+		Email:       req.Email.Value,
+		Phone:       req.Phone.Value,
+		RequestedBy: id.Subject,
+	})
+	if errors.Is(err, model.ErrEmptyErasure) {
+		span.SetStatus(codes.Error, "empty request")
+		return &gen.EraseCustomerBadRequest{Message: err.Error()}, nil
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "erase failed")
+		return nil, err
+	}
+
+	span.SetAttributes(
+		attribute.Int64("erasure.id", rep.ID),
+		attribute.Int("erasure.orders", len(rep.OrderUIDs)),
+	)
+	span.SetStatus(codes.Ok, "ok")
+	return &gen.ErasureReport{
+		ID:          rep.ID,
+		RequestedBy: rep.RequestedBy,
+		OrderUids:   rep.OrderUIDs,
+		ErasedAt:    rep.ErasedAt,
+	}, nil
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEraser struct {
+	got model.ErasureRequest
+}
+
+func (f *fakeEraser) Erase(_ context.Context, req model.ErasureRequest) (model.ErasureReport, error) {
+	if req.CustomerID == "" && req.Email == "" && req.Phone == "" {
+		return model.ErasureReport{}, model.ErrEmptyErasure
+	}
+	f.got = req
+	return model.ErasureReport{
+		ID:          5,
+		RequestedBy: req.RequestedBy,
+		OrderUIDs:   []string{"uid-1"},
+		ErasedAt:    time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, nil
+}
+
+func TestAPI_EraseCustomer(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	reader, readerHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	dpo, dpoHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	store := fakeKeyStore{
+		string(readerHash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}},
+		string(dpoHash):    {ID: 2, Name: "dpo", Scopes: []string{"orders:erase"}},
+	}
+
+	eraser := &fakeEraser{}
+	api, err := NewAPI(&fakeService{}, eraser, NewSecurity(auth.NewAPIKeys(store, time.Minute), nil), pii.DefaultPolicy)
+	require.NoError(t, err)
+
+	do := func(key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/admin/erasures", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-API-Key", key)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	require.Equal(t, http.StatusForbidden, do(reader, `{"customer_id":"cust-1"}`).Code)
+
+	rec := do(dpo, `{}`)
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+	rec = do(dpo, `{"customer_id":"cust-1","email":"a@b.c"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.JSONEq(t, `{"id":5,"requested_by":"apikey:2","order_uids":["uid-1"],"erased_at":"2025-03-01T00:00:00Z"}`, rec.Body.String())
+	require.Equal(t, model.ErasureRequest{CustomerID: "cust-1", Email: "a@b.c", RequestedBy: "apikey:2"}, eraser.got)
+}
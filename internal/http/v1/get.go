@@ -2,17 +2,31 @@ package v1
 
 import (
 	gen "app/internal/api/v1"
+	"app/internal/auth"
 	"app/internal/converter"
+	"app/internal/model"
+	"app/internal/pii"
+	"bytes"
 	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
 )
 
 var httpTracer = otel.Tracer("app/http/v1")
 
+// vary — от чего зависит ответ: от представления и от роли вызывающего,
+// то есть от его учётных данных.
+const vary = "Accept, Authorization, X-API-Key"
+
 func (h *Handler) GetOrder(ctx context.Context, params gen.GetOrderParams) (gen.GetOrderRes, error) {
 	ctx, span := httpTracer.Start(ctx, "v1.GetOrder",
 		trace.WithAttributes(attribute.String("order.uid", params.OrderUID)),
@@ -20,13 +34,108 @@ func (h *Handler) GetOrder(ctx context.Context, params gen.GetOrderParams) (gen.
 	defer span.End()
 
 	order, err := h.orderService.Get(ctx, params.OrderUID)
+	if errors.Is(err, model.ErrNotFound) {
+		span.SetStatus(codes.Error, "not found")
+		return &gen.GetOrderNotFound{Message: "order not found"}, nil
+	}
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "service error")
 		return nil, err
 	}
 
-	res := converter.ModelOrderToGen(order)
+	id, _ := auth.FromContext(ctx)
+	role := pii.RoleFromScopes(id.Scopes)
+	media := negotiate(ctx, mediaJSON, mediaMsgpack, mediaProtobuf)
+	span.SetAttributes(
+		attribute.String("pii.role", string(role)),
+		attribute.String("http.response.media_type", media),
+	)
+
+	etag := orderETag(order, role, media)
+	lastModified := order.DateCreated.UTC().Format(http.TimeFormat)
+	cacheControl := "private, max-age=" + strconv.Itoa(int(h.maxAge.Seconds()))
+
+	if notModified(params, etag, order.DateCreated) {
+		span.SetAttributes(attribute.Bool("http.not_modified", true))
+		span.SetStatus(codes.Ok, "not modified")
+		return &gen.GetOrderNotModified{
+			ETag:         etag,
+			LastModified: lastModified,
+			CacheControl: cacheControl,
+			Vary:         vary,
+		}, nil
+	}
+
+	res := converter.ModelOrderToGen(order, h.pii.For(role))
 	span.SetStatus(codes.Ok, "ok")
-	return &res, nil
+	switch media {
+	case mediaMsgpack:
+		body, err := toMsgpack(jsonBytes(&res))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "encode failed")
+			return nil, err
+		}
+		return &gen.GetOrderOKApplicationMsgpackHeaders{
+			ETag:         etag,
+			LastModified: lastModified,
+			CacheControl: cacheControl,
+			Vary:         vary,
+			Response:     gen.GetOrderOKApplicationMsgpack{Data: body},
+		}, nil
+	case mediaProtobuf:
+		body, err := proto.Marshal(converter.ModelOrderToProto(order, h.pii.For(role)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "encode failed")
+			return nil, err
+		}
+		return &gen.GetOrderOKApplicationProtobufHeaders{
+			ETag:         etag,
+			LastModified: lastModified,
+			CacheControl: cacheControl,
+			Vary:         vary,
+			Response:     gen.GetOrderOKApplicationProtobuf{Data: bytes.NewReader(body)},
+		}, nil
+	}
+	return &gen.OrderHeaders{
+		ETag:         etag,
+		LastModified: lastModified,
+		CacheControl: cacheControl,
+		Vary:         vary,
+		Response:     res,
+	}, nil
+}
+
+// orderETag — ETag ответа. Одному заказу разные роли видят разный JSON,
+// поэтому роль входит в тег; представления, кроме JSON, — тоже.
+func orderETag(o model.Order, role pii.Role, media string) string {
+	tag := o.WithETag().ETag + "-" + string(role)
+	if media != mediaJSON {
+		_, sub, _ := strings.Cut(media, "/")
+		tag += "-" + sub
+	}
+	return `"` + tag + `"`
+}
+
+// notModified проверяет условия запроса по RFC 9110: If-None-Match
+// (слабое сравнение, список тегов или *), а без него — If-Modified-Since.
+// Дата — только дата создания: удаление данных субъекта её не меняет, и
+// изменения видны лишь по ETag.
+func notModified(params gen.GetOrderParams, etag string, created time.Time) bool {
+	if inm, ok := params.IfNoneMatch.Get(); ok {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+		return false
+	}
+	if ims, ok := params.IfModifiedSince.Get(); ok {
+		t, err := http.ParseTime(ims)
+		return err == nil && !created.Truncate(time.Second).After(t)
+	}
+	return false
 }
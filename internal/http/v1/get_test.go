@@ -0,0 +1,88 @@
+package v1
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type orderService struct {
+	order model.Order
+}
+
+func (s orderService) ProcessOrder(context.Context, model.Order) error { return nil }
+
+func (s orderService) List(context.Context, model.OrderFilter) ([]model.Order, error) {
+	return nil, nil
+}
+
+func (s orderService) Get(context.Context, string) (model.Order, error) { return s.order, nil }
+
+func (s orderService) GetMany(context.Context, []string) ([]model.Order, error) { return nil, nil }
+
+func TestAPI_GetOrder_Conditional(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	reader, readerHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	admin, adminHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	store := fakeKeyStore{
+		string(readerHash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}},
+		string(adminHash):  {ID: 2, Name: "admin", Scopes: []string{"orders:read", pii.ScopeFull}},
+	}
+
+	created := time.Date(2024, 3, 1, 12, 30, 45, 500, time.UTC)
+	svc := orderService{order: model.Order{OrderUUID: "uid-1", DateCreated: created}}
+	api, err := NewAPI(svc, &fakeEraser{}, NewSecurity(auth.NewAPIKeys(store, time.Minute), nil), pii.DefaultPolicy,
+		WithOrderMaxAge(time.Minute))
+	require.NoError(t, err)
+
+	do := func(key string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/order/uid-1", nil)
+		req.Header.Set("X-API-Key", key)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do(reader)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	etag := rec.Header().Get("ETag")
+	require.Equal(t, `"`+model.ComputeETag(svc.order)+`-support"`, etag)
+	require.Equal(t, "private, max-age=60", rec.Header().Get("Cache-Control"))
+	require.Equal(t, "Fri, 01 Mar 2024 12:30:45 GMT", rec.Header().Get("Last-Modified"))
+	require.Contains(t, rec.Header().Get("Vary"), "X-API-Key")
+
+	rec = do(reader, "If-None-Match", etag)
+	require.Equal(t, http.StatusNotModified, rec.Code)
+	require.Empty(t, rec.Body.String())
+	require.Equal(t, etag, rec.Header().Get("ETag"))
+
+	require.Equal(t, http.StatusNotModified, do(reader, "If-None-Match", `"x", W/`+etag).Code)
+	require.Equal(t, http.StatusNotModified, do(reader, "If-None-Match", "*").Code)
+	require.Equal(t, http.StatusOK, do(reader, "If-None-Match", `"x"`).Code)
+
+	// Другая роль видит другой JSON, и тег у неё свой.
+	rec = do(admin, "If-None-Match", etag)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, etag, rec.Header().Get("ETag"))
+
+	// If-Modified-Since учитывается, только если нет If-None-Match.
+	since := "Fri, 01 Mar 2024 12:30:45 GMT"
+	require.Equal(t, http.StatusNotModified, do(reader, "If-Modified-Since", since).Code)
+	require.Equal(t, http.StatusOK, do(reader, "If-Modified-Since", "Fri, 01 Mar 2024 12:30:44 GMT").Code)
+	require.Equal(t, http.StatusOK, do(reader, "If-Modified-Since", since, "If-None-Match", `"x"`).Code)
+	require.Equal(t, http.StatusOK, do(reader, "If-Modified-Since", "yesterday").Code)
+}
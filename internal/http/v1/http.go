@@ -2,8 +2,13 @@ package v1
 
 import (
 	gen "app/internal/api/v1"
+	"app/internal/feed"
+	"app/internal/http/compress"
+	"app/internal/pii"
+	"app/internal/ratelimit"
 	"app/internal/service"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -12,16 +17,63 @@ import (
 
 type Handler struct {
 	orderService service.Service
+	eraser       Eraser
+	pii          pii.Policy
+	maxAge       time.Duration
 }
 
-func NewHandler(orderService service.Service) *Handler {
-	return &Handler{orderService: orderService}
+func NewHandler(orderService service.Service, eraser Eraser, policy pii.Policy) *Handler {
+	return &Handler{orderService: orderService, eraser: eraser, pii: policy}
 }
 
-func NewAPI(svc service.Service) (http.Handler, error) {
-	h := NewHandler(svc)
+type apiOptions struct {
+	limiter    *ratelimit.Limiter
+	compressor *compress.Compressor
+	maxAge     time.Duration
+	feed       *feed.Hub
+	keepalive  time.Duration
+	graphql    http.Handler
+}
+
+type APIOption func(*apiOptions)
+
+// WithRateLimiter ограничивает частоту запросов ко всем маршрутам API.
+func WithRateLimiter(l *ratelimit.Limiter) APIOption {
+	return func(o *apiOptions) { o.limiter = l }
+}
+
+// WithCompressor сжимает ответы API по Accept-Encoding.
+func WithCompressor(c *compress.Compressor) APIOption {
+	return func(o *apiOptions) { o.compressor = c }
+}
+
+// WithOrderMaxAge задаёт max-age в Cache-Control ответа с заказом. По
+// умолчанию 0: клиент перепроверяет заказ по ETag при каждом запросе.
+func WithOrderMaxAge(d time.Duration) APIOption {
+	return func(o *apiOptions) { o.maxAge = d }
+}
+
+// WithFeed включает ленту новых заказов: GET /orders/stream (SSE) и
+// GET /orders/ws (WebSocket). keepalive — период ping; 0 — по умолчанию.
+func WithFeed(hub *feed.Hub, keepalive time.Duration) APIOption {
+	return func(o *apiOptions) { o.feed, o.keepalive = hub, keepalive }
+}
 
-	ogenServer, err := gen.NewServer(h)
+// WithGraphQL монтирует GraphQL API (см. internal/graphql) на /graphql
+// за той же аутентификацией, что и операции чтения.
+func WithGraphQL(h http.Handler) APIOption {
+	return func(o *apiOptions) { o.graphql = h }
+}
+
+func NewAPI(svc service.Service, eraser Eraser, sec *Security, policy pii.Policy, opts ...APIOption) (http.Handler, error) {
+	var o apiOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	h := NewHandler(svc, eraser, policy)
+	h.maxAge = o.maxAge
+
+	ogenServer, err := gen.NewServer(h, sec, gen.WithErrorHandler(errorHandler))
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +82,13 @@ func NewAPI(svc service.Service) (http.Handler, error) {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Logger)
+	if o.compressor != nil {
+		r.Use(o.compressor.Handler)
+	}
+	if o.limiter != nil {
+		r.Use(o.limiter.Handler)
+	}
+	r.Use(withAccept)
 
 	r.Get("/openapi.yaml", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/yaml; charset=utf-8")
@@ -49,6 +108,19 @@ func NewAPI(svc service.Service) (http.Handler, error) {
 </html>`))
 	})
 
+	if o.feed != nil {
+		st := &streams{hub: o.feed, pii: policy, keepalive: o.keepalive}
+		if st.keepalive <= 0 {
+			st.keepalive = DefaultKeepalive
+		}
+		r.With(sec.Require("streamOrders", "orders:read")).Get("/orders/stream", st.SSE)
+		r.With(sec.Require("streamOrdersWS", "orders:read")).Get("/orders/ws", st.WebSocket)
+	}
+
+	if o.graphql != nil {
+		r.With(sec.Require("graphql", "orders:read")).Handle("/graphql", o.graphql)
+	}
+
 	r.Mount("/", ogenServer)
 
 	return otelhttp.NewHandler(r, "http"), nil
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	"app/internal/api/pb/orderv1"
+	gen "app/internal/api/v1"
+	"app/internal/auth"
+	"app/internal/converter"
+	"app/internal/pii"
+	"bytes"
+	"context"
+
+	"github.com/go-faster/jx"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+func (h *Handler) GetOrders(ctx context.Context, params gen.GetOrdersParams) (gen.GetOrdersRes, error) {
+	ctx, span := httpTracer.Start(ctx, "v1.GetOrders",
+		trace.WithAttributes(attribute.Int("orders.requested", len(params.UID))),
+	)
+	defer span.End()
+
+	// Сервис сохраняет порядок запроса и пропускает неизвестные и повторные UID.
+	orders, err := h.orderService.GetMany(ctx, params.UID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "service error")
+		return nil, err
+	}
+
+	id, _ := auth.FromContext(ctx)
+	view := h.pii.For(pii.RoleFromScopes(id.Scopes))
+	media := negotiate(ctx, mediaJSON, mediaNDJSON, mediaMsgpack, mediaProtobuf)
+	span.SetAttributes(
+		attribute.Int("orders.found", len(orders)),
+		attribute.String("pii.role", string(view.Role())),
+		attribute.String("http.response.media_type", media),
+	)
+
+	res := make([]gen.Order, len(orders))
+	for i, o := range orders {
+		res[i] = converter.ModelOrderToGen(o, view)
+	}
+	span.SetStatus(codes.Ok, "ok")
+
+	switch media {
+	case mediaNDJSON:
+		var buf bytes.Buffer
+		for i := range res {
+			buf.Write(jsonBytes(&res[i]))
+			buf.WriteByte('\n')
+		}
+		return &gen.GetOrdersOKApplicationXNdjsonHeaders{
+			Vary:     vary,
+			Response: gen.GetOrdersOKApplicationXNdjson{Data: &buf},
+		}, nil
+	case mediaMsgpack:
+		body, err := toMsgpack(jsonBytes(orderArray(res)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "encode failed")
+			return nil, err
+		}
+		return &gen.GetOrdersOKApplicationMsgpackHeaders{
+			Vary:     vary,
+			Response: gen.GetOrdersOKApplicationMsgpack{Data: body},
+		}, nil
+	case mediaProtobuf:
+		list := &orderv1.OrderList{Orders: make([]*orderv1.Order, len(orders))}
+		for i, o := range orders {
+			list.Orders[i] = converter.ModelOrderToProto(o, view)
+		}
+		body, err := proto.Marshal(list)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "encode failed")
+			return nil, err
+		}
+		return &gen.GetOrdersOKApplicationProtobufHeaders{
+			Vary:     vary,
+			Response: gen.GetOrdersOKApplicationProtobuf{Data: bytes.NewReader(body)},
+		}, nil
+	}
+	return &gen.GetOrdersOKHeaders{Vary: vary, Response: res}, nil
+}
+
+// orderArray кодирует список заказов JSON-массивом.
+type orderArray []gen.Order
+
+func (a orderArray) Encode(e *jx.Encoder) {
+	e.ArrStart()
+	for i := range a {
+		a[i].Encode(e)
+	}
+	e.ArrEnd()
+}
@@ -0,0 +1,130 @@
+package v1
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-faster/jx"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Представления заказа, которые можно запросить заголовком Accept.
+const (
+	mediaJSON     = "application/json"
+	mediaNDJSON   = "application/x-ndjson"
+	mediaMsgpack  = "application/msgpack"
+	mediaProtobuf = "application/protobuf"
+)
+
+type acceptKey struct{}
+
+// withAccept кладёт Accept в контекст: ogen не передаёт его обработчикам.
+func withAccept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if accept := r.Header.Get("Accept"); accept != "" {
+			r = r.WithContext(context.WithValue(r.Context(), acceptKey{}, accept))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// negotiate выбирает из offers представление с наибольшим q в Accept;
+// при равных q — более раннее. Без Accept или без совпадений отдаётся
+// первое (JSON): лучше ответить в формате по умолчанию, чем 406.
+func negotiate(ctx context.Context, offers ...string) string {
+	accept, _ := ctx.Value(acceptKey{}).(string)
+	if accept == "" {
+		return offers[0]
+	}
+
+	best, bestQ, bestSpec := offers[0], 0.0, -1
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		for _, offer := range offers {
+			spec := specificity(mt, offer)
+			if spec < 0 {
+				continue
+			}
+			// Точное совпадение важнее маски с тем же q.
+			if q > bestQ || q == bestQ && spec > bestSpec {
+				best, bestQ, bestSpec = offer, q, spec
+			}
+			break
+		}
+	}
+	return best
+}
+
+// specificity: 2 — точное совпадение, 1 — type/*, 0 — */*, -1 — не подходит.
+func specificity(pattern, offer string) int {
+	switch {
+	case pattern == offer:
+		return 2
+	case pattern == "*/*":
+		return 0
+	case strings.HasSuffix(pattern, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(pattern, "*")):
+		return 1
+	}
+	return -1
+}
+
+// jsonBytes кодирует ogen-модель в JSON.
+func jsonBytes(v interface{ Encode(*jx.Encoder) }) []byte {
+	var e jx.Encoder
+	v.Encode(&e)
+	return e.Bytes()
+}
+
+// toMsgpack перекладывает JSON в MessagePack: поля и пропуски совпадают с
+// JSON-представлением, целые остаются целыми.
+func toMsgpack(js []byte) (io.Reader, error) {
+	d := json.NewDecoder(bytes.NewReader(js))
+	d.UseNumber()
+	var v any
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	b, err := msgpack.Marshal(fromJSONNumbers(v))
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(b), nil
+}
+
+func fromJSONNumbers(v any) any {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]any:
+		for k, e := range x {
+			x[k] = fromJSONNumbers(e)
+		}
+	case []any:
+		for i, e := range x {
+			x[i] = fromJSONNumbers(e)
+		}
+	}
+	return v
+}
@@ -0,0 +1,152 @@
+package v1
+
+import (
+	"app/internal/api/pb/orderv1"
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNegotiate(t *testing.T) {
+	ctx := func(accept string) context.Context {
+		return context.WithValue(context.Background(), acceptKey{}, accept)
+	}
+	offers := []string{mediaJSON, mediaMsgpack, mediaProtobuf}
+
+	require.Equal(t, mediaJSON, negotiate(context.Background(), offers...))
+	require.Equal(t, mediaJSON, negotiate(ctx("*/*"), offers...))
+	require.Equal(t, mediaMsgpack, negotiate(ctx("application/msgpack"), offers...))
+	require.Equal(t, mediaProtobuf, negotiate(ctx("application/json;q=0.5, application/protobuf"), offers...))
+	require.Equal(t, mediaMsgpack, negotiate(ctx("application/msgpack, */*;q=0.1"), offers...))
+	// Точный тип важнее маски с тем же q.
+	require.Equal(t, mediaProtobuf, negotiate(ctx("application/*, application/protobuf"), offers...))
+	// Ничего подходящего — JSON.
+	require.Equal(t, mediaJSON, negotiate(ctx("text/html"), offers...))
+	require.Equal(t, mediaJSON, negotiate(ctx("application/msgpack;q=0"), offers...))
+}
+
+type mapService map[string]model.Order
+
+func (s mapService) ProcessOrder(context.Context, model.Order) error { return nil }
+
+func (s mapService) List(context.Context, model.OrderFilter) ([]model.Order, error) { return nil, nil }
+
+func (s mapService) GetMany(ctx context.Context, uids []string) ([]model.Order, error) {
+	var out []model.Order
+	for _, uid := range uids {
+		if o, err := s.Get(ctx, uid); err == nil && !slices.ContainsFunc(out, func(p model.Order) bool {
+			return p.OrderUUID == uid
+		}) {
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+func (s mapService) Get(_ context.Context, uid string) (model.Order, error) {
+	o, ok := s[uid]
+	if !ok {
+		return model.Order{}, model.ErrNotFound
+	}
+	return o, nil
+}
+
+func TestAPI_Representations(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	key, hash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	store := fakeKeyStore{string(hash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}}}
+
+	created := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc := mapService{
+		"uid-1": {OrderUUID: "uid-1", DateCreated: created, SmID: 99,
+			Delivery: model.Delivery{Phone: "+79991234567"}, Items: []model.Item{{ChrtID: 7, Price: 453}}},
+		"uid-2": {OrderUUID: "uid-2", DateCreated: created},
+	}
+	api, err := NewAPI(svc, &fakeEraser{}, NewSecurity(auth.NewAPIKeys(store, time.Minute), nil), pii.DefaultPolicy)
+	require.NoError(t, err)
+
+	do := func(target, accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.Header.Set("X-API-Key", key)
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("/order/uid-1", "application/msgpack")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, mediaMsgpack, rec.Header().Get("Content-Type"))
+	require.Contains(t, rec.Header().Get("Vary"), "Accept")
+	require.True(t, strings.HasSuffix(rec.Header().Get("ETag"), `-support-msgpack"`))
+	var m map[string]any
+	require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &m))
+	require.Equal(t, "uid-1", m["order_uid"])
+	require.EqualValues(t, 99, m["sm_id"])
+	require.Equal(t, "+7***4567", m["delivery"].(map[string]any)["phone"])
+	require.NotContains(t, m, "internal_signature")
+
+	rec = do("/order/uid-1", "application/protobuf")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, mediaProtobuf, rec.Header().Get("Content-Type"))
+	var pb orderv1.Order
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &pb))
+	require.Equal(t, "uid-1", pb.GetOrderUid())
+	require.Equal(t, "+7***4567", pb.GetDelivery().GetPhone())
+	require.Equal(t, created, pb.GetDateCreated().AsTime())
+
+	require.Equal(t, http.StatusNotFound, do("/order/missing", "").Code)
+
+	// Список: неизвестные и повторные UID пропускаются.
+	list := "/orders?uid=uid-2&uid=missing&uid=uid-1&uid=uid-2"
+	rec = do(list, "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var orders []map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orders))
+	require.Len(t, orders, 2)
+	require.Equal(t, "uid-2", orders[0]["order_uid"])
+
+	rec = do(list, "application/x-ndjson")
+	require.Equal(t, mediaNDJSON, rec.Header().Get("Content-Type"))
+	var lines []string
+	sc := bufio.NewScanner(rec.Body)
+	for sc.Scan() {
+		lines = append(lines, sc.Text())
+	}
+	require.Len(t, lines, 2)
+	require.Contains(t, lines[1], `"order_uid":"uid-1"`)
+
+	rec = do(list, "application/msgpack")
+	var arr []map[string]any
+	require.NoError(t, msgpack.Unmarshal(rec.Body.Bytes(), &arr))
+	require.Len(t, arr, 2)
+
+	rec = do(list, "application/protobuf")
+	var pl orderv1.OrderList
+	require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), &pl))
+	require.Len(t, pl.GetOrders(), 2)
+	require.Equal(t, "uid-1", pl.GetOrders()[1].GetOrderUid())
+
+	rec = do("/orders?uid="+strings.Repeat("x&uid=", 100)+"x", "")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), `"message"`)
+	require.Equal(t, http.StatusBadRequest, do("/orders", "").Code)
+}
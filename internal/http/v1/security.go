@@ -0,0 +1,148 @@
+package v1
+
+import (
+	gen "app/internal/api/v1"
+	"app/internal/auth"
+	"app/internal/logger"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/ogen-go/ogen/ogenerrors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// Security реализует схемы безопасности из openapi.yaml. nil-проверяльщик
+// означает, что схема выключена в конфиге: такие учётные данные отклоняются.
+type Security struct {
+	keys *auth.APIKeys
+	jwt  *auth.JWT
+}
+
+func NewSecurity(keys *auth.APIKeys, jwt *auth.JWT) *Security {
+	return &Security{keys: keys, jwt: jwt}
+}
+
+func (s *Security) HandleApiKeyAuth(ctx context.Context, op gen.OperationName, t gen.ApiKeyAuth) (context.Context, error) {
+	if s.keys == nil {
+		return ctx, auth.ErrUnauthenticated
+	}
+	id, err := s.keys.Verify(ctx, t.APIKey)
+	return s.authorize(ctx, op, id, err, t.Roles)
+}
+
+func (s *Security) HandleBearerAuth(ctx context.Context, op gen.OperationName, t gen.BearerAuth) (context.Context, error) {
+	if s.jwt == nil {
+		return ctx, auth.ErrUnauthenticated
+	}
+	id, err := s.jwt.Verify(ctx, t.Token)
+	return s.authorize(ctx, op, id, err, t.Roles)
+}
+
+// authorize проверяет scopes и кладёт Identity в ctx запроса, в поля логов
+// и в атрибуты span'а.
+func (s *Security) authorize(ctx context.Context, op gen.OperationName, id auth.Identity, err error, scopes []string) (context.Context, error) {
+	span := trace.SpanFromContext(ctx)
+	if errors.Is(err, auth.ErrUnavailable) {
+		logger.Error(ctx, "authentication unavailable", zap.String("operation", op), zap.Error(err))
+		return ctx, err
+	}
+	if err != nil {
+		logger.Warn(ctx, "authentication failed", zap.String("operation", op), zap.Error(err))
+		return ctx, err
+	}
+
+	span.SetAttributes(
+		attribute.String("enduser.id", id.Subject),
+		attribute.String("auth.method", id.Method),
+	)
+	ctx = logger.WithFields(ctx, zap.String("caller", id.Subject), zap.String("auth_method", id.Method))
+
+	if !id.HasScopes(scopes...) {
+		logger.Warn(ctx, "authorization failed", zap.String("operation", op), zap.Strings("required_scopes", scopes))
+		return ctx, auth.ErrForbidden
+	}
+	return auth.WithIdentity(ctx, id), nil
+}
+
+// Require защищает маршруты вне openapi.yaml (потоки заказов) теми же
+// схемами и scopes, что и операции ogen, и отвечает теми же ошибками.
+func (s *Security) Require(op string, scopes ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id, err := s.Authenticate(r)
+			ctx, err := s.authorize(r.Context(), op, id, err, scopes)
+			switch {
+			case errors.Is(err, auth.ErrForbidden):
+				writeError(w, http.StatusForbidden, "insufficient scope")
+			case errors.Is(err, auth.ErrUnavailable):
+				writeError(w, http.StatusServiceUnavailable, "authentication unavailable")
+			case err != nil:
+				w.Header().Set("WWW-Authenticate", wwwAuthenticate)
+				writeError(w, http.StatusUnauthorized, "missing or invalid credentials")
+			default:
+				next.ServeHTTP(w, r.WithContext(ctx))
+			}
+		})
+	}
+}
+
+// Authenticate проверяет учётные данные запроса без проверки scopes:
+// X-API-Key, если он есть, иначе Bearer-токен.
+func (s *Security) Authenticate(r *http.Request) (auth.Identity, error) {
+	ctx := r.Context()
+	switch key, tok := r.Header.Get("X-API-Key"), bearer(r); {
+	case key != "" && s.keys != nil:
+		return s.keys.Verify(ctx, key)
+	case key == "" && tok != "" && s.jwt != nil:
+		return s.jwt.Verify(ctx, tok)
+	}
+	return auth.Identity{}, auth.ErrUnauthenticated
+}
+
+func bearer(r *http.Request) string {
+	if tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return tok
+	}
+	return ""
+}
+
+const wwwAuthenticate = `Bearer, ApiKey header="X-API-Key"`
+
+// errorHandler отвечает на ошибки безопасности телом Error из openapi.yaml:
+// нехватка scope — 403, недоступное хранилище ключей — 503, прочие — 401
+// с WWW-Authenticate. Остальные ошибки обрабатываются ogen по умолчанию.
+func errorHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	var (
+		code   int
+		msg    string
+		secErr *ogenerrors.SecurityError
+		parErr *ogenerrors.DecodeParamsError
+	)
+	switch {
+	case errors.Is(err, auth.ErrForbidden):
+		code, msg = http.StatusForbidden, "insufficient scope"
+	case errors.Is(err, auth.ErrUnavailable):
+		code, msg = http.StatusServiceUnavailable, "authentication unavailable"
+	case errors.As(err, &secErr):
+		code, msg = http.StatusUnauthorized, "missing or invalid credentials"
+		w.Header().Set("WWW-Authenticate", wwwAuthenticate)
+	case errors.As(err, &parErr):
+		code, msg = http.StatusBadRequest, parErr.Error()
+	default:
+		ogenerrors.DefaultErrorHandler(ctx, w, r, err)
+		return
+	}
+
+	writeError(w, code, msg)
+}
+
+func writeError(w http.ResponseWriter, code int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(gen.Error{Message: msg})
+}
@@ -0,0 +1,173 @@
+package v1
+
+import (
+	"app/internal/auth"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"app/internal/ratelimit"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeService struct {
+	caller auth.Identity
+}
+
+func (f *fakeService) ProcessOrder(context.Context, model.Order) error { return nil }
+
+func (f *fakeService) List(context.Context, model.OrderFilter) ([]model.Order, error) {
+	return nil, nil
+}
+
+func (f *fakeService) GetMany(context.Context, []string) ([]model.Order, error) {
+	return nil, nil
+}
+
+func (f *fakeService) Get(ctx context.Context, uuid string) (model.Order, error) {
+	f.caller, _ = auth.FromContext(ctx)
+	return model.Order{
+		OrderUUID:   uuid,
+		DateCreated: time.Now(),
+		Delivery:    model.Delivery{Phone: "+79991234567"},
+		Payment:     model.Payment{Transaction: "tx-1"},
+	}, nil
+}
+
+type fakeKeyStore map[string]model.APIKey
+
+func (f fakeKeyStore) Lookup(_ context.Context, hash []byte) (model.APIKey, error) {
+	k, ok := f[string(hash)]
+	if !ok {
+		return model.APIKey{}, model.ErrNotFound
+	}
+	return k, nil
+}
+
+type failingKeyStore struct{}
+
+func (failingKeyStore) Lookup(context.Context, []byte) (model.APIKey, error) {
+	return model.APIKey{}, errors.New("connection refused")
+}
+
+// Недоступное хранилище ключей — сбой сервиса: клиент получает 503, а не 401,
+// и не начинает считать свой ключ отозванным.
+func TestAPI_Security_KeyStoreUnavailable(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	key, _, err := auth.GenerateKey()
+	require.NoError(t, err)
+	api, err := NewAPI(&fakeService{}, &fakeEraser{}, NewSecurity(auth.NewAPIKeys(failingKeyStore{}, time.Minute), nil), pii.DefaultPolicy)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/order/uid-1", nil)
+	req.Header.Set("X-API-Key", key)
+	rec := httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Empty(t, rec.Header().Get("WWW-Authenticate"))
+	require.JSONEq(t, `{"message":"authentication unavailable"}`, rec.Body.String())
+}
+
+func TestAPI_Security(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	reader, readerHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	other, otherHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	admin, adminHash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	store := fakeKeyStore{
+		string(readerHash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}},
+		string(otherHash):  {ID: 2, Name: "other", Scopes: []string{"billing:read"}},
+		string(adminHash):  {ID: 3, Name: "admin", Scopes: []string{"orders:read", pii.ScopeFull}},
+	}
+
+	svc := &fakeService{}
+	api, err := NewAPI(svc, &fakeEraser{}, NewSecurity(auth.NewAPIKeys(store, time.Minute), nil), pii.DefaultPolicy)
+	require.NoError(t, err)
+
+	do := func(header, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/order/uid-1", nil)
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("", "")
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+	require.JSONEq(t, `{"message":"missing or invalid credentials"}`, rec.Body.String())
+
+	require.Equal(t, http.StatusUnauthorized, do("X-API-Key", auth.KeyPrefix+"unknown").Code)
+	// JWT выключен: bearer-токен не принимается.
+	require.Equal(t, http.StatusUnauthorized, do("Authorization", "Bearer a.b.c").Code)
+
+	rec = do("X-API-Key", other)
+	require.Equal(t, http.StatusForbidden, rec.Code)
+	require.JSONEq(t, `{"message":"insufficient scope"}`, rec.Body.String())
+
+	rec = do("X-API-Key", reader)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Equal(t, "apikey:1", svc.caller.Subject)
+	require.Equal(t, auth.MethodAPIKey, svc.caller.Method)
+	// Без orders:pii персональные поля маскируются или не отдаются.
+	require.Contains(t, rec.Body.String(), `"phone":"+7***4567"`)
+	require.NotContains(t, rec.Body.String(), `"transaction"`)
+
+	rec = do("X-API-Key", admin)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	require.Contains(t, rec.Body.String(), `"phone":"+79991234567"`)
+	require.Contains(t, rec.Body.String(), `"transaction":"tx-1"`)
+
+	// Web UI доступен без учётных данных.
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec = httptest.NewRecorder()
+	api.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+// Лимитер считает клиента по subject проверенного ключа; выдуманный ключ
+// остаётся в бакете своего IP.
+func TestAPI_RateLimitByAuthenticatedSubject(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	key, hash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	sec := NewSecurity(auth.NewAPIKeys(fakeKeyStore{string(hash): {ID: 1, Name: "reader", Scopes: []string{"orders:read"}}}, time.Minute), nil)
+
+	rules, err := ratelimit.ParseRules("1/m", nil)
+	require.NoError(t, err)
+	limiter := ratelimit.New(ratelimit.NewMemory(), rules, ratelimit.Identify(func(r *http.Request) (string, bool) {
+		id, err := sec.Authenticate(r)
+		return id.Subject, err == nil
+	}))
+
+	api, err := NewAPI(&fakeService{}, &fakeEraser{}, sec, pii.DefaultPolicy, WithRateLimiter(limiter))
+	require.NoError(t, err)
+
+	do := func(apiKey, addr string) int {
+		req := httptest.NewRequest(http.MethodGet, "/order/uid-1", nil)
+		req.RemoteAddr = addr
+		req.Header.Set("X-API-Key", apiKey)
+		rec := httptest.NewRecorder()
+		api.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	require.Equal(t, http.StatusUnauthorized, do("wbo_bogus", "10.0.0.1:1"))
+	require.Equal(t, http.StatusTooManyRequests, do("wbo_other", "10.0.0.1:1"))
+	require.Equal(t, http.StatusOK, do(key, "10.0.0.1:1"))
+	require.Equal(t, http.StatusTooManyRequests, do(key, "10.0.0.2:1"))
+}
@@ -21,7 +21,10 @@ const indexHTML = `<!doctype html>
 <body>
 <h1>Поиск заказа</h1>
 
-<label for="order-id">ID заказа (order_uid)</label>
+<label for="api-key">API-ключ (X-API-Key)</label>
+<input id="api-key" type="text" placeholder="wbo_..." autocomplete="off" />
+
+<label for="order-id" style="margin-top: 12px;">ID заказа (order_uid)</label>
 <input id="order-id" type="text" placeholder="b563feb7b2b84b6test" />
 
 <div>
@@ -33,10 +36,15 @@ const indexHTML = `<!doctype html>
 
 <script>
   const input = document.getElementById('order-id');
+  const keyInput = document.getElementById('api-key');
   const btn = document.getElementById('load-order');
   const errorBox = document.getElementById('error');
   const resultBox = document.getElementById('result');
 
+  // Ключ хранится только в sessionStorage этой вкладки.
+  keyInput.value = sessionStorage.getItem('apiKey') || '';
+  keyInput.addEventListener('change', () => sessionStorage.setItem('apiKey', keyInput.value.trim()));
+
   btn.addEventListener('click', async () => {
     const id = input.value.trim();
     errorBox.textContent = '';
@@ -49,7 +57,9 @@ const indexHTML = `<!doctype html>
     }
 
     try {
-      const resp = await fetch('/order/' + encodeURIComponent(id));
+      const resp = await fetch('/order/' + encodeURIComponent(id), {
+        headers: { 'X-API-Key': keyInput.value.trim() }
+      });
 
       if (!resp.ok) {
         const text = await resp.text();
@@ -0,0 +1,223 @@
+package v1
+
+import (
+	"app/internal/auth"
+	"app/internal/converter"
+	"app/internal/feed"
+	"app/internal/logger"
+	"app/internal/pii"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/go-faster/jx"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultKeepalive — как часто поток шлёт ping, чтобы прокси не
+	// закрывали простаивающее соединение.
+	DefaultKeepalive = 15 * time.Second
+	// wsWriteTimeout — сколько ждать записи одного сообщения WebSocket.
+	wsWriteTimeout = 10 * time.Second
+	// sseRetry — через сколько миллисекунд EventSource переподключается.
+	sseRetry = "3000"
+)
+
+// streams отдаёт ленту новых заказов через SSE и WebSocket.
+type streams struct {
+	hub       *feed.Hub
+	pii       pii.Policy
+	keepalive time.Duration
+}
+
+// subscribe подписывает запрос на ленту с фильтром из query и продолжением
+// с Last-Event-ID (заголовок или параметр last_event_id: EventSource
+// и браузерный WebSocket не умеют задавать заголовки при первом запросе).
+func (st *streams) subscribe(w http.ResponseWriter, r *http.Request, transport string) (*feed.Subscriber, bool) {
+	q := r.URL.Query()
+	f := feed.Filter{
+		DeliveryService: q.Get("delivery_service"),
+		CustomerID:      q.Get("customer_id"),
+		Region:          q.Get("region"),
+	}
+	last := r.Header.Get("Last-Event-ID")
+	if last == "" {
+		last = q.Get("last_event_id")
+	}
+
+	sub, err := st.hub.Subscribe(r.Context(), f, last, transport)
+	if err != nil {
+		logger.Warn(r.Context(), "feed subscribe rejected", zap.String("transport", transport), zap.Error(err))
+		w.Header().Set("Retry-After", "5")
+		writeError(w, http.StatusServiceUnavailable, "order feed is unavailable")
+		return nil, false
+	}
+	return sub, true
+}
+
+func (st *streams) view(ctx context.Context) pii.View {
+	id, _ := auth.FromContext(ctx)
+	return st.pii.For(pii.RoleFromScopes(id.Scopes))
+}
+
+// SSE — GET /orders/stream. Событие order несёт заказ в JSON, как
+// GET /order/{uid}; событие gap сообщает, что часть заказов после
+// Last-Event-ID уже недоступна.
+func (st *streams) SSE(w http.ResponseWriter, r *http.Request) {
+	sub, ok := st.subscribe(w, r, "sse")
+	if !ok {
+		return
+	}
+	ctx := r.Context()
+	defer st.hub.Unsubscribe(context.WithoutCancel(ctx), sub)
+
+	rc := http.NewResponseController(w)
+	// Поток живёт дольше http.write_timeout.
+	_ = rc.SetWriteDeadline(time.Time{})
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	view := st.view(ctx)
+	var buf bytes.Buffer
+	buf.WriteString("retry: " + sseRetry + "\n\n")
+	if sub.Gap {
+		buf.WriteString("event: gap\ndata: {}\n\n")
+	}
+	for _, e := range sub.Backlog {
+		writeSSE(&buf, e, view)
+	}
+
+	tick := time.NewTicker(st.keepalive)
+	defer tick.Stop()
+	for {
+		if buf.Len() > 0 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+			buf.Reset()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-sub.C:
+			writeSSE(&buf, e, view)
+		case <-tick.C:
+			buf.WriteString(": ping\n\n")
+		case <-sub.Done():
+			// Дописываем то, что успело попасть в буфер подписчика:
+			// клиент продолжит с последнего полученного ID.
+			drain(sub, func(e feed.Event) { writeSSE(&buf, e, view) })
+			_, _ = w.Write(buf.Bytes())
+			_ = rc.Flush()
+			return
+		}
+	}
+}
+
+func writeSSE(w io.Writer, e feed.Event, view pii.View) {
+	res := converter.ModelOrderToGen(e.Order, view)
+	_, _ = io.WriteString(w, "id: "+e.ID+"\nevent: order\ndata: ")
+	_, _ = w.Write(jsonBytes(&res))
+	_, _ = io.WriteString(w, "\n\n")
+}
+
+// WebSocket — GET /orders/ws, то же, что SSE: сообщения
+// {"type":"order","id":…,"order":{…}} и {"type":"gap"}. Медленный
+// подписчик отключается с кодом 1013, при shutdown — 1001.
+func (st *streams) WebSocket(w http.ResponseWriter, r *http.Request) {
+	sub, ok := st.subscribe(w, r, "ws")
+	if !ok {
+		return
+	}
+	defer st.hub.Unsubscribe(context.WithoutCancel(r.Context()), sub)
+
+	conn, err := websocket.Accept(w, r, nil)
+	if err != nil {
+		// Accept уже ответил клиенту.
+		return
+	}
+	defer conn.CloseNow()
+
+	// Клиент ничего не присылает; CloseRead отвечает на ping и close и
+	// отменяет ctx, когда соединение закрыто.
+	ctx := conn.CloseRead(r.Context())
+	view := st.view(ctx)
+
+	send := func(msg []byte) bool {
+		wctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+		defer cancel()
+		return conn.Write(wctx, websocket.MessageText, msg) == nil
+	}
+
+	if sub.Gap && !send([]byte(`{"type":"gap"}`)) {
+		return
+	}
+	for _, e := range sub.Backlog {
+		if !send(wsMessage(e, view)) {
+			return
+		}
+	}
+
+	tick := time.NewTicker(st.keepalive)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case e := <-sub.C:
+			if !send(wsMessage(e, view)) {
+				return
+			}
+		case <-tick.C:
+			pctx, cancel := context.WithTimeout(ctx, wsWriteTimeout)
+			err := conn.Ping(pctx)
+			cancel()
+			if err != nil {
+				return
+			}
+		case <-sub.Done():
+			drain(sub, func(e feed.Event) { send(wsMessage(e, view)) })
+			code, reason := websocket.StatusGoingAway, "server shutting down"
+			if sub.Reason() == feed.ReasonSlow {
+				code, reason = websocket.StatusTryAgainLater, "slow consumer"
+			}
+			_ = conn.Close(code, reason)
+			return
+		}
+	}
+}
+
+func wsMessage(e feed.Event, view pii.View) []byte {
+	res := converter.ModelOrderToGen(e.Order, view)
+	var enc jx.Encoder
+	enc.Obj(func(enc *jx.Encoder) {
+		enc.Field("type", func(enc *jx.Encoder) { enc.Str("order") })
+		enc.Field("id", func(enc *jx.Encoder) { enc.Str(e.ID) })
+		enc.Field("order", func(enc *jx.Encoder) { res.Encode(enc) })
+	})
+	return enc.Bytes()
+}
+
+// drain отдаёт события, оставшиеся в буфере отключённого подписчика.
+func drain(sub *feed.Subscriber, fn func(feed.Event)) {
+	for {
+		select {
+		case e := <-sub.C:
+			fn(e)
+		default:
+			return
+		}
+	}
+}
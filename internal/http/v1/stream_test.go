@@ -0,0 +1,146 @@
+package v1
+
+import (
+	"app/internal/auth"
+	"app/internal/feed"
+	"app/internal/logger"
+	"app/internal/model"
+	"app/internal/pii"
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/stretchr/testify/require"
+)
+
+// readEvent читает одно SSE-событие, пропуская комментарии и retry.
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	t.Helper()
+	ev := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			if _, ok := ev["event"]; ok {
+				return ev
+			}
+			continue
+		}
+		if k, v, ok := strings.Cut(line, ": "); ok && k != "" {
+			ev[k] = v
+		}
+	}
+}
+
+func TestAPI_OrderStream(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	key, hash, err := auth.GenerateKey()
+	require.NoError(t, err)
+	keys := auth.NewAPIKeys(fakeKeyStore{string(hash): {ID: 1, Name: "dash", Scopes: []string{"orders:read"}}}, time.Minute)
+
+	hub := feed.NewHub(feed.WithBuffer(2))
+	api, err := NewAPI(&fakeService{}, &fakeEraser{}, NewSecurity(keys, nil), pii.DefaultPolicy,
+		WithFeed(hub, time.Hour))
+	require.NoError(t, err)
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	order := func(uid, ds string) model.Order {
+		return model.Order{OrderUUID: uid, DeliveryService: ds, Delivery: model.Delivery{Phone: "+79991234567"}}
+	}
+	open := func(query, lastID string) (*http.Response, context.CancelFunc) {
+		ctx, cancel := context.WithCancel(context.Background())
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/orders/stream"+query, nil)
+		req.Header.Set("X-API-Key", key)
+		if lastID != "" {
+			req.Header.Set("Last-Event-ID", lastID)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return res, cancel
+	}
+
+	// Без учётных данных — 401, как у операций openapi.yaml.
+	res, err := http.Get(srv.URL + "/orders/stream")
+	require.NoError(t, err)
+	res.Body.Close()
+	require.Equal(t, http.StatusUnauthorized, res.StatusCode)
+
+	// SSE: только заказы фильтра, PII маскирован по роли.
+	res, cancel := open("?delivery_service=meest", "")
+	require.Equal(t, http.StatusOK, res.StatusCode)
+	require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+	body := bufio.NewReader(res.Body)
+
+	// Подписка регистрируется до отправки заголовков ответа.
+	hub.Publish(context.Background(), order("skip", "dpd"))
+	hub.Publish(context.Background(), order("a", "meest"))
+	ev := readEvent(t, body)
+	require.Equal(t, "order", ev["event"])
+	var got struct {
+		OrderUID string `json:"order_uid"`
+		Delivery struct {
+			Phone string `json:"phone"`
+		} `json:"delivery"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(ev["data"]), &got))
+	require.Equal(t, "a", got.OrderUID)
+	require.Equal(t, pii.MaskPhone("+79991234567"), got.Delivery.Phone)
+	cancel()
+	res.Body.Close()
+
+	// Продолжение с Last-Event-ID: пропущенные заказы приходят из кольца.
+	hub.Publish(context.Background(), order("b", "meest"))
+	res, cancel = open("", ev["id"])
+	body = bufio.NewReader(res.Body)
+	first := readEvent(t, body)
+	require.Contains(t, first["data"], `"order_uid":"b"`)
+	cancel()
+	res.Body.Close()
+
+	// Неизвестный ID — событие gap и всё кольцо.
+	res, cancel = open("?delivery_service=meest", "other-1")
+	body = bufio.NewReader(res.Body)
+	require.Equal(t, "gap", readEvent(t, body)["event"])
+	require.Contains(t, readEvent(t, body)["data"], `"order_uid":"a"`)
+	cancel()
+	res.Body.Close()
+
+	// WebSocket: то же сообщение; при shutdown соединение закрывается с 1001.
+	ctx := context.Background()
+	conn, _, err := websocket.Dial(ctx, "ws"+strings.TrimPrefix(srv.URL, "http")+"/orders/ws?delivery_service=meest",
+		&websocket.DialOptions{HTTPHeader: http.Header{"X-API-Key": {key}}})
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	hub.Publish(ctx, order("c", "meest"))
+	_, msg, err := conn.Read(ctx)
+	require.NoError(t, err)
+	var wsMsg struct {
+		Type  string `json:"type"`
+		ID    string `json:"id"`
+		Order struct {
+			OrderUID string `json:"order_uid"`
+		} `json:"order"`
+	}
+	require.NoError(t, json.Unmarshal(msg, &wsMsg))
+	require.Equal(t, "order", wsMsg.Type)
+	require.Equal(t, "c", wsMsg.Order.OrderUID)
+	require.NotEmpty(t, wsMsg.ID)
+
+	require.NoError(t, hub.Close(ctx))
+	for {
+		if _, _, err = conn.Read(ctx); err != nil {
+			break
+		}
+	}
+	require.Equal(t, websocket.StatusGoingAway, websocket.CloseStatus(err))
+}
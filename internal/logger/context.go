@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+type fieldsKey struct{}
+
+// WithFields возвращает ctx, записи с которым дополняются fields:
+// так в логи запроса попадает, например, вызывающий клиент.
+func WithFields(ctx context.Context, fields ...zap.Field) context.Context {
+	prev := fieldsFrom(ctx)
+	all := make([]zap.Field, 0, len(prev)+len(fields))
+	all = append(append(all, prev...), fields...)
+	return context.WithValue(ctx, fieldsKey{}, all)
+}
+
+func fieldsFrom(ctx context.Context) []zap.Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]zap.Field)
+	return fields
+}
+
+// withContext добавляет к fields поля из ctx.
+func withContext(ctx context.Context, fields []zap.Field) []zap.Field {
+	ctxFields := fieldsFrom(ctx)
+	if len(ctxFields) == 0 {
+		return fields
+	}
+	out := make([]zap.Field, 0, len(ctxFields)+len(fields))
+	return append(append(out, ctxFields...), fields...)
+}
@@ -82,43 +82,43 @@ func Logger() *logger {
 }
 
 func Debug(ctx context.Context, msg string, fields ...zap.Field) {
-	globalLogger.zapLogger.Debug(msg, fields...)
+	globalLogger.zapLogger.Debug(msg, withContext(ctx, fields)...)
 }
 
 func Info(ctx context.Context, msg string, fields ...zap.Field) {
-	globalLogger.zapLogger.Info(msg, fields...)
+	globalLogger.zapLogger.Info(msg, withContext(ctx, fields)...)
 }
 
 func Warn(ctx context.Context, msg string, fields ...zap.Field) {
-	globalLogger.zapLogger.Warn(msg, fields...)
+	globalLogger.zapLogger.Warn(msg, withContext(ctx, fields)...)
 }
 
 func Error(ctx context.Context, msg string, fields ...zap.Field) {
-	globalLogger.zapLogger.Error(msg, fields...)
+	globalLogger.zapLogger.Error(msg, withContext(ctx, fields)...)
 }
 
 func Fatal(ctx context.Context, msg string, fields ...zap.Field) {
-	globalLogger.zapLogger.Fatal(msg, fields...)
+	globalLogger.zapLogger.Fatal(msg, withContext(ctx, fields)...)
 }
 
 func (l *logger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
-	l.zapLogger.Debug(msg, fields...)
+	l.zapLogger.Debug(msg, withContext(ctx, fields)...)
 }
 
 func (l *logger) Info(ctx context.Context, msg string, fields ...zap.Field) {
-	l.zapLogger.Info(msg, fields...)
+	l.zapLogger.Info(msg, withContext(ctx, fields)...)
 }
 
 func (l *logger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
-	l.zapLogger.Warn(msg, fields...)
+	l.zapLogger.Warn(msg, withContext(ctx, fields)...)
 }
 
 func (l *logger) Error(ctx context.Context, msg string, fields ...zap.Field) {
-	l.zapLogger.Error(msg, fields...)
+	l.zapLogger.Error(msg, withContext(ctx, fields)...)
 }
 
 func (l *logger) Fatal(ctx context.Context, msg string, fields ...zap.Field) {
-	l.zapLogger.Fatal(msg, fields...)
+	l.zapLogger.Fatal(msg, withContext(ctx, fields)...)
 }
 
 func parseLevel(levelStr string) zapcore.Level {
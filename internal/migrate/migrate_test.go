@@ -0,0 +1,169 @@
+package migrate
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"app/migrations"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func testMigrations(t *testing.T) []Migration {
+	t.Helper()
+
+	ms, err := Load(fstest.MapFS{
+		"000001_init.up.sql":     {Data: []byte("CREATE TABLE a (id INT);")},
+		"000001_init.down.sql":   {Data: []byte("DROP TABLE a;")},
+		"000002_second.up.sql":   {Data: []byte("CREATE TABLE b (id INT);")},
+		"000002_second.down.sql": {Data: []byte("DROP TABLE b;")},
+		"README.md":              {Data: []byte("ignored")},
+	})
+	require.NoError(t, err)
+	return ms
+}
+
+func TestLoad_SortsAndPairs(t *testing.T) {
+	ms := testMigrations(t)
+
+	require.Len(t, ms, 2)
+	require.Equal(t, int64(1), ms[0].Version)
+	require.Equal(t, "init", ms[0].Name)
+	require.Equal(t, "DROP TABLE a;", ms[0].Down)
+	require.Equal(t, int64(2), ms[1].Version)
+	require.NotEmpty(t, ms[1].Checksum)
+	require.NotEqual(t, ms[0].Checksum, ms[1].Checksum)
+}
+
+func TestLoad_MissingUp(t *testing.T) {
+	_, err := Load(fstest.MapFS{
+		"000001_init.down.sql": {Data: []byte("DROP TABLE a;")},
+	})
+	require.Error(t, err)
+}
+
+func TestLoad_Embedded(t *testing.T) {
+	ms, err := Load(migrations.FS)
+	require.NoError(t, err)
+	require.NotEmpty(t, ms)
+	require.Equal(t, int64(1), ms[0].Version)
+	require.NotEmpty(t, ms[0].Down)
+}
+
+func expectLocked(mock pgxmock.PgxConnIface, rows *pgxmock.Rows) {
+	mock.ExpectExec("SET statement_timeout = 0").
+		WillReturnResult(pgxmock.NewResult("SET", 0))
+	mock.ExpectExec("SELECT pg_advisory_lock").WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(rows)
+}
+
+func expectUnlock(mock pgxmock.PgxConnIface) {
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+	mock.ExpectExec("RESET statement_timeout").
+		WillReturnResult(pgxmock.NewResult("RESET", 0))
+}
+
+func migrationRows() *pgxmock.Rows {
+	return pgxmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+}
+
+func TestRunner_Up_AppliesPendingOnly(t *testing.T) {
+	ctx := context.Background()
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	defer mock.Close(ctx)
+
+	ms := testMigrations(t)
+	r := NewRunner(mock, ms)
+
+	expectLocked(mock, migrationRows().AddRow(int64(1), "init", ms[0].Checksum, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE TABLE b").WillReturnResult(pgxmock.NewResult("CREATE", 0))
+	mock.ExpectExec("INSERT INTO schema_migrations").
+		WithArgs(int64(2), "second", ms[1].Checksum).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	n, err := r.Up(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunner_Up_ChecksumMismatch(t *testing.T) {
+	ctx := context.Background()
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	defer mock.Close(ctx)
+
+	r := NewRunner(mock, testMigrations(t))
+
+	expectLocked(mock, migrationRows().AddRow(int64(1), "init", "tampered", time.Now()))
+	expectUnlock(mock)
+
+	_, err = r.Up(ctx)
+	require.ErrorIs(t, err, ErrChecksumMismatch)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunner_Down_RollsBackLatest(t *testing.T) {
+	ctx := context.Background()
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	defer mock.Close(ctx)
+
+	ms := testMigrations(t)
+	r := NewRunner(mock, ms)
+
+	expectLocked(mock, migrationRows().
+		AddRow(int64(1), "init", ms[0].Checksum, time.Now()).
+		AddRow(int64(2), "second", ms[1].Checksum, time.Now()))
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP TABLE b").WillReturnResult(pgxmock.NewResult("DROP", 0))
+	mock.ExpectExec("DELETE FROM schema_migrations").WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+	expectUnlock(mock)
+
+	n, err := r.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunner_To_UnknownVersion(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+
+	_, err = NewRunner(mock, testMigrations(t)).To(context.Background(), 42)
+	require.ErrorIs(t, err, ErrUnknownVersion)
+}
+
+func TestRunner_Status(t *testing.T) {
+	ctx := context.Background()
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+	defer mock.Close(ctx)
+
+	ms := testMigrations(t)
+	r := NewRunner(mock, ms)
+
+	expectLocked(mock, migrationRows().AddRow(int64(1), "init", "tampered", time.Now()))
+	expectUnlock(mock)
+
+	st, err := r.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, st, 2)
+	require.True(t, st[0].Applied)
+	require.True(t, st[0].Dirty)
+	require.False(t, st[1].Applied)
+}
@@ -0,0 +1,25 @@
+package migrate
+
+import (
+	"app/migrations"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WithPool берёт соединение из пула и выполняет fn с раннером
+// встроенных миграций.
+func WithPool(ctx context.Context, pool *pgxpool.Pool, fn func(r *Runner) error) error {
+	ms, err := Load(migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	return fn(NewRunner(conn, ms))
+}
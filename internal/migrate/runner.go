@@ -0,0 +1,269 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// lockID — ключ advisory lock, под которым выполняются миграции.
+const lockID int64 = 7_250_001
+
+var (
+	ErrChecksumMismatch = errors.New("migrate: checksum mismatch")
+	ErrUnknownVersion   = errors.New("migrate: unknown version")
+	ErrNoDown           = errors.New("migrate: migration has no down file")
+)
+
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Dirty     bool
+}
+
+type applied struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// Runner применяет миграции на выделенном соединении: advisory lock
+// сессионный, поэтому пул соединений для него не подходит.
+type Runner struct {
+	conn       Conn
+	migrations []Migration
+}
+
+func NewRunner(conn Conn, migrations []Migration) *Runner {
+	return &Runner{conn: conn, migrations: migrations}
+}
+
+const createTableQuery = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       TEXT NOT NULL,
+    checksum   TEXT NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+)
+`
+
+// Up применяет все ещё не применённые миграции.
+func (r *Runner) Up(ctx context.Context) (int, error) {
+	if len(r.migrations) == 0 {
+		return 0, nil
+	}
+	return r.To(ctx, r.migrations[len(r.migrations)-1].Version)
+}
+
+// Down откатывает steps последних применённых миграций.
+func (r *Runner) Down(ctx context.Context, steps int) (int, error) {
+	n := 0
+	err := r.locked(ctx, true, func(done map[int64]applied) error {
+		for i := len(r.migrations) - 1; i >= 0 && n < steps; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; !ok {
+				continue
+			}
+			if err := r.down(ctx, m); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// To приводит схему к версии target: применяет миграции с версией <= target
+// и откатывает миграции с версией > target.
+func (r *Runner) To(ctx context.Context, target int64) (int, error) {
+	if target != 0 && r.find(target) < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrUnknownVersion, target)
+	}
+
+	n := 0
+	err := r.locked(ctx, true, func(done map[int64]applied) error {
+		for i := len(r.migrations) - 1; i >= 0; i-- {
+			m := r.migrations[i]
+			if _, ok := done[m.Version]; !ok || m.Version <= target {
+				continue
+			}
+			if err := r.down(ctx, m); err != nil {
+				return err
+			}
+			n++
+		}
+
+		for _, m := range r.migrations {
+			if _, ok := done[m.Version]; ok || m.Version > target {
+				continue
+			}
+			if err := r.up(ctx, m); err != nil {
+				return err
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// Status возвращает состояние всех известных миграций. Dirty означает,
+// что применённая миграция отличается от встроенной в бинарник.
+func (r *Runner) Status(ctx context.Context) ([]Status, error) {
+	var out []Status
+	err := r.locked(ctx, false, func(done map[int64]applied) error {
+		for _, m := range r.migrations {
+			st := Status{Version: m.Version, Name: m.Name}
+			if a, ok := done[m.Version]; ok {
+				st.Applied = true
+				st.AppliedAt = a.appliedAt
+				st.Dirty = a.checksum != m.Checksum
+			}
+			out = append(out, st)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// locked выполняет fn под advisory lock. Если verify, применённые миграции
+// сверяются по контрольной сумме со встроенными. statement_timeout пула на
+// время миграций снимается: ожидание lock и тяжёлые DDL дольше него законны.
+func (r *Runner) locked(ctx context.Context, verify bool, fn func(done map[int64]applied) error) (err error) {
+	if _, err := r.conn.Exec(ctx, "SET statement_timeout = 0"); err != nil {
+		return fmt.Errorf("migrate: disable statement_timeout: %w", err)
+	}
+	defer func() {
+		// RESET возвращает значение из параметров соединения, с которым
+		// оно вернётся в пул.
+		if _, rerr := r.conn.Exec(context.WithoutCancel(ctx), "RESET statement_timeout"); rerr != nil && err == nil {
+			err = fmt.Errorf("migrate: reset statement_timeout: %w", rerr)
+		}
+	}()
+
+	if _, err := r.conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("migrate: acquire lock: %w", err)
+	}
+	defer func() {
+		if _, uerr := r.conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockID); uerr != nil && err == nil {
+			err = fmt.Errorf("migrate: release lock: %w", uerr)
+		}
+	}()
+
+	if _, err := r.conn.Exec(ctx, createTableQuery); err != nil {
+		return fmt.Errorf("migrate: create schema_migrations: %w", err)
+	}
+
+	done, err := r.applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	if verify {
+		for _, m := range r.migrations {
+			if a, ok := done[m.Version]; ok && a.checksum != m.Checksum {
+				return fmt.Errorf("%w: %d_%s", ErrChecksumMismatch, m.Version, m.Name)
+			}
+		}
+	}
+
+	return fn(done)
+}
+
+func (r *Runner) applied(ctx context.Context) (map[int64]applied, error) {
+	rows, err := r.conn.Query(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("migrate: read schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int64]applied)
+	for rows.Next() {
+		var (
+			v int64
+			a applied
+		)
+		if err := rows.Scan(&v, &a.name, &a.checksum, &a.appliedAt); err != nil {
+			return nil, err
+		}
+		out[v] = a
+	}
+	return out, rows.Err()
+}
+
+func (r *Runner) up(ctx context.Context, m Migration) error {
+	log.Printf("[migrate] up %d_%s", m.Version, m.Name)
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, m.Up); err != nil {
+			return fmt.Errorf("migrate: up %d_%s: %w", m.Version, m.Name, err)
+		}
+		_, err := tx.Exec(ctx,
+			`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+			m.Version, m.Name, m.Checksum,
+		)
+		return err
+	})
+}
+
+func (r *Runner) down(ctx context.Context, m Migration) error {
+	if m.Down == "" {
+		return fmt.Errorf("%w: %d_%s", ErrNoDown, m.Version, m.Name)
+	}
+
+	log.Printf("[migrate] down %d_%s", m.Version, m.Name)
+	return r.inTx(ctx, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, m.Down); err != nil {
+			return fmt.Errorf("migrate: down %d_%s: %w", m.Version, m.Name, err)
+		}
+		_, err := tx.Exec(ctx, `DELETE FROM schema_migrations WHERE version = $1`, m.Version)
+		return err
+	})
+}
+
+func (r *Runner) inTx(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		_ = tx.Rollback(ctx)
+	}()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (r *Runner) find(version int64) int {
+	for i, m := range r.migrations {
+		if m.Version == version {
+			return i
+		}
+	}
+	return -1
+}
@@ -5,18 +5,18 @@
 package mocks
 
 import (
-	"app/internal/model"
+	"time"
 
 	mock "github.com/stretchr/testify/mock"
 )
 
 // NewMockCache creates a new instance of MockCache. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockCache(t interface {
+func NewMockCache[K comparable, V any](t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockCache {
-	mock := &MockCache{}
+}) *MockCache[K, V] {
+	mock := &MockCache[K, V]{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -25,40 +25,73 @@ func NewMockCache(t interface {
 }
 
 // MockCache is an autogenerated mock type for the Cache type
-type MockCache struct {
+type MockCache[K comparable, V any] struct {
 	mock.Mock
 }
 
-type MockCache_Expecter struct {
+type MockCache_Expecter[K comparable, V any] struct {
 	mock *mock.Mock
 }
 
-func (_m *MockCache) EXPECT() *MockCache_Expecter {
-	return &MockCache_Expecter{mock: &_m.Mock}
+func (_m *MockCache[K, V]) EXPECT() *MockCache_Expecter[K, V] {
+	return &MockCache_Expecter[K, V]{mock: &_m.Mock}
+}
+
+// Clear provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) Clear() {
+	_mock.Called()
+	return
+}
+
+// MockCache_Clear_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Clear'
+type MockCache_Clear_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// Clear is a helper method to define mock.On call
+func (_e *MockCache_Expecter[K, V]) Clear() *MockCache_Clear_Call[K, V] {
+	return &MockCache_Clear_Call[K, V]{Call: _e.mock.On("Clear")}
+}
+
+func (_c *MockCache_Clear_Call[K, V]) Run(run func()) *MockCache_Clear_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCache_Clear_Call[K, V]) Return() *MockCache_Clear_Call[K, V] {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockCache_Clear_Call[K, V]) RunAndReturn(run func()) *MockCache_Clear_Call[K, V] {
+	_c.Run(run)
+	return _c
 }
 
 // Delete provides a mock function for the type MockCache
-func (_mock *MockCache) Delete(key string) {
+func (_mock *MockCache[K, V]) Delete(key K) {
 	_mock.Called(key)
 	return
 }
 
 // MockCache_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type MockCache_Delete_Call struct {
+type MockCache_Delete_Call[K comparable, V any] struct {
 	*mock.Call
 }
 
 // Delete is a helper method to define mock.On call
-//   - key string
-func (_e *MockCache_Expecter) Delete(key interface{}) *MockCache_Delete_Call {
-	return &MockCache_Delete_Call{Call: _e.mock.On("Delete", key)}
+//   - key K
+func (_e *MockCache_Expecter[K, V]) Delete(key interface{}) *MockCache_Delete_Call[K, V] {
+	return &MockCache_Delete_Call[K, V]{Call: _e.mock.On("Delete", key)}
 }
 
-func (_c *MockCache_Delete_Call) Run(run func(key string)) *MockCache_Delete_Call {
+func (_c *MockCache_Delete_Call[K, V]) Run(run func(key K)) *MockCache_Delete_Call[K, V] {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 K
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(K)
 		}
 		run(
 			arg0,
@@ -67,35 +100,151 @@ func (_c *MockCache_Delete_Call) Run(run func(key string)) *MockCache_Delete_Cal
 	return _c
 }
 
-func (_c *MockCache_Delete_Call) Return() *MockCache_Delete_Call {
+func (_c *MockCache_Delete_Call[K, V]) Return() *MockCache_Delete_Call[K, V] {
 	_c.Call.Return()
 	return _c
 }
 
-func (_c *MockCache_Delete_Call) RunAndReturn(run func(key string)) *MockCache_Delete_Call {
+func (_c *MockCache_Delete_Call[K, V]) RunAndReturn(run func(key K)) *MockCache_Delete_Call[K, V] {
 	_c.Run(run)
 	return _c
 }
 
+// Fill provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) Fill(key K, value V, gen uint64) bool {
+	ret := _mock.Called(key, value, gen)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fill")
+	}
+
+	var r0 bool
+	if returnFunc, ok := ret.Get(0).(func(K, V, uint64) bool); ok {
+		r0 = returnFunc(key, value, gen)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+	return r0
+}
+
+// MockCache_Fill_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Fill'
+type MockCache_Fill_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// Fill is a helper method to define mock.On call
+//   - key K
+//   - value V
+//   - gen uint64
+func (_e *MockCache_Expecter[K, V]) Fill(key interface{}, value interface{}, gen interface{}) *MockCache_Fill_Call[K, V] {
+	return &MockCache_Fill_Call[K, V]{Call: _e.mock.On("Fill", key, value, gen)}
+}
+
+func (_c *MockCache_Fill_Call[K, V]) Run(run func(key K, value V, gen uint64)) *MockCache_Fill_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 K
+		if args[0] != nil {
+			arg0 = args[0].(K)
+		}
+		var arg1 V
+		if args[1] != nil {
+			arg1 = args[1].(V)
+		}
+		var arg2 uint64
+		if args[2] != nil {
+			arg2 = args[2].(uint64)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCache_Fill_Call[K, V]) Return(b bool) *MockCache_Fill_Call[K, V] {
+	_c.Call.Return(b)
+	return _c
+}
+
+func (_c *MockCache_Fill_Call[K, V]) RunAndReturn(run func(key K, value V, gen uint64) bool) *MockCache_Fill_Call[K, V] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Generation provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) Generation(key K) uint64 {
+	ret := _mock.Called(key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Generation")
+	}
+
+	var r0 uint64
+	if returnFunc, ok := ret.Get(0).(func(K) uint64); ok {
+		r0 = returnFunc(key)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+	return r0
+}
+
+// MockCache_Generation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Generation'
+type MockCache_Generation_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// Generation is a helper method to define mock.On call
+//   - key K
+func (_e *MockCache_Expecter[K, V]) Generation(key interface{}) *MockCache_Generation_Call[K, V] {
+	return &MockCache_Generation_Call[K, V]{Call: _e.mock.On("Generation", key)}
+}
+
+func (_c *MockCache_Generation_Call[K, V]) Run(run func(key K)) *MockCache_Generation_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 K
+		if args[0] != nil {
+			arg0 = args[0].(K)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCache_Generation_Call[K, V]) Return(v uint64) *MockCache_Generation_Call[K, V] {
+	_c.Call.Return(v)
+	return _c
+}
+
+func (_c *MockCache_Generation_Call[K, V]) RunAndReturn(run func(key K) uint64) *MockCache_Generation_Call[K, V] {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Get provides a mock function for the type MockCache
-func (_mock *MockCache) Get(key string) (model.Order, error) {
+func (_mock *MockCache[K, V]) Get(key K) (V, error) {
 	ret := _mock.Called(key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Get")
 	}
 
-	var r0 model.Order
+	var r0 V
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(string) (model.Order, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(K) (V, error)); ok {
 		return returnFunc(key)
 	}
-	if returnFunc, ok := ret.Get(0).(func(string) model.Order); ok {
+	if returnFunc, ok := ret.Get(0).(func(K) V); ok {
 		r0 = returnFunc(key)
 	} else {
-		r0 = ret.Get(0).(model.Order)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(V)
+		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(string) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(K) error); ok {
 		r1 = returnFunc(key)
 	} else {
 		r1 = ret.Error(1)
@@ -104,41 +253,193 @@ func (_mock *MockCache) Get(key string) (model.Order, error) {
 }
 
 // MockCache_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockCache_Get_Call struct {
+type MockCache_Get_Call[K comparable, V any] struct {
 	*mock.Call
 }
 
 // Get is a helper method to define mock.On call
-//   - key string
-func (_e *MockCache_Expecter) Get(key interface{}) *MockCache_Get_Call {
-	return &MockCache_Get_Call{Call: _e.mock.On("Get", key)}
+//   - key K
+func (_e *MockCache_Expecter[K, V]) Get(key interface{}) *MockCache_Get_Call[K, V] {
+	return &MockCache_Get_Call[K, V]{Call: _e.mock.On("Get", key)}
+}
+
+func (_c *MockCache_Get_Call[K, V]) Run(run func(key K)) *MockCache_Get_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 K
+		if args[0] != nil {
+			arg0 = args[0].(K)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCache_Get_Call[K, V]) Return(v V, err error) *MockCache_Get_Call[K, V] {
+	_c.Call.Return(v, err)
+	return _c
+}
+
+func (_c *MockCache_Get_Call[K, V]) RunAndReturn(run func(key K) (V, error)) *MockCache_Get_Call[K, V] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetOrLoad provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) GetOrLoad(key K, load func() (V, error)) (V, error) {
+	ret := _mock.Called(key, load)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrLoad")
+	}
+
+	var r0 V
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(K, func() (V, error)) (V, error)); ok {
+		return returnFunc(key, load)
+	}
+	if returnFunc, ok := ret.Get(0).(func(K, func() (V, error)) V); ok {
+		r0 = returnFunc(key, load)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(V)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(K, func() (V, error)) error); ok {
+		r1 = returnFunc(key, load)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCache_GetOrLoad_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrLoad'
+type MockCache_GetOrLoad_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// GetOrLoad is a helper method to define mock.On call
+//   - key K
+//   - load func() (V, error)
+func (_e *MockCache_Expecter[K, V]) GetOrLoad(key interface{}, load interface{}) *MockCache_GetOrLoad_Call[K, V] {
+	return &MockCache_GetOrLoad_Call[K, V]{Call: _e.mock.On("GetOrLoad", key, load)}
 }
 
-func (_c *MockCache_Get_Call) Run(run func(key string)) *MockCache_Get_Call {
+func (_c *MockCache_GetOrLoad_Call[K, V]) Run(run func(key K, load func() (V, error))) *MockCache_GetOrLoad_Call[K, V] {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 K
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(K)
+		}
+		var arg1 func() (V, error)
+		if args[1] != nil {
+			arg1 = args[1].(func() (V, error))
 		}
 		run(
 			arg0,
+			arg1,
 		)
 	})
 	return _c
 }
 
-func (_c *MockCache_Get_Call) Return(order model.Order, err error) *MockCache_Get_Call {
-	_c.Call.Return(order, err)
+func (_c *MockCache_GetOrLoad_Call[K, V]) Return(v V, err error) *MockCache_GetOrLoad_Call[K, V] {
+	_c.Call.Return(v, err)
 	return _c
 }
 
-func (_c *MockCache_Get_Call) RunAndReturn(run func(key string) (model.Order, error)) *MockCache_Get_Call {
+func (_c *MockCache_GetOrLoad_Call[K, V]) RunAndReturn(run func(key K, load func() (V, error)) (V, error)) *MockCache_GetOrLoad_Call[K, V] {
 	_c.Call.Return(run)
 	return _c
 }
 
+// Len provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) Len() int {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Len")
+	}
+
+	var r0 int
+	if returnFunc, ok := ret.Get(0).(func() int); ok {
+		r0 = returnFunc()
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	return r0
+}
+
+// MockCache_Len_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Len'
+type MockCache_Len_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// Len is a helper method to define mock.On call
+func (_e *MockCache_Expecter[K, V]) Len() *MockCache_Len_Call[K, V] {
+	return &MockCache_Len_Call[K, V]{Call: _e.mock.On("Len")}
+}
+
+func (_c *MockCache_Len_Call[K, V]) Run(run func()) *MockCache_Len_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockCache_Len_Call[K, V]) Return(n int) *MockCache_Len_Call[K, V] {
+	_c.Call.Return(n)
+	return _c
+}
+
+func (_c *MockCache_Len_Call[K, V]) RunAndReturn(run func() int) *MockCache_Len_Call[K, V] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Range provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) Range(fn func(key K, value V) bool) {
+	_mock.Called(fn)
+	return
+}
+
+// MockCache_Range_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Range'
+type MockCache_Range_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// Range is a helper method to define mock.On call
+//   - fn func(key K, value V) bool
+func (_e *MockCache_Expecter[K, V]) Range(fn interface{}) *MockCache_Range_Call[K, V] {
+	return &MockCache_Range_Call[K, V]{Call: _e.mock.On("Range", fn)}
+}
+
+func (_c *MockCache_Range_Call[K, V]) Run(run func(fn func(key K, value V) bool)) *MockCache_Range_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 func(key K, value V) bool
+		if args[0] != nil {
+			arg0 = args[0].(func(key K, value V) bool)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCache_Range_Call[K, V]) Return() *MockCache_Range_Call[K, V] {
+	_c.Call.Return()
+	return _c
+}
+
+func (_c *MockCache_Range_Call[K, V]) RunAndReturn(run func(fn func(key K, value V) bool)) *MockCache_Range_Call[K, V] {
+	_c.Run(run)
+	return _c
+}
+
 // Set provides a mock function for the type MockCache
-func (_mock *MockCache) Set(key string, value model.Order) error {
+func (_mock *MockCache[K, V]) Set(key K, value V) error {
 	ret := _mock.Called(key, value)
 
 	if len(ret) == 0 {
@@ -146,7 +447,7 @@ func (_mock *MockCache) Set(key string, value model.Order) error {
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(string, model.Order) error); ok {
+	if returnFunc, ok := ret.Get(0).(func(K, V) error); ok {
 		r0 = returnFunc(key, value)
 	} else {
 		r0 = ret.Error(0)
@@ -155,41 +456,104 @@ func (_mock *MockCache) Set(key string, value model.Order) error {
 }
 
 // MockCache_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
-type MockCache_Set_Call struct {
+type MockCache_Set_Call[K comparable, V any] struct {
 	*mock.Call
 }
 
 // Set is a helper method to define mock.On call
-//   - key string
-//   - value model.Order
-func (_e *MockCache_Expecter) Set(key interface{}, value interface{}) *MockCache_Set_Call {
-	return &MockCache_Set_Call{Call: _e.mock.On("Set", key, value)}
+//   - key K
+//   - value V
+func (_e *MockCache_Expecter[K, V]) Set(key interface{}, value interface{}) *MockCache_Set_Call[K, V] {
+	return &MockCache_Set_Call[K, V]{Call: _e.mock.On("Set", key, value)}
 }
 
-func (_c *MockCache_Set_Call) Run(run func(key string, value model.Order)) *MockCache_Set_Call {
+func (_c *MockCache_Set_Call[K, V]) Run(run func(key K, value V)) *MockCache_Set_Call[K, V] {
 	_c.Call.Run(func(args mock.Arguments) {
-		var arg0 string
+		var arg0 K
 		if args[0] != nil {
-			arg0 = args[0].(string)
+			arg0 = args[0].(K)
 		}
-		var arg1 model.Order
+		var arg1 V
 		if args[1] != nil {
-			arg1 = args[1].(model.Order)
+			arg1 = args[1].(V)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCache_Set_Call[K, V]) Return(err error) *MockCache_Set_Call[K, V] {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCache_Set_Call[K, V]) RunAndReturn(run func(key K, value V) error) *MockCache_Set_Call[K, V] {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetWithTTL provides a mock function for the type MockCache
+func (_mock *MockCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) error {
+	ret := _mock.Called(key, value, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWithTTL")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(K, V, time.Duration) error); ok {
+		r0 = returnFunc(key, value, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCache_SetWithTTL_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetWithTTL'
+type MockCache_SetWithTTL_Call[K comparable, V any] struct {
+	*mock.Call
+}
+
+// SetWithTTL is a helper method to define mock.On call
+//   - key K
+//   - value V
+//   - ttl time.Duration
+func (_e *MockCache_Expecter[K, V]) SetWithTTL(key interface{}, value interface{}, ttl interface{}) *MockCache_SetWithTTL_Call[K, V] {
+	return &MockCache_SetWithTTL_Call[K, V]{Call: _e.mock.On("SetWithTTL", key, value, ttl)}
+}
+
+func (_c *MockCache_SetWithTTL_Call[K, V]) Run(run func(key K, value V, ttl time.Duration)) *MockCache_SetWithTTL_Call[K, V] {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 K
+		if args[0] != nil {
+			arg0 = args[0].(K)
+		}
+		var arg1 V
+		if args[1] != nil {
+			arg1 = args[1].(V)
+		}
+		var arg2 time.Duration
+		if args[2] != nil {
+			arg2 = args[2].(time.Duration)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockCache_Set_Call) Return(err error) *MockCache_Set_Call {
+func (_c *MockCache_SetWithTTL_Call[K, V]) Return(err error) *MockCache_SetWithTTL_Call[K, V] {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockCache_Set_Call) RunAndReturn(run func(key string, value model.Order) error) *MockCache_Set_Call {
+func (_c *MockCache_SetWithTTL_Call[K, V]) RunAndReturn(run func(key K, value V, ttl time.Duration) error) *MockCache_SetWithTTL_Call[K, V] {
 	_c.Call.Return(run)
 	return _c
 }
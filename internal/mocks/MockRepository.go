@@ -104,6 +104,142 @@ func (_c *MockRepository_GetOrder_Call) RunAndReturn(run func(ctx context.Contex
 	return _c
 }
 
+// GetOrders provides a mock function for the type MockRepository
+func (_mock *MockRepository) GetOrders(ctx context.Context, uuids []string) ([]model.Order, error) {
+	ret := _mock.Called(ctx, uuids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOrders")
+	}
+
+	var r0 []model.Order
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]model.Order, error)); ok {
+		return returnFunc(ctx, uuids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []model.Order); ok {
+		r0 = returnFunc(ctx, uuids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Order)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, uuids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_GetOrders_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetOrders'
+type MockRepository_GetOrders_Call struct {
+	*mock.Call
+}
+
+// GetOrders is a helper method to define mock.On call
+//   - ctx context.Context
+//   - uuids []string
+func (_e *MockRepository_Expecter) GetOrders(ctx interface{}, uuids interface{}) *MockRepository_GetOrders_Call {
+	return &MockRepository_GetOrders_Call{Call: _e.mock.On("GetOrders", ctx, uuids)}
+}
+
+func (_c *MockRepository_GetOrders_Call) Run(run func(ctx context.Context, uuids []string)) *MockRepository_GetOrders_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_GetOrders_Call) Return(orders []model.Order, err error) *MockRepository_GetOrders_Call {
+	_c.Call.Return(orders, err)
+	return _c
+}
+
+func (_c *MockRepository_GetOrders_Call) RunAndReturn(run func(ctx context.Context, uuids []string) ([]model.Order, error)) *MockRepository_GetOrders_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListOrderUIDs provides a mock function for the type MockRepository
+func (_mock *MockRepository) ListOrderUIDs(ctx context.Context, f model.OrderFilter) ([]string, error) {
+	ret := _mock.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOrderUIDs")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.OrderFilter) ([]string, error)); ok {
+		return returnFunc(ctx, f)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.OrderFilter) []string); ok {
+		r0 = returnFunc(ctx, f)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.OrderFilter) error); ok {
+		r1 = returnFunc(ctx, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockRepository_ListOrderUIDs_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListOrderUIDs'
+type MockRepository_ListOrderUIDs_Call struct {
+	*mock.Call
+}
+
+// ListOrderUIDs is a helper method to define mock.On call
+//   - ctx context.Context
+//   - f model.OrderFilter
+func (_e *MockRepository_Expecter) ListOrderUIDs(ctx interface{}, f interface{}) *MockRepository_ListOrderUIDs_Call {
+	return &MockRepository_ListOrderUIDs_Call{Call: _e.mock.On("ListOrderUIDs", ctx, f)}
+}
+
+func (_c *MockRepository_ListOrderUIDs_Call) Run(run func(ctx context.Context, f model.OrderFilter)) *MockRepository_ListOrderUIDs_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.OrderFilter
+		if args[1] != nil {
+			arg1 = args[1].(model.OrderFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockRepository_ListOrderUIDs_Call) Return(strings []string, err error) *MockRepository_ListOrderUIDs_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockRepository_ListOrderUIDs_Call) RunAndReturn(run func(ctx context.Context, f model.OrderFilter) ([]string, error)) *MockRepository_ListOrderUIDs_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // SetOrder provides a mock function for the type MockRepository
 func (_mock *MockRepository) SetOrder(ctx context.Context, order model.Order) error {
 	ret := _mock.Called(ctx, order)
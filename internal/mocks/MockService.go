@@ -104,6 +104,142 @@ func (_c *MockService_Get_Call) RunAndReturn(run func(ctx context.Context, uuid
 	return _c
 }
 
+// GetMany provides a mock function for the type MockService
+func (_mock *MockService) GetMany(ctx context.Context, uuids []string) ([]model.Order, error) {
+	ret := _mock.Called(ctx, uuids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMany")
+	}
+
+	var r0 []model.Order
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) ([]model.Order, error)); ok {
+		return returnFunc(ctx, uuids)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []string) []model.Order); ok {
+		r0 = returnFunc(ctx, uuids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Order)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = returnFunc(ctx, uuids)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_GetMany_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMany'
+type MockService_GetMany_Call struct {
+	*mock.Call
+}
+
+// GetMany is a helper method to define mock.On call
+//   - ctx context.Context
+//   - uuids []string
+func (_e *MockService_Expecter) GetMany(ctx interface{}, uuids interface{}) *MockService_GetMany_Call {
+	return &MockService_GetMany_Call{Call: _e.mock.On("GetMany", ctx, uuids)}
+}
+
+func (_c *MockService_GetMany_Call) Run(run func(ctx context.Context, uuids []string)) *MockService_GetMany_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []string
+		if args[1] != nil {
+			arg1 = args[1].([]string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_GetMany_Call) Return(orders []model.Order, err error) *MockService_GetMany_Call {
+	_c.Call.Return(orders, err)
+	return _c
+}
+
+func (_c *MockService_GetMany_Call) RunAndReturn(run func(ctx context.Context, uuids []string) ([]model.Order, error)) *MockService_GetMany_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockService
+func (_mock *MockService) List(ctx context.Context, f model.OrderFilter) ([]model.Order, error) {
+	ret := _mock.Called(ctx, f)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []model.Order
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.OrderFilter) ([]model.Order, error)); ok {
+		return returnFunc(ctx, f)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, model.OrderFilter) []model.Order); ok {
+		r0 = returnFunc(ctx, f)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Order)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, model.OrderFilter) error); ok {
+		r1 = returnFunc(ctx, f)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - f model.OrderFilter
+func (_e *MockService_Expecter) List(ctx interface{}, f interface{}) *MockService_List_Call {
+	return &MockService_List_Call{Call: _e.mock.On("List", ctx, f)}
+}
+
+func (_c *MockService_List_Call) Run(run func(ctx context.Context, f model.OrderFilter)) *MockService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 model.OrderFilter
+		if args[1] != nil {
+			arg1 = args[1].(model.OrderFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockService_List_Call) Return(orders []model.Order, err error) *MockService_List_Call {
+	_c.Call.Return(orders, err)
+	return _c
+}
+
+func (_c *MockService_List_Call) RunAndReturn(run func(ctx context.Context, f model.OrderFilter) ([]model.Order, error)) *MockService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // ProcessOrder provides a mock function for the type MockService
 func (_mock *MockService) ProcessOrder(ctx context.Context, order model.Order) error {
 	ret := _mock.Called(ctx, order)
@@ -0,0 +1,12 @@
+package model
+
+import "time"
+
+// APIKey — выданный API-ключ. Сам ключ не хранится, только его хэш.
+type APIKey struct {
+	ID        int64
+	Name      string
+	Scopes    []string
+	CreatedAt time.Time
+	RevokedAt *time.Time
+}
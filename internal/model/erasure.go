@@ -0,0 +1,26 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrEmptyErasure = errors.New("erasure: customer_id, email or phone is required")
+
+// ErasureRequest — запрос субъекта на удаление персональных данных.
+// Стираются доставки всех заказов, совпавших хотя бы по одному полю.
+type ErasureRequest struct {
+	CustomerID string
+	Email      string
+	Phone      string
+	// RequestedBy — кто выполнил удаление, для аудита.
+	RequestedBy string
+}
+
+// ErasureReport — запись аудита об удалении: какие заказы затронуты.
+type ErasureReport struct {
+	ID          int64     `json:"id"`
+	RequestedBy string    `json:"requested_by"`
+	OrderUIDs   []string  `json:"order_uids"`
+	ErasedAt    time.Time `json:"erased_at"`
+}
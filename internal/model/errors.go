@@ -3,6 +3,8 @@ package model
 import "errors"
 
 var (
-	ErrNotFound  = errors.New("not found")
-	ErrCacheMiss = errors.New("miss cache")
+	ErrNotFound      = errors.New("not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrCacheMiss     = errors.New("miss cache")
+	ErrStale         = errors.New("stale cache entry")
 )
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// ComputeETag возвращает хэш содержимого заказа без кавычек. Поле ETag
+// в хэш не входит, поэтому результат не зависит от того, посчитан ли он уже.
+func ComputeETag(o Order) string {
+	// Marshal структуры без map и интерфейсов не возвращает ошибок, а
+	// порядок полей фиксирован, поэтому хэш стабилен.
+	b, _ := json.Marshal(o)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:16])
+}
+
+// WithETag возвращает заказ с заполненным ETag.
+func (o Order) WithETag() Order {
+	if o.ETag == "" {
+		o.ETag = ComputeETag(o)
+	}
+	return o
+}
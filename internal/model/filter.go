@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// OrderFilter — условия выборки заказов. Пустые поля не фильтруют.
+// Заказы выдаются от новых к старым.
+type OrderFilter struct {
+	DeliveryService string
+	CustomerID      string
+	Region          string
+	// CreatedFrom включительно, CreatedTo — нет.
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+
+	// After — курсор: выдаются заказы строго после него.
+	After *OrderCursor
+	Limit int
+}
+
+// OrderCursor — позиция заказа в выдаче OrderFilter.
+type OrderCursor struct {
+	DateCreated time.Time
+	OrderUID    string
+}
+
+// CursorOf возвращает курсор, указывающий на заказ o.
+func CursorOf(o Order) *OrderCursor {
+	return &OrderCursor{DateCreated: o.DateCreated, OrderUID: o.OrderUUID}
+}
@@ -18,6 +18,10 @@ type Order struct {
 	Delivery Delivery `json:"delivery"`
 	Payment  Payment  `json:"payment"`
 	Items    []Item   `json:"items"`
+
+	// ETag — хэш содержимого (см. ComputeETag). Считается при записи в кэш
+	// и хранится вместе с записью; в JSON не попадает.
+	ETag string `json:"-"`
 }
 
 type Delivery struct {
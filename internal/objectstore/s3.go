@@ -0,0 +1,96 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type S3Options struct {
+	// Endpoint — адрес S3-совместимого хранилища (MinIO, Ceph, ...);
+	// пусто — AWS S3 в Region.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string
+	AccessKey string
+	SecretKey string
+	// PathStyle — адресовать бакет путём (/bucket/key), а не поддоменом;
+	// нужно большинству S3-совместимых хранилищ.
+	PathStyle bool
+}
+
+// S3 хранит объекты в бакете S3 или S3-совместимого хранилища.
+type S3 struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func NewS3(opts S3Options) *S3 {
+	client := s3.New(s3.Options{
+		Region:       opts.Region,
+		Credentials:  credentials.NewStaticCredentialsProvider(opts.AccessKey, opts.SecretKey, ""),
+		UsePathStyle: opts.PathStyle,
+		BaseEndpoint: endpoint(opts.Endpoint),
+	})
+	return &S3{client: client, bucket: opts.Bucket, prefix: opts.Prefix}
+}
+
+func endpoint(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return aws.String(s)
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64) error {
+	in := &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.key(key)),
+		Body:        r,
+		IfNoneMatch: aws.String("*"),
+	}
+	if size >= 0 {
+		in.ContentLength = aws.Int64(size)
+	}
+	if _, err := s.client.PutObject(ctx, in); err != nil {
+		var re *awshttp.ResponseError
+		if errors.As(err, &re) && re.HTTPStatusCode() == http.StatusPreconditionFailed {
+			return fmt.Errorf("%w: %s", ErrExists, key)
+		}
+		return fmt.Errorf("objectstore: put s3://%s/%s: %w", s.bucket, s.key(key), err)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key(key)),
+	})
+	if err != nil {
+		var nsk *types.NoSuchKey
+		if errors.As(err, &nsk) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, fmt.Errorf("objectstore: get s3://%s/%s: %w", s.bucket, s.key(key), err)
+	}
+	return out.Body, nil
+}
+
+func (s *S3) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return strings.TrimSuffix(s.prefix, "/") + "/" + key
+}
@@ -0,0 +1,20 @@
+package objectstore
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrNotFound = errors.New("objectstore: object not found")
+	ErrExists   = errors.New("objectstore: object already exists")
+)
+
+// Store — хранилище объектов по ключу вида "orders/2025/01.avro".
+// Put только создаёт объект: на занятый ключ он возвращает ErrExists и не
+// трогает прежний объект. Частично записанный объект не виден.
+type Store interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+}
@@ -0,0 +1,77 @@
+package objectstore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/stretchr/testify/require"
+)
+
+func testStore(t *testing.T, s Store) {
+	t.Helper()
+	ctx := context.Background()
+
+	_, err := s.Get(ctx, "orders/2025/01.avro")
+	require.True(t, errors.Is(err, ErrNotFound), "got %v", err)
+
+	require.NoError(t, s.Put(ctx, "orders/2025/01.avro", strings.NewReader("first"), 5))
+
+	err = s.Put(ctx, "orders/2025/01.avro", strings.NewReader("second version"), 14)
+	require.True(t, errors.Is(err, ErrExists), "got %v", err)
+
+	rc, err := s.Get(ctx, "orders/2025/01.avro")
+	require.NoError(t, err)
+	got, err := io.ReadAll(rc)
+	require.NoError(t, rc.Close())
+	require.NoError(t, err)
+	require.Equal(t, "first", string(got), "existing object is not overwritten")
+}
+
+func TestFS(t *testing.T) {
+	testStore(t, NewFS(t.TempDir()))
+}
+
+func TestFS_RejectsShortWrite(t *testing.T) {
+	s := NewFS(t.TempDir())
+	err := s.Put(context.Background(), "a.avro", bytes.NewReader([]byte("abc")), 10)
+	require.Error(t, err)
+
+	_, err = s.Get(context.Background(), "a.avro")
+	require.True(t, errors.Is(err, ErrNotFound))
+}
+
+func TestFS_KeyCannotEscapeRoot(t *testing.T) {
+	root := t.TempDir()
+	s := NewFS(root)
+
+	path, err := s.path("../../etc/passwd")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(path, root))
+}
+
+func TestS3(t *testing.T) {
+	backend := s3mem.New()
+	srv := httptest.NewServer(gofakes3.New(backend).Server())
+	t.Cleanup(srv.Close)
+	require.NoError(t, backend.CreateBucket("archive"))
+
+	testStore(t, NewS3(S3Options{
+		Endpoint:  srv.URL,
+		Region:    "us-east-1",
+		Bucket:    "archive",
+		Prefix:    "wb-orders/",
+		AccessKey: "key",
+		SecretKey: "secret",
+		PathStyle: true,
+	}))
+
+	_, err := backend.HeadObject("archive", "wb-orders/orders/2025/01.avro")
+	require.NoError(t, err)
+}
@@ -30,7 +30,9 @@ type InitResult struct {
 	Shutdown  func(ctx context.Context) error
 }
 
-func Init(ctx context.Context, serviceName, appEnv string) (InitResult, error) {
+// Init настраивает провайдеры OTel; attrs добавляются к ресурсу и попадают
+// во все трейсы, метрики и логи процесса.
+func Init(ctx context.Context, serviceName, appEnv string, attrs ...attribute.KeyValue) (InitResult, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
@@ -46,6 +48,7 @@ func Init(ctx context.Context, serviceName, appEnv string) (InitResult, error) {
 			semconv.ServiceName(serviceName),
 			attribute.String("app_env", appEnv),
 		),
+		resource.WithAttributes(attrs...),
 	)
 	if err != nil {
 		return InitResult{}, err
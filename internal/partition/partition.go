@@ -0,0 +1,269 @@
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// lockID — ключ advisory lock: job выполняет только та реплика, которая его взяла.
+const lockID int64 = 7_250_002
+
+// Tables — таблицы, секционированные по месяцам date_created (см. миграцию 000002).
+var Tables = []string{"orders", "deliveries", "payments", "items"}
+
+var nameRe = regexp.MustCompile(`_p(\d{4})_(\d{2})$`)
+
+type Conn interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// ArchiveFunc выгружает заказы месяца в архив и удаляет их строки из БД;
+// conn — соединение job'а (см. archive.Archiver.ArchiveMonth).
+type ArchiveFunc func(ctx context.Context, conn Conn, month time.Time) error
+
+type Options struct {
+	// PremakeMonths — на сколько месяцев вперёд создавать секции.
+	PremakeMonths int
+	// RetentionMonths — сколько месяцев, включая текущий, секции хранятся
+	// в БД; 0 — хранить всё.
+	RetentionMonths int
+	// Archive вызывается для месяца старше retention перед удалением его
+	// секций. Без него удаляются только уже пустые секции.
+	Archive ArchiveFunc
+}
+
+// Report — что сделал один запуск. Leader == false: job выполняет другая реплика.
+type Report struct {
+	Leader  bool
+	Created []string
+	Dropped []string
+}
+
+type Maintainer struct {
+	opts Options
+	now  func() time.Time
+}
+
+func New(opts Options) *Maintainer {
+	return &Maintainer{opts: opts, now: time.Now}
+}
+
+// Name возвращает имя месячной секции таблицы, например orders_p2026_10.
+func Name(table string, month time.Time) string {
+	return fmt.Sprintf("%s_p%04d_%02d", table, month.Year(), int(month.Month()))
+}
+
+// RunOnce создаёт недостающие секции и удаляет секции старше retention:
+// месяц сначала архивируется, и секция удаляется, только если в ней не
+// осталось строк.
+// conn должен быть выделенным соединением: advisory lock сессионный.
+func (m *Maintainer) RunOnce(ctx context.Context, conn Conn) (rep Report, err error) {
+	rows, err := conn.Query(ctx, "SELECT pg_try_advisory_lock($1)", lockID)
+	if err != nil {
+		return rep, fmt.Errorf("partition: acquire lock: %w", err)
+	}
+	locked, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[bool])
+	if err != nil {
+		return rep, fmt.Errorf("partition: acquire lock: %w", err)
+	}
+	if !locked {
+		return rep, nil
+	}
+	rep.Leader = true
+	defer func() {
+		if _, uerr := conn.Exec(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", lockID); uerr != nil && err == nil {
+			err = fmt.Errorf("partition: release lock: %w", uerr)
+		}
+	}()
+
+	cur := monthStart(m.now())
+	cutoff := cur.AddDate(0, -(m.opts.RetentionMonths - 1), 0)
+	// old — секции старше retention по месяцам, в порядке Tables.
+	type oldPartition struct{ table, name string }
+	old := make(map[time.Time][]oldPartition)
+	var errs []error
+	for _, table := range Tables {
+		existing, err := m.partitions(ctx, conn, table)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		for i := 0; i <= m.opts.PremakeMonths; i++ {
+			month := cur.AddDate(0, i, 0)
+			name := Name(table, month)
+			if _, ok := existing[name]; ok {
+				continue
+			}
+			if err := create(ctx, conn, table, name, month); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rep.Created = append(rep.Created, name)
+		}
+
+		if m.opts.RetentionMonths <= 0 {
+			continue
+		}
+		for name, month := range existing {
+			if month.Before(cutoff) {
+				old[month] = append(old[month], oldPartition{table, name})
+			}
+		}
+	}
+
+	months := make([]time.Time, 0, len(old))
+	for month := range old {
+		months = append(months, month)
+	}
+	sort.Slice(months, func(i, j int) bool { return months[i].Before(months[j]) })
+	for _, month := range months {
+		if m.opts.Archive != nil {
+			if err := m.opts.Archive(ctx, conn, month); err != nil {
+				errs = append(errs, fmt.Errorf("partition: archive %s: %w", month.Format("2006-01"), err))
+				continue
+			}
+		}
+		for _, p := range old[month] {
+			if err := dropEmpty(ctx, conn, p.table, p.name); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			rep.Dropped = append(rep.Dropped, p.name)
+		}
+	}
+	return rep, errors.Join(errs...)
+}
+
+// Run запускает RunOnce сразу и затем раз в interval до отмены ctx.
+func (m *Maintainer) Run(ctx context.Context, pool *pgxpool.Pool, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		m.runPooled(ctx, pool)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+func (m *Maintainer) runPooled(ctx context.Context, pool *pgxpool.Pool) {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[partition] acquire conn: %v", err)
+		}
+		return
+	}
+	defer conn.Release()
+
+	rep, err := m.RunOnce(ctx, conn)
+	if err != nil {
+		log.Printf("[partition] maintenance failed: %v", err)
+	}
+	if !rep.Leader {
+		return
+	}
+	if len(rep.Created) > 0 || len(rep.Dropped) > 0 {
+		log.Printf("[partition] created=%v dropped=%v", rep.Created, rep.Dropped)
+	}
+}
+
+// partitions возвращает месячные секции таблицы с их месяцем.
+func (m *Maintainer) partitions(ctx context.Context, conn Conn, table string) (map[string]time.Time, error) {
+	rows, err := conn.Query(ctx, `
+SELECT c.relname
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+JOIN pg_class p ON p.oid = i.inhparent
+WHERE p.relname = $1
+`, table)
+	if err != nil {
+		return nil, fmt.Errorf("partition: list %s: %w", table, err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("partition: list %s: %w", table, err)
+	}
+
+	out := make(map[string]time.Time, len(names))
+	for _, name := range names {
+		if month, ok := parseMonth(table, name); ok {
+			out[name] = month
+		}
+	}
+	return out, nil
+}
+
+// dropEmpty удаляет секцию, если в ней нет строк. Проверка и удаление идут
+// под ACCESS EXCLUSIVE: заказ с давней датой не вставится между ними.
+// Родитель блокируется первым, как при вставке, чтобы не ловить deadlock.
+func dropEmpty(ctx context.Context, conn Conn, table, name string) error {
+	sql := fmt.Sprintf(`
+DO $$
+BEGIN
+    LOCK TABLE %[1]s, %[2]s IN ACCESS EXCLUSIVE MODE;
+    IF EXISTS (SELECT 1 FROM %[2]s) THEN
+        RAISE EXCEPTION 'partition %% is not empty', %[3]s;
+    END IF;
+    DROP TABLE %[2]s;
+END
+$$`, pgx.Identifier{table}.Sanitize(), pgx.Identifier{name}.Sanitize(), quoteLiteral(name))
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("partition: drop %s: %w", name, err)
+	}
+	return nil
+}
+
+// quoteLiteral — строковый литерал SQL для имени секции внутри DO-блока,
+// где параметры запроса недоступны.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+func create(ctx context.Context, conn Conn, table, name string, month time.Time) error {
+	sql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+		pgx.Identifier{name}.Sanitize(), pgx.Identifier{table}.Sanitize(),
+		month.Format(time.DateOnly), month.AddDate(0, 1, 0).Format(time.DateOnly))
+	if _, err := conn.Exec(ctx, sql); err != nil {
+		return fmt.Errorf("partition: create %s: %w", name, err)
+	}
+	return nil
+}
+
+func parseMonth(table, name string) (time.Time, bool) {
+	if len(name) <= len(table) || name[:len(table)] != table {
+		return time.Time{}, false
+	}
+	sm := nameRe.FindStringSubmatch(name[len(table):])
+	if sm == nil || len(name[len(table):]) != len(sm[0]) {
+		return time.Time{}, false
+	}
+	y, _ := strconv.Atoi(sm[1])
+	mo, _ := strconv.Atoi(sm[2])
+	if mo < 1 || mo > 12 {
+		return time.Time{}, false
+	}
+	return time.Date(y, time.Month(mo), 1, 0, 0, 0, 0, time.UTC), true
+}
+
+func monthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,129 @@
+package partition
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMaintainer(opts Options, now time.Time) *Maintainer {
+	m := New(opts)
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func expectLock(mock pgxmock.PgxConnIface, got bool) {
+	mock.ExpectQuery("SELECT pg_try_advisory_lock").WithArgs(lockID).
+		WillReturnRows(pgxmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(got))
+}
+
+func TestName(t *testing.T) {
+	require.Equal(t, "orders_p2026_03", Name("orders", time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseMonth(t *testing.T) {
+	m, ok := parseMonth("orders", "orders_p2026_03")
+	require.True(t, ok)
+	require.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), m)
+
+	for _, name := range []string{"orders_default", "items_p2026_03", "orders_p2026_13", "orders_p2026_03_old"} {
+		_, ok := parseMonth("orders", name)
+		require.False(t, ok, name)
+	}
+}
+
+func TestMaintainer_RunOnce_NotLeader(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+
+	expectLock(mock, false)
+
+	rep, err := newTestMaintainer(Options{PremakeMonths: 1}, time.Now()).RunOnce(context.Background(), mock)
+	require.NoError(t, err)
+	require.False(t, rep.Leader)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintainer_RunOnce_CreatesArchivesAndDrops(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	var archived []time.Time
+	m := newTestMaintainer(Options{
+		PremakeMonths:   1,
+		RetentionMonths: 2,
+		Archive: func(ctx context.Context, conn Conn, month time.Time) error {
+			archived = append(archived, month)
+			return nil
+		},
+	}, now)
+
+	expectLock(mock, true)
+	for _, table := range Tables {
+		mock.ExpectQuery("FROM pg_inherits").WithArgs(table).
+			WillReturnRows(pgxmock.NewRows([]string{"relname"}).
+				AddRow(table + "_default").
+				AddRow(table + "_p2026_07").
+				AddRow(table + "_p2026_08").
+				AddRow(table + "_p2026_09").
+				AddRow(table + "_p2026_10"))
+		mock.ExpectExec(`CREATE TABLE IF NOT EXISTS "` + table + `_p2026_11" PARTITION OF "` + table + `" FOR VALUES FROM \('2026-11-01'\) TO \('2026-12-01'\)`).
+			WillReturnResult(pgxmock.NewResult("CREATE TABLE", 0))
+	}
+	for _, month := range []string{"07", "08"} {
+		for _, table := range Tables {
+			mock.ExpectExec(`LOCK TABLE "` + table + `", "` + table + `_p2026_` + month + `" IN ACCESS EXCLUSIVE MODE`).
+				WillReturnResult(pgxmock.NewResult("DO", 0))
+		}
+	}
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	rep, err := m.RunOnce(context.Background(), mock)
+	require.NoError(t, err)
+	require.True(t, rep.Leader)
+	require.Equal(t, []string{"orders_p2026_11", "deliveries_p2026_11", "payments_p2026_11", "items_p2026_11"}, rep.Created)
+	require.Equal(t, []string{
+		"orders_p2026_07", "deliveries_p2026_07", "payments_p2026_07", "items_p2026_07",
+		"orders_p2026_08", "deliveries_p2026_08", "payments_p2026_08", "items_p2026_08",
+	}, rep.Dropped)
+	require.Equal(t, []time.Time{
+		time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2026, 8, 1, 0, 0, 0, 0, time.UTC),
+	}, archived)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMaintainer_RunOnce_KeepsPartitionsWhenArchiveFails(t *testing.T) {
+	mock, err := pgxmock.NewConn()
+	require.NoError(t, err)
+
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	m := newTestMaintainer(Options{
+		PremakeMonths:   0,
+		RetentionMonths: 2,
+		Archive: func(ctx context.Context, conn Conn, month time.Time) error {
+			return errors.New("store unavailable")
+		},
+	}, now)
+
+	expectLock(mock, true)
+	for _, table := range Tables {
+		mock.ExpectQuery("FROM pg_inherits").WithArgs(table).
+			WillReturnRows(pgxmock.NewRows([]string{"relname"}).
+				AddRow(table + "_p2026_08").
+				AddRow(table + "_p2026_10"))
+	}
+	mock.ExpectExec("SELECT pg_advisory_unlock").WithArgs(lockID).
+		WillReturnResult(pgxmock.NewResult("SELECT", 1))
+
+	rep, err := m.RunOnce(context.Background(), mock)
+	require.ErrorContains(t, err, "store unavailable")
+	require.Empty(t, rep.Dropped)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,206 @@
+package pii
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Action — что вызывающий видит в поле.
+type Action string
+
+const (
+	Full Action = "full"
+	Mask Action = "mask"
+	Omit Action = "omit"
+)
+
+// Role — уровень доступа к персональным данным, выводится из scopes.
+type Role string
+
+const (
+	// RoleFull видит всё (scope orders:pii).
+	RoleFull Role = "full"
+	// RoleSupport — по умолчанию: контакты маскируются, служебные поля скрыты.
+	RoleSupport Role = "support"
+	// RoleAnalyst видит заказ без персональных данных (scope orders:analytics).
+	RoleAnalyst Role = "analyst"
+)
+
+const (
+	ScopeFull      = "orders:pii"
+	ScopeAnalytics = "orders:analytics"
+)
+
+// Поля заказа, к которым применяется политика; имена — пути в JSON API.
+const (
+	FieldInternalSignature = "internal_signature"
+	FieldCustomerID        = "customer_id"
+	FieldName              = "delivery.name"
+	FieldPhone             = "delivery.phone"
+	FieldZip               = "delivery.zip"
+	FieldCity              = "delivery.city"
+	FieldAddress           = "delivery.address"
+	FieldEmail             = "delivery.email"
+	FieldTransaction       = "payment.transaction"
+	FieldRequestID         = "payment.request"
+)
+
+// Fields — поля, для которых можно задать правило, и их маски.
+var Fields = map[string]func(string) string{
+	FieldInternalSignature: maskTail,
+	FieldCustomerID:        maskTail,
+	FieldName:              maskName,
+	FieldPhone:             MaskPhone,
+	FieldZip:               maskTail,
+	FieldCity:              maskTail,
+	FieldAddress:           maskTail,
+	FieldEmail:             MaskEmail,
+	FieldTransaction:       maskTail,
+	FieldRequestID:         maskTail,
+}
+
+var roles = []Role{RoleFull, RoleSupport, RoleAnalyst}
+
+// Policy — правила по полям: поле -> роль -> действие. Роль, не указанная
+// для поля, видит его полностью; RoleFull видит всё всегда.
+type Policy map[string]map[Role]Action
+
+// DefaultPolicy — политика, если pii.rules не переопределяет её.
+var DefaultPolicy = Policy{
+	FieldInternalSignature: {RoleSupport: Omit, RoleAnalyst: Omit},
+	FieldCustomerID:        {RoleAnalyst: Mask},
+	FieldName:              {RoleAnalyst: Omit},
+	FieldPhone:             {RoleSupport: Mask, RoleAnalyst: Omit},
+	FieldZip:               {RoleAnalyst: Omit},
+	FieldAddress:           {RoleAnalyst: Omit},
+	FieldEmail:             {RoleSupport: Mask, RoleAnalyst: Omit},
+	FieldTransaction:       {RoleSupport: Omit, RoleAnalyst: Omit},
+	FieldRequestID:         {RoleSupport: Omit, RoleAnalyst: Omit},
+}
+
+// RoleFromScopes выбирает самую широкую роль, которую дают scopes.
+func RoleFromScopes(scopes []string) Role {
+	switch {
+	case slices.Contains(scopes, ScopeFull):
+		return RoleFull
+	case slices.Contains(scopes, ScopeAnalytics):
+		return RoleAnalyst
+	default:
+		return RoleSupport
+	}
+}
+
+// WithRules возвращает копию политики с правилами вида
+// "delivery.phone:support=omit" поверх неё.
+func (p Policy) WithRules(rules []string) (Policy, error) {
+	out := make(Policy, len(p))
+	for field, byRole := range p {
+		out[field] = make(map[Role]Action, len(byRole))
+		for role, action := range byRole {
+			out[field][role] = action
+		}
+	}
+
+	for _, rule := range rules {
+		target, action, ok := strings.Cut(rule, "=")
+		field, role, ok2 := strings.Cut(target, ":")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("pii: rule %q: want field:role=action", rule)
+		}
+		if _, known := Fields[field]; !known {
+			return nil, fmt.Errorf("pii: rule %q: unknown field %q", rule, field)
+		}
+		if r := Role(role); r == RoleFull || !slices.Contains(roles, r) {
+			return nil, fmt.Errorf("pii: rule %q: role must be %s or %s", rule, RoleSupport, RoleAnalyst)
+		}
+		switch a := Action(action); a {
+		case Full, Mask, Omit:
+			if out[field] == nil {
+				out[field] = map[Role]Action{}
+			}
+			out[field][Role(role)] = a
+		default:
+			return nil, fmt.Errorf("pii: rule %q: action must be %s, %s or %s", rule, Full, Mask, Omit)
+		}
+	}
+	return out, nil
+}
+
+// View — политика для конкретной роли.
+type View struct {
+	role   Role
+	policy Policy
+}
+
+func (p Policy) For(role Role) View {
+	return View{role: role, policy: p}
+}
+
+func (v View) Role() Role {
+	return v.role
+}
+
+// Apply возвращает значение поля для роли; ok == false — поле не отдаётся.
+func (v View) Apply(field, value string) (string, bool) {
+	if v.role == RoleFull {
+		return value, true
+	}
+	switch v.policy[field][v.role] {
+	case Omit:
+		return "", false
+	case Mask:
+		if mask := Fields[field]; mask != nil {
+			return mask(value), true
+		}
+		return maskTail(value), true
+	default:
+		return value, true
+	}
+}
+
+// MaskPhone оставляет код страны и 4 последние цифры: +79991234567 -> +7***4567.
+// Разделители не сохраняются: +7 (999) 123-45-67 -> +7***4567.
+func MaskPhone(s string) string {
+	var digits []byte
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			digits = append(digits, s[i])
+		}
+	}
+	if len(digits) < 6 {
+		return "***"
+	}
+	prefix := ""
+	if strings.HasPrefix(s, "+") {
+		prefix = "+" + string(digits[0])
+	}
+	return prefix + "***" + string(digits[len(digits)-4:])
+}
+
+// MaskEmail оставляет первую букву и домен: ivan@example.com -> i***@example.com.
+func MaskEmail(s string) string {
+	local, domain, ok := strings.Cut(s, "@")
+	if !ok || local == "" {
+		return "***"
+	}
+	return local[:1] + "***@" + domain
+}
+
+// maskName оставляет первые буквы слов: Test Testov -> T*** T***.
+func maskName(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = string([]rune(w)[:1]) + "***"
+	}
+	return strings.Join(words, " ")
+}
+
+// maskTail оставляет 4 последних символа длинных значений.
+func maskTail(s string) string {
+	r := []rune(s)
+	if len(r) <= 8 {
+		return "***"
+	}
+	return "***" + string(r[len(r)-4:])
+}
@@ -0,0 +1,82 @@
+package pii
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoleFromScopes(t *testing.T) {
+	require.Equal(t, RoleSupport, RoleFromScopes(nil))
+	require.Equal(t, RoleSupport, RoleFromScopes([]string{"orders:read"}))
+	require.Equal(t, RoleAnalyst, RoleFromScopes([]string{"orders:read", ScopeAnalytics}))
+	require.Equal(t, RoleFull, RoleFromScopes([]string{ScopeAnalytics, ScopeFull}))
+}
+
+func TestMasks(t *testing.T) {
+	require.Equal(t, "+7***4567", MaskPhone("+79991234567"))
+	require.Equal(t, "+7***4567", MaskPhone("+7 (999) 123-45-67"))
+	require.Equal(t, "***4567", MaskPhone("89991234567"))
+	require.Equal(t, "***", MaskPhone("123"))
+
+	require.Equal(t, "i***@example.com", MaskEmail("ivan@example.com"))
+	require.Equal(t, "***", MaskEmail("not-an-email"))
+
+	require.Equal(t, "T*** T***", maskName("Test Testov"))
+	require.Equal(t, "***", maskTail("short"))
+	require.Equal(t, "***6789", maskTail("tx-123456789"))
+}
+
+func TestView_Apply(t *testing.T) {
+	cases := []struct {
+		role  Role
+		field string
+		want  string
+		ok    bool
+	}{
+		{RoleFull, FieldPhone, "+79991234567", true},
+		{RoleFull, FieldTransaction, "tx-123456789", true},
+		{RoleSupport, FieldPhone, "+7***4567", true},
+		{RoleSupport, FieldEmail, "i***@example.com", true},
+		{RoleSupport, FieldTransaction, "", false},
+		{RoleSupport, FieldInternalSignature, "", false},
+		{RoleSupport, FieldAddress, "Lenina 1", true},
+		{RoleAnalyst, FieldPhone, "", false},
+		{RoleAnalyst, FieldCustomerID, "***-042", true},
+		{RoleAnalyst, FieldCity, "Kazan", true},
+	}
+	values := map[string]string{
+		FieldPhone:             "+79991234567",
+		FieldEmail:             "ivan@example.com",
+		FieldTransaction:       "tx-123456789",
+		FieldInternalSignature: "sig",
+		FieldAddress:           "Lenina 1",
+		FieldCustomerID:        "customer-042",
+		FieldCity:              "Kazan",
+	}
+	for _, tc := range cases {
+		got, ok := DefaultPolicy.For(tc.role).Apply(tc.field, values[tc.field])
+		require.Equal(t, tc.ok, ok, "%s %s", tc.role, tc.field)
+		require.Equal(t, tc.want, got, "%s %s", tc.role, tc.field)
+	}
+}
+
+func TestPolicy_WithRules(t *testing.T) {
+	p, err := DefaultPolicy.WithRules([]string{"delivery.phone:support=omit", "delivery.city:analyst=mask"})
+	require.NoError(t, err)
+
+	_, ok := p.For(RoleSupport).Apply(FieldPhone, "+79991234567")
+	require.False(t, ok)
+	got, _ := p.For(RoleAnalyst).Apply(FieldCity, "Saint Petersburg")
+	require.Equal(t, "***burg", got)
+
+	// Исходная политика не меняется.
+	got, ok = DefaultPolicy.For(RoleSupport).Apply(FieldPhone, "+79991234567")
+	require.True(t, ok)
+	require.Equal(t, "+7***4567", got)
+
+	for _, bad := range []string{"delivery.phone", "delivery.phone:support", "track_number:support=omit", "delivery.phone:full=omit", "delivery.phone:support=hide"} {
+		_, err := DefaultPolicy.WithRules([]string{bad})
+		require.Error(t, err, bad)
+	}
+}
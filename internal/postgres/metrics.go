@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// RegisterMetrics экспортирует статистику пула: занятые и простаивающие
+// соединения, число Acquire и ожиданий свободного соединения. Значения
+// снимаются при каждом сборе метрик с атрибутом pool=name (primary, replica);
+// вызовите Unregister при закрытии пула.
+func RegisterMetrics(pool *pgxpool.Pool, name string) (metric.Registration, error) {
+	m := otel.Meter("app/postgres")
+
+	var errs []error
+	gauge := func(instrument, desc string) metric.Int64ObservableGauge {
+		g, err := m.Int64ObservableGauge(instrument, metric.WithDescription(desc))
+		errs = append(errs, err)
+		return g
+	}
+	counter := func(instrument, desc string) metric.Int64ObservableCounter {
+		c, err := m.Int64ObservableCounter(instrument, metric.WithDescription(desc))
+		errs = append(errs, err)
+		return c
+	}
+	msCounter := func(instrument, desc string) metric.Float64ObservableCounter {
+		c, err := m.Float64ObservableCounter(instrument, metric.WithDescription(desc), metric.WithUnit("ms"))
+		errs = append(errs, err)
+		return c
+	}
+
+	acquired := gauge("pg_pool_acquired_conns", "Соединения, занятые запросами")
+	idle := gauge("pg_pool_idle_conns", "Простаивающие соединения")
+	total := gauge("pg_pool_total_conns", "Все соединения пула, включая создаваемые")
+	maxConns := gauge("pg_pool_max_conns", "Размер пула")
+	acquires := counter("pg_pool_acquires_total", "Успешные Acquire")
+	waits := counter("pg_pool_empty_acquires_total", "Acquire, которым пришлось ждать свободное соединение")
+	canceled := counter("pg_pool_canceled_acquires_total", "Acquire, отменённые по ctx (в том числе по acquire_timeout)")
+	acquireDur := msCounter("pg_pool_acquire_duration_ms_total", "Суммарное время Acquire")
+	waitDur := msCounter("pg_pool_empty_acquire_wait_ms_total", "Суммарное время ожидания свободного соединения")
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := pool.Stat()
+		attrs := metric.WithAttributes(attribute.String("pool", name))
+		o.ObserveInt64(acquired, int64(s.AcquiredConns()), attrs)
+		o.ObserveInt64(idle, int64(s.IdleConns()), attrs)
+		o.ObserveInt64(total, int64(s.TotalConns()), attrs)
+		o.ObserveInt64(maxConns, int64(s.MaxConns()), attrs)
+		o.ObserveInt64(acquires, s.AcquireCount(), attrs)
+		o.ObserveInt64(waits, s.EmptyAcquireCount(), attrs)
+		o.ObserveInt64(canceled, s.CanceledAcquireCount(), attrs)
+		o.ObserveFloat64(acquireDur, float64(s.AcquireDuration().Microseconds())/1000, attrs)
+		o.ObserveFloat64(waitDur, float64(s.EmptyAcquireWaitTime().Microseconds())/1000, attrs)
+		return nil
+	}, acquired, idle, total, maxConns, acquires, waits, canceled, acquireDur, waitDur)
+}
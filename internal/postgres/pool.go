@@ -0,0 +1,158 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var ErrAcquireTimeout = errors.New("postgres: timed out waiting for a pool connection")
+
+// PoolOptions — настройки пула поверх DSN. Нулевые длительности оставляют
+// значения pgxpool по умолчанию; нулевой StatementTimeout его не задаёт.
+type PoolOptions struct {
+	MaxConns          int32
+	MinConns          int32
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+	StatementTimeout  time.Duration
+	ApplicationName   string
+}
+
+// ParseConfig разбирает DSN и применяет к нему opts. statement_timeout
+// и application_name передаются как параметры сессии, поэтому действуют
+// на каждый запрос, в том числе на миграции.
+func ParseConfig(dsn string, opts PoolOptions) (*pgxpool.Config, error) {
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.MaxConns > 0 {
+		cfg.MaxConns = opts.MaxConns
+	}
+	cfg.MinConns = opts.MinConns
+	if opts.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.MaxConnIdleTime > 0 {
+		cfg.MaxConnIdleTime = opts.MaxConnIdleTime
+	}
+	if opts.HealthCheckPeriod > 0 {
+		cfg.HealthCheckPeriod = opts.HealthCheckPeriod
+	}
+
+	params := cfg.ConnConfig.RuntimeParams
+	if opts.StatementTimeout > 0 {
+		params["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	if opts.ApplicationName != "" {
+		params["application_name"] = opts.ApplicationName
+	}
+	return cfg, nil
+}
+
+// Pool ограничивает время ожидания свободного соединения: pgxpool ждёт его,
+// пока жив ctx запроса. Таймаут действует только на Acquire, сам запрос
+// выполняется с исходным ctx.
+type Pool struct {
+	pool    *pgxpool.Pool
+	timeout time.Duration
+}
+
+func WithAcquireTimeout(pool *pgxpool.Pool, timeout time.Duration) *Pool {
+	return &Pool{pool: pool, timeout: timeout}
+}
+
+func (p *Pool) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.Query(ctx, sql, args...)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releaseRows{Rows: rows, conn: conn}, nil
+}
+
+func (p *Pool) Begin(ctx context.Context) (pgx.Tx, error) {
+	conn, err := p.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		conn.Release()
+		return nil, err
+	}
+	return &releaseTx{Tx: tx, conn: conn}, nil
+}
+
+func (p *Pool) acquire(ctx context.Context) (*pgxpool.Conn, error) {
+	if p.timeout <= 0 {
+		return p.pool.Acquire(ctx)
+	}
+
+	actx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	conn, err := p.pool.Acquire(actx)
+	if err != nil && ctx.Err() == nil && errors.Is(actx.Err(), context.DeadlineExceeded) {
+		return nil, fmt.Errorf("%w (%s)", ErrAcquireTimeout, p.timeout)
+	}
+	return conn, err
+}
+
+// releaseRows возвращает соединение в пул, когда строки дочитаны или закрыты.
+type releaseRows struct {
+	pgx.Rows
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (r *releaseRows) Next() bool {
+	if r.Rows.Next() {
+		return true
+	}
+	r.release()
+	return false
+}
+
+func (r *releaseRows) Close() {
+	r.Rows.Close()
+	r.release()
+}
+
+func (r *releaseRows) release() {
+	r.once.Do(r.conn.Release)
+}
+
+// releaseTx возвращает соединение в пул после Commit или Rollback.
+type releaseTx struct {
+	pgx.Tx
+	conn *pgxpool.Conn
+	once sync.Once
+}
+
+func (t *releaseTx) Commit(ctx context.Context) error {
+	err := t.Tx.Commit(ctx)
+	t.once.Do(t.conn.Release)
+	return err
+}
+
+func (t *releaseTx) Rollback(ctx context.Context) error {
+	err := t.Tx.Rollback(ctx)
+	t.once.Do(t.conn.Release)
+	return err
+}
@@ -0,0 +1,78 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConfig_AppliesOptions(t *testing.T) {
+	cfg, err := ParseConfig("postgres://u:p@localhost:5432/db", PoolOptions{
+		MaxConns:          20,
+		MinConns:          2,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   10 * time.Minute,
+		HealthCheckPeriod: 30 * time.Second,
+		StatementTimeout:  1500 * time.Millisecond,
+		ApplicationName:   "wb-orders",
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, int32(20), cfg.MaxConns)
+	require.Equal(t, int32(2), cfg.MinConns)
+	require.Equal(t, time.Hour, cfg.MaxConnLifetime)
+	require.Equal(t, 10*time.Minute, cfg.MaxConnIdleTime)
+	require.Equal(t, 30*time.Second, cfg.HealthCheckPeriod)
+	require.Equal(t, "1500", cfg.ConnConfig.RuntimeParams["statement_timeout"])
+	require.Equal(t, "wb-orders", cfg.ConnConfig.RuntimeParams["application_name"])
+}
+
+func TestParseConfig_ZeroStatementTimeoutKeepsDSN(t *testing.T) {
+	cfg, err := ParseConfig("postgres://u:p@localhost:5432/db?application_name=from-dsn", PoolOptions{MaxConns: 1})
+	require.NoError(t, err)
+
+	_, ok := cfg.ConnConfig.RuntimeParams["statement_timeout"]
+	require.False(t, ok)
+	require.Equal(t, "from-dsn", cfg.ConnConfig.RuntimeParams["application_name"])
+}
+
+// TestPool_AcquireTimeout поднимает TCP-сервер, который принимает соединение
+// и молчит: pgxpool ждёт рукопожатия, пока не истечёт acquire timeout.
+func TestPool_AcquireTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ln.Close() })
+	go func() {
+		var conns []net.Conn
+		defer func() {
+			for _, c := range conns {
+				_ = c.Close()
+			}
+		}()
+		for {
+			c, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conns = append(conns, c)
+		}
+	}()
+
+	cfg, err := ParseConfig("postgres://u:p@"+ln.Addr().String()+"/db?sslmode=disable", PoolOptions{MaxConns: 1})
+	require.NoError(t, err)
+	pool, err := pgxpool.NewWithConfig(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	p := WithAcquireTimeout(pool, 50*time.Millisecond)
+
+	start := time.Now()
+	_, err = p.Query(context.Background(), "SELECT 1")
+	require.True(t, errors.Is(err, ErrAcquireTimeout), "got %v", err)
+	require.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,189 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit — token bucket: Rate запросов за Period в среднем и до Burst
+// подряд. Нулевой Rate — без ограничений.
+type Limit struct {
+	Rate   int
+	Period time.Duration
+	Burst  int
+}
+
+// Unlimited сообщает, что лимит выключен.
+func (l Limit) Unlimited() bool {
+	return l.Rate == 0
+}
+
+// perToken — за сколько восполняется один токен.
+func (l Limit) perToken() time.Duration {
+	return l.Period / time.Duration(l.Rate)
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "off"
+	}
+	return fmt.Sprintf("%d/%s:%d", l.Rate, l.Period, l.Burst)
+}
+
+// ParseLimit разбирает лимит вида RATE/PERIOD[:BURST], например "20/s",
+// "100/1m:200" или "off". PERIOD — длительность Go или одна единица (s, m, h).
+// Без BURST ёмкость равна RATE.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "off" {
+		return Limit{}, nil
+	}
+
+	spec, burstStr, hasBurst := strings.Cut(s, ":")
+	rateStr, periodStr, ok := strings.Cut(spec, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("limit %q: want RATE/PERIOD[:BURST] or off", s)
+	}
+	rate, err := strconv.Atoi(rateStr)
+	if err != nil || rate < 1 {
+		return Limit{}, fmt.Errorf("limit %q: rate must be a positive integer", s)
+	}
+	if periodStr != "" && (periodStr[0] < '0' || periodStr[0] > '9') {
+		periodStr = "1" + periodStr
+	}
+	period, err := time.ParseDuration(periodStr)
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: bad period", s)
+	}
+	if period/time.Duration(rate) <= 0 {
+		return Limit{}, fmt.Errorf("limit %q: rate is too high for the period", s)
+	}
+
+	burst := rate
+	if hasBurst {
+		if burst, err = strconv.Atoi(burstStr); err != nil || burst < 1 {
+			return Limit{}, fmt.Errorf("limit %q: burst must be a positive integer", s)
+		}
+	}
+	return Limit{Rate: rate, Period: period, Burst: burst}, nil
+}
+
+// Rule задаёт лимит для запросов, путь которых начинается с Prefix
+// (и с методом Method, если он задан).
+type Rule struct {
+	Method string
+	Prefix string
+	Limit  Limit
+}
+
+func (r Rule) name() string {
+	if r.Method == "" {
+		return r.Prefix
+	}
+	return r.Method + " " + r.Prefix
+}
+
+// ParseRule разбирает правило вида "[METHOD ]/prefix=LIMIT", например
+// "/admin/=1/s:5" или "GET /order/=50/s:100".
+func ParseRule(s string) (Rule, error) {
+	route, limit, ok := strings.Cut(s, "=")
+	if !ok {
+		return Rule{}, fmt.Errorf("rule %q: want [METHOD ]/prefix=LIMIT", s)
+	}
+	var r Rule
+	route = strings.TrimSpace(route)
+	if method, prefix, ok := strings.Cut(route, " "); ok {
+		r.Method, route = strings.ToUpper(method), strings.TrimSpace(prefix)
+	}
+	if !strings.HasPrefix(route, "/") {
+		return Rule{}, fmt.Errorf("rule %q: path prefix must start with /", s)
+	}
+	r.Prefix = route
+
+	var err error
+	if r.Limit, err = ParseLimit(limit); err != nil {
+		return Rule{}, fmt.Errorf("rule %q: %w", s, err)
+	}
+	return r, nil
+}
+
+// Rules — лимит по умолчанию и лимиты отдельных маршрутов.
+type Rules struct {
+	Default Limit
+	Routes  []Rule
+}
+
+// ParseRules собирает Rules из строк конфига.
+func ParseRules(def string, routes []string) (Rules, error) {
+	var (
+		rs  Rules
+		err error
+	)
+	if rs.Default, err = ParseLimit(def); err != nil {
+		return Rules{}, err
+	}
+	for _, s := range routes {
+		r, err := ParseRule(s)
+		if err != nil {
+			return Rules{}, err
+		}
+		rs.Routes = append(rs.Routes, r)
+	}
+	return rs, nil
+}
+
+// match выбирает правило с самым длинным подходящим префиксом; правило
+// с методом важнее правила без него. Имя правила разделяет бакеты и
+// попадает в метрики.
+func (rs Rules) match(method, path string) (string, Limit) {
+	best, bestLen := -1, -1
+	for i, r := range rs.Routes {
+		if r.Method != "" && r.Method != method || !strings.HasPrefix(path, r.Prefix) {
+			continue
+		}
+		n := len(r.Prefix) * 2
+		if r.Method != "" {
+			n++
+		}
+		if n > bestLen {
+			best, bestLen = i, n
+		}
+	}
+	if best < 0 {
+		return "default", rs.Default
+	}
+	return rs.Routes[best].name(), rs.Routes[best].Limit
+}
+
+// Result — итог попытки взять токен.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// RetryAfter — через сколько появится следующий токен (для отказа).
+	RetryAfter time.Duration
+	// Reset — через сколько бакет заполнится целиком.
+	Reset time.Duration
+}
+
+// Store хранит бакеты. Take берёт один токен из бакета key.
+type Store interface {
+	Take(ctx context.Context, key string, l Limit) (Result, error)
+}
+
+// result считает Result по числу токенов после попытки.
+func result(l Limit, allowed bool, tokens float64) Result {
+	per := float64(l.perToken())
+	res := Result{
+		Allowed:   allowed,
+		Remaining: int(math.Floor(tokens)),
+		Reset:     time.Duration((float64(l.Burst) - tokens) * per),
+	}
+	if !allowed {
+		res.RetryAfter = time.Duration((1 - tokens) * per)
+	}
+	return res
+}
@@ -0,0 +1,152 @@
+package ratelimit
+
+import (
+	"app/internal/logger"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	"go.uber.org/zap"
+)
+
+// Limiter — HTTP middleware с token bucket на клиента и маршрут.
+type Limiter struct {
+	store     Store
+	rules     atomic.Pointer[Rules]
+	proxyHops atomic.Int64
+	identify  func(r *http.Request) (string, bool)
+
+	throttled metric.Int64Counter
+	errs      metric.Int64Counter
+}
+
+type Option func(*Limiter)
+
+// TrustProxy берёт IP клиента из X-Forwarded-For: hops — сколько доверенных
+// прокси стоит перед сервисом и дописывают заголовок. IP клиента — адрес,
+// дописанный самым внешним из них, hops-й с конца; всё левее клиент мог
+// прислать сам. 0 — заголовкам не доверять. Включайте, только если сервис
+// доступен исключительно через эти прокси.
+func TrustProxy(hops int) Option {
+	return func(l *Limiter) { l.proxyHops.Store(int64(hops)) }
+}
+
+// Identify задаёт проверку учётных данных запроса: проверенный клиент
+// получает свой бакет, остальные считаются по IP.
+func Identify(fn func(r *http.Request) (subject string, ok bool)) Option {
+	return func(l *Limiter) { l.identify = fn }
+}
+
+func New(store Store, rules Rules, opts ...Option) *Limiter {
+	m := otel.Meter("app/ratelimit")
+
+	throttled, err := m.Int64Counter("http_ratelimit_throttled_total")
+	if err != nil {
+		throttled, _ = noop.NewMeterProvider().Meter("noop").Int64Counter("http_ratelimit_throttled_total")
+	}
+	errs, err := m.Int64Counter("http_ratelimit_errors_total")
+	if err != nil {
+		errs, _ = noop.NewMeterProvider().Meter("noop").Int64Counter("http_ratelimit_errors_total")
+	}
+
+	l := &Limiter{store: store, throttled: throttled, errs: errs}
+	l.rules.Store(&rules)
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
+}
+
+// SetRules заменяет лимиты на лету (hot reload). Уже набранные бакеты
+// сохраняются.
+func (l *Limiter) SetRules(rules Rules) {
+	l.rules.Store(&rules)
+}
+
+// SetTrustProxy меняет число доверенных прокси на лету (hot reload).
+func (l *Limiter) SetTrustProxy(hops int) {
+	l.proxyHops.Store(int64(hops))
+}
+
+// Handler пропускает запрос, если в бакете клиента есть токен, иначе
+// отвечает 429 с Retry-After. Ответы несут заголовки RateLimit-*. Если
+// хранилище бакетов недоступно, запрос пропускается: лимитер не должен
+// ронять API.
+func (l *Limiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, limit := l.rules.Load().match(r.Method, r.URL.Path)
+		if limit.Unlimited() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		res, err := l.store.Take(ctx, l.clientKey(r)+"|"+route, limit)
+		if err != nil {
+			l.errs.Add(ctx, 1)
+			logger.Warn(ctx, "rate limit store failed", zap.String("route", route), zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		h := w.Header()
+		h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		h.Set("RateLimit-Reset", seconds(res.Reset))
+		if res.Allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		l.throttled.Add(ctx, 1, metric.WithAttributes(attribute.String("route", route)))
+		h.Set("Retry-After", seconds(res.RetryAfter))
+		h.Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"message":"rate limit exceeded"}` + "\n"))
+	})
+}
+
+// clientKey — ключ бакета: subject клиента, если его учётные данные
+// проверены, иначе IP. Непроверенные заголовки ключом не становятся:
+// каждый выдуманный ключ получал бы свой бакет и обходил лимит.
+func (l *Limiter) clientKey(r *http.Request) string {
+	if l.identify != nil {
+		if sub, ok := l.identify(r); ok {
+			return "sub:" + sub
+		}
+	}
+	return "ip:" + clientIP(r, int(l.proxyHops.Load()))
+}
+
+// clientIP берёт hops-й с конца адрес X-Forwarded-For. Если адресов меньше,
+// все они дописаны доверенными прокси, и клиент — самый левый. Без
+// X-Forwarded-For берётся X-Real-IP, который прокси выставляет целиком.
+func clientIP(r *http.Request, hops int) string {
+	if hops > 0 {
+		if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			ips := strings.Split(strings.Join(xff, ","), ",")
+			return strings.TrimSpace(ips[max(len(ips)-hops, 0)])
+		}
+		if ip := r.Header.Get("X-Real-IP"); ip != "" {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// seconds округляет вверх: клиент, выждавший Retry-After, должен получить токен.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,198 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	l, err := ParseLimit("20/s")
+	require.NoError(t, err)
+	require.Equal(t, Limit{Rate: 20, Period: time.Second, Burst: 20}, l)
+
+	l, err = ParseLimit("100/10m:5")
+	require.NoError(t, err)
+	require.Equal(t, Limit{Rate: 100, Period: 10 * time.Minute, Burst: 5}, l)
+
+	l, err = ParseLimit("off")
+	require.NoError(t, err)
+	require.True(t, l.Unlimited())
+
+	for _, bad := range []string{"", "20", "0/s", "x/s", "20/fortnight", "20/s:0"} {
+		_, err := ParseLimit(bad)
+		require.Error(t, err, bad)
+	}
+}
+
+func TestRules_Match(t *testing.T) {
+	rs, err := ParseRules("10/s", []string{"/order/=50/s", "GET /order/=60/s", "/admin/=1/s:5"})
+	require.NoError(t, err)
+
+	name, l := rs.match(http.MethodGet, "/order/uid-1")
+	require.Equal(t, "GET /order/", name)
+	require.Equal(t, 60, l.Rate)
+
+	name, l = rs.match(http.MethodHead, "/order/uid-1")
+	require.Equal(t, "/order/", name)
+	require.Equal(t, 50, l.Rate)
+
+	name, _ = rs.match(http.MethodPost, "/admin/erasures")
+	require.Equal(t, "/admin/", name)
+
+	name, l = rs.match(http.MethodGet, "/")
+	require.Equal(t, "default", name)
+	require.Equal(t, 10, l.Rate)
+
+	_, err = ParseRules("10/s", []string{"order=1/s"})
+	require.Error(t, err)
+}
+
+func TestMemoryStore_Refills(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	l := Limit{Rate: 2, Period: time.Second, Burst: 2}
+
+	for i := 0; i < 2; i++ {
+		res, err := m.Take(context.Background(), "k", l)
+		require.NoError(t, err)
+		require.True(t, res.Allowed)
+	}
+	res, err := m.Take(context.Background(), "k", l)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Equal(t, 0, res.Remaining)
+	require.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	require.Equal(t, time.Second, res.Reset)
+
+	// Другой ключ — свой бакет.
+	res, _ = m.Take(context.Background(), "other", l)
+	require.True(t, res.Allowed)
+
+	now = now.Add(500 * time.Millisecond)
+	res, _ = m.Take(context.Background(), "k", l)
+	require.True(t, res.Allowed)
+
+	// Заполнившиеся бакеты удаляются.
+	now = now.Add(time.Hour)
+	_, _ = m.Take(context.Background(), "k", l)
+	require.Len(t, m.buckets, 1)
+}
+
+func TestRedisStore_SharedBucket(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	// Две реплики с общим Redis делят один бакет.
+	a, b := NewRedis(client, "rl:"), NewRedis(client, "rl:")
+	l := Limit{Rate: 1, Period: time.Minute, Burst: 2}
+	ctx := context.Background()
+
+	res, err := a.Take(ctx, "k", l)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+	require.Equal(t, 1, res.Remaining)
+
+	res, err = b.Take(ctx, "k", l)
+	require.NoError(t, err)
+	require.True(t, res.Allowed)
+
+	res, err = a.Take(ctx, "k", l)
+	require.NoError(t, err)
+	require.False(t, res.Allowed)
+	require.Greater(t, res.RetryAfter, 59*time.Second)
+	require.True(t, mr.Exists("rl:k"))
+}
+
+type failingStore struct{}
+
+func (failingStore) Take(context.Context, string, Limit) (Result, error) {
+	return Result{}, context.DeadlineExceeded
+}
+
+func TestLimiter_Handler(t *testing.T) {
+	_ = logger.Init("error", false, nil)
+
+	rs, err := ParseRules("1/m", []string{"/docs=off"})
+	require.NoError(t, err)
+	// Проверенным считается только ключ wbo_valid.
+	lim := New(NewMemory(), rs, Identify(func(r *http.Request) (string, bool) {
+		if r.Header.Get("X-API-Key") == "wbo_valid" {
+			return "apikey:1", true
+		}
+		return "", false
+	}))
+	h := lim.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	do := func(path, apiKey, addr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = addr
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("/order/1", "", "10.0.0.1:1234")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("RateLimit-Limit"))
+	require.Equal(t, "0", rec.Header().Get("RateLimit-Remaining"))
+	require.Equal(t, "60", rec.Header().Get("RateLimit-Reset"))
+
+	rec = do("/order/1", "", "10.0.0.1:5678")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.Equal(t, "60", rec.Header().Get("Retry-After"))
+	require.JSONEq(t, `{"message":"rate limit exceeded"}`, rec.Body.String())
+
+	// Другой IP считается отдельно, а непроверенный API-ключ не даёт
+	// нового бакета.
+	require.Equal(t, http.StatusOK, do("/order/1", "", "10.0.0.2:1").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("/order/1", "wbo_a", "10.0.0.1:1").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("/order/1", "wbo_b", "10.0.0.1:1").Code)
+
+	// Проверенный клиент считается по subject, с какого бы IP он ни пришёл.
+	require.Equal(t, http.StatusOK, do("/order/1", "wbo_valid", "10.0.0.1:1").Code)
+	require.Equal(t, http.StatusTooManyRequests, do("/order/1", "wbo_valid", "10.0.0.3:1").Code)
+
+	// Маршрут без лимита.
+	rec = do("/docs", "", "10.0.0.1:1")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, rec.Header().Get("RateLimit-Limit"))
+
+	// Hot reload лимитов.
+	lim.SetRules(Rules{})
+	require.Equal(t, http.StatusOK, do("/order/1", "", "10.0.0.1:1").Code)
+
+	// Недоступное хранилище не блокирует запросы.
+	h = New(failingStore{}, rs).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/order/1", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestClientIP_TrustProxy(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	// Клиент прислал свой X-Forwarded-For, два прокси дописали адреса.
+	req.Header.Set("X-Forwarded-For", "192.0.2.1, 203.0.113.7")
+	req.Header.Add("X-Forwarded-For", "10.0.0.9")
+
+	require.Equal(t, "10.0.0.1", clientIP(req, 0))
+	require.Equal(t, "10.0.0.9", clientIP(req, 1))
+	require.Equal(t, "203.0.113.7", clientIP(req, 2))
+	require.Equal(t, "192.0.2.1", clientIP(req, 5))
+}
@@ -0,0 +1,62 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// takeScript — тот же token bucket, что в MemoryStore, атомарно в Redis.
+// Время берётся из Redis, чтобы расхождение часов реплик не влияло на лимит.
+// Время в миллисекундах: микросекунды Lua 5.1 переводит в строку с потерей
+// точности. Возвращает {allowed, tokens}; tokens строкой, иначе Redis
+// обрежет дробь.
+var takeScript = goredis.NewScript(`
+local burst = tonumber(ARGV[1])
+local per_token = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+local v = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(v[1]) or burst
+local ts = tonumber(v[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) / per_token)
+
+local allowed = 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  allowed = 1
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil((burst - tokens) * per_token) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore держит бакеты в Redis: лимит общий для всех реплик.
+type RedisStore struct {
+	client goredis.Scripter
+	prefix string
+}
+
+func NewRedis(client goredis.Scripter, prefix string) *RedisStore {
+	return &RedisStore{client: client, prefix: prefix}
+}
+
+func (s *RedisStore) Take(ctx context.Context, key string, l Limit) (Result, error) {
+	perTokenMS := strconv.FormatFloat(float64(l.perToken())/float64(time.Millisecond), 'f', -1, 64)
+	vals, err := takeScript.Run(ctx, s.client, []string{s.prefix + key}, l.Burst, perTokenMS).Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	allowed, _ := vals[0].(int64)
+	str, _ := vals[1].(string)
+	tokens, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return Result{}, err
+	}
+	return result(l, allowed == 1, tokens), nil
+}
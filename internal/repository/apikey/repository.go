@@ -0,0 +1,79 @@
+package apikey
+
+import (
+	"app/internal/model"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type Pool interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+type Repository struct {
+	pool Pool
+}
+
+func New(pool Pool) *Repository {
+	return &Repository{pool: pool}
+}
+
+const columns = `id, name, scopes, created_at, revoked_at`
+
+func scanKey(row pgx.CollectableRow) (model.APIKey, error) {
+	var k model.APIKey
+	err := row.Scan(&k.ID, &k.Name, &k.Scopes, &k.CreatedAt, &k.RevokedAt)
+	return k, err
+}
+
+// Create сохраняет ключ по его хэшу.
+func (r *Repository) Create(ctx context.Context, name string, hash []byte, scopes []string) (model.APIKey, error) {
+	if scopes == nil {
+		scopes = []string{}
+	}
+	rows, err := r.pool.Query(ctx,
+		`INSERT INTO api_keys (name, key_hash, scopes) VALUES ($1, $2, $3) RETURNING `+columns,
+		name, hash, scopes)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	return pgx.CollectExactlyOneRow(rows, scanKey)
+}
+
+// Lookup возвращает действующий (не отозванный) ключ по хэшу или model.ErrNotFound.
+func (r *Repository) Lookup(ctx context.Context, hash []byte) (model.APIKey, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+columns+` FROM api_keys WHERE key_hash = $1 AND revoked_at IS NULL`, hash)
+	if err != nil {
+		return model.APIKey{}, err
+	}
+	k, err := pgx.CollectExactlyOneRow(rows, scanKey)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.APIKey{}, model.ErrNotFound
+	}
+	return k, err
+}
+
+func (r *Repository) List(ctx context.Context) ([]model.APIKey, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+columns+` FROM api_keys ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, scanKey)
+}
+
+// Revoke отзывает ключ; повторный отзыв и неизвестный id — model.ErrNotFound.
+func (r *Repository) Revoke(ctx context.Context, id int64) error {
+	rows, err := r.pool.Query(ctx,
+		`UPDATE api_keys SET revoked_at = now() WHERE id = $1 AND revoked_at IS NULL RETURNING id`, id)
+	if err != nil {
+		return err
+	}
+	_, err = pgx.CollectExactlyOneRow(rows, pgx.RowTo[int64])
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,50 @@
+package apikey
+
+import (
+	"app/internal/model"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var keyColumns = []string{"id", "name", "scopes", "created_at", "revoked_at"}
+
+func TestRepository_Lookup(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	created := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM api_keys WHERE key_hash").WithArgs([]byte("h1")).
+		WillReturnRows(pgxmock.NewRows(keyColumns).AddRow(int64(3), "ci", []string{"orders:read"}, created, (*time.Time)(nil)))
+	mock.ExpectQuery("FROM api_keys WHERE key_hash").WithArgs([]byte("h2")).
+		WillReturnRows(pgxmock.NewRows(keyColumns))
+
+	r := New(mock)
+	k, err := r.Lookup(context.Background(), []byte("h1"))
+	require.NoError(t, err)
+	require.Equal(t, model.APIKey{ID: 3, Name: "ci", Scopes: []string{"orders:read"}, CreatedAt: created}, k)
+
+	_, err = r.Lookup(context.Background(), []byte("h2"))
+	require.ErrorIs(t, err, model.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_Revoke_Unknown(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectQuery("UPDATE api_keys SET revoked_at").WithArgs(int64(9)).
+		WillReturnRows(pgxmock.NewRows([]string{"id"}))
+
+	require.ErrorIs(t, New(mock).Revoke(context.Background(), 9), model.ErrNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
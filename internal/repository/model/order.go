@@ -26,6 +26,13 @@ type DeliveryRow struct {
 	Address  string `db:"address"`
 	Region   string `db:"region"`
 	Email    string `db:"email"`
+
+	// KeyID — идентификатор KEK, которым зашифрован WrappedDEK; nil, если
+	// строка хранится в открытом виде.
+	KeyID      *string `db:"key_id"`
+	WrappedDEK []byte  `db:"wrapped_dek"`
+	EmailBidx  []byte  `db:"email_bidx"`
+	PhoneBidx  []byte  `db:"phone_bidx"`
 }
 
 type PaymentRow struct {
@@ -105,3 +105,64 @@ func (r *Repository) GetOrder(ctx context.Context, uuid string) (order service.O
 
 	return order, nil
 }
+
+func (r *Repository) GetOrders(ctx context.Context, uuids []string) (orders []service.Order, err error) {
+	start := time.Now()
+
+	ctx, span := r.tracer.Start(ctx, "repo.GetOrders",
+		trace.WithAttributes(attribute.Int("batch.size", len(uuids))),
+	)
+	defer span.End()
+
+	defer func() {
+		r.dur.Record(ctx, float64(time.Since(start).Milliseconds()),
+			metric.WithAttributes(attribute.String("op", "GetOrders")),
+		)
+		if err != nil {
+			r.errs.Add(ctx, 1, metric.WithAttributes(attribute.String("op", "GetOrders")))
+		}
+	}()
+
+	orders, err = r.next.GetOrders(ctx, uuids)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "repo error")
+		logger.Error(ctx, "repo get orders failed",
+			zap.Int("batch_size", len(uuids)),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("batch.found", len(orders)))
+	return orders, nil
+}
+
+func (r *Repository) ListOrderUIDs(ctx context.Context, f service.OrderFilter) (uids []string, err error) {
+	start := time.Now()
+
+	ctx, span := r.tracer.Start(ctx, "repo.ListOrderUIDs",
+		trace.WithAttributes(attribute.Int("list.limit", f.Limit)),
+	)
+	defer span.End()
+
+	defer func() {
+		r.dur.Record(ctx, float64(time.Since(start).Milliseconds()),
+			metric.WithAttributes(attribute.String("op", "ListOrderUIDs")),
+		)
+		if err != nil {
+			r.errs.Add(ctx, 1, metric.WithAttributes(attribute.String("op", "ListOrderUIDs")))
+		}
+	}()
+
+	uids, err = r.next.ListOrderUIDs(ctx, f)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "repo error")
+		logger.Error(ctx, "repo list orders failed", zap.Error(err))
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("list.found", len(uids)))
+	return uids, nil
+}
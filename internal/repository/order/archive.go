@@ -0,0 +1,224 @@
+package order
+
+import (
+	service "app/internal/model"
+	"app/internal/repository/converter"
+	repo "app/internal/repository/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrDeleteMismatch — при удалении архивированного диапазона затронуто
+// другое число заказов, чем было выгружено; удаление откатывается.
+var ErrDeleteMismatch = errors.New("order: deleted rows do not match archived rows")
+
+// Запросы архивации всегда идут на primary: выгрузка и удаление должны
+// видеть одни и те же строки, а реплика может отставать.
+
+// OldestOrderDate возвращает date_created самого старого заказа;
+// ok == false, если заказов нет.
+func (o *OrderRepository) OldestOrderDate(ctx context.Context) (oldest time.Time, ok bool, err error) {
+	rows, err := o.pool.Query(ctx, `SELECT min(date_created) FROM orders`)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[*time.Time])
+	if err != nil || t == nil {
+		return time.Time{}, false, err
+	}
+	return *t, true, nil
+}
+
+// CountOrders считает заказы с date_created в [from, to).
+func (o *OrderRepository) CountOrders(ctx context.Context, from, to time.Time) (int, error) {
+	rows, err := o.pool.Query(ctx,
+		`SELECT count(*) FROM orders WHERE date_created >= $1 AND date_created < $2`, from, to)
+	if err != nil {
+		return 0, err
+	}
+	n, err := pgx.CollectExactlyOneRow(rows, pgx.RowTo[int64])
+	return int(n), err
+}
+
+// ScanOrders передаёт в fn заказы с date_created в [from, to) в порядке
+// (date_created, order_uid), читая их страницами по batch заказов.
+func (o *OrderRepository) ScanOrders(ctx context.Context, from, to time.Time, batch int, fn func(service.Order) error) (int, error) {
+	var (
+		n        int
+		afterAt  = from
+		afterUID = ""
+	)
+	for {
+		page, err := o.ordersPage(ctx, from, to, afterAt, afterUID, batch)
+		if err != nil {
+			return n, err
+		}
+		for _, ord := range page {
+			if err := fn(ord); err != nil {
+				return n, err
+			}
+			n++
+		}
+		if len(page) < batch {
+			return n, nil
+		}
+		last := page[len(page)-1]
+		afterAt, afterUID = last.DateCreated, last.OrderUUID
+	}
+}
+
+func (o *OrderRepository) ordersPage(ctx context.Context, from, to, afterAt time.Time, afterUID string, limit int) ([]service.Order, error) {
+	rows, err := o.pool.Query(ctx, `
+SELECT order_uid, track_number, entry, locale, internal_signature,
+       customer_id, delivery_service, shardkey,
+       sm_id, date_created, oof_shard
+FROM orders
+WHERE date_created >= $1 AND date_created < $2
+  AND (date_created, order_uid) > ($3, $4)
+ORDER BY date_created, order_uid
+LIMIT $5
+`, from, to, afterAt, afterUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	oRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repo.OrderRow, error) {
+		var r repo.OrderRow
+		err := row.Scan(&r.OrderUUID, &r.TrackNumber, &r.Entry, &r.Locale, &r.InternalSignature,
+			&r.CustomerID, &r.DeliveryService, &r.ShardKey, &r.SmID, &r.DateCreated, &r.OffShard)
+		return r, err
+	})
+	if err != nil || len(oRows) == 0 {
+		return nil, err
+	}
+
+	uids := make([]string, len(oRows))
+	byUID := make(map[string]*service.Order, len(oRows))
+	out := make([]service.Order, len(oRows))
+	for i, r := range oRows {
+		uids[i] = r.OrderUUID
+		out[i] = converter.ConvertRepoOrderToServiceOrder(r)
+		byUID[r.OrderUUID] = &out[i]
+	}
+
+	// date_created в условиях позволяет планировщику читать только нужные секции.
+	rows, err = o.pool.Query(ctx, `
+SELECT order_uid, name, phone, zip, city, address, region, email, key_id, wrapped_dek
+FROM deliveries
+WHERE order_uid = ANY($1) AND date_created >= $2 AND date_created < $3
+`, uids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	dRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repo.DeliveryRow, error) {
+		var r repo.DeliveryRow
+		if err := row.Scan(&r.OrderUID, &r.Name, &r.Phone, &r.Zip, &r.City, &r.Address, &r.Region, &r.Email,
+			&r.KeyID, &r.WrappedDEK); err != nil {
+			return r, err
+		}
+		// В архив уходят открытые значения: у архива своё шифрование хранилища.
+		return r, o.openDelivery(&r)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range dRows {
+		if ord := byUID[r.OrderUID]; ord != nil {
+			ord.Delivery = converter.ConvertRepoDeliveryToServiceDelivery(r)
+		}
+	}
+
+	rows, err = o.pool.Query(ctx, `
+SELECT order_uid, transaction, request_id, currency, provider,
+       amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+FROM payments
+WHERE order_uid = ANY($1) AND date_created >= $2 AND date_created < $3
+`, uids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	pRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repo.PaymentRow, error) {
+		var r repo.PaymentRow
+		err := row.Scan(&r.OrderUID, &r.Transaction, &r.RequestID, &r.Currency, &r.Provider,
+			&r.Amount, &r.PaymentDT, &r.Bank, &r.DeliveryCost, &r.GoodsTotal, &r.CustomFee)
+		return r, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range pRows {
+		if ord := byUID[r.OrderUID]; ord != nil {
+			ord.Payment = converter.ConvertRepoPaymentToServicePayment(r)
+		}
+	}
+
+	rows, err = o.pool.Query(ctx, `
+SELECT order_uid, chrt_id, track_number,
+       price, rid, name, sale, size, total_price,
+       nm_id, brand, status
+FROM items
+WHERE order_uid = ANY($1) AND date_created >= $2 AND date_created < $3
+ORDER BY id
+`, uids, from, to)
+	if err != nil {
+		return nil, err
+	}
+	iRows, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (repo.ItemRow, error) {
+		var r repo.ItemRow
+		err := row.Scan(&r.OrderUID, &r.ChrtID, &r.TrackNumber, &r.Price, &r.Rid, &r.Name,
+			&r.Sale, &r.Size, &r.TotalPrice, &r.NmId, &r.Brand, &r.Status)
+		return r, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range iRows {
+		if ord := byUID[r.OrderUID]; ord != nil {
+			ord.Items = append(ord.Items, converter.ConvertRepoItemToServiceItem(r))
+		}
+	}
+
+	return out, nil
+}
+
+// DeleteOrders удаляет заказы с date_created в [from, to) вместе с доставкой,
+// оплатой и товарами. Если удалено не expect заказов (например, в диапазон
+// успел записаться ещё один), транзакция откатывается с ErrDeleteMismatch.
+func (o *OrderRepository) DeleteOrders(ctx context.Context, from, to time.Time, expect int) error {
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	committed := false
+	defer func() {
+		if committed {
+			return
+		}
+		_ = tx.Rollback(ctx)
+	}()
+
+	for _, table := range []string{"items", "payments", "deliveries"} {
+		if _, err := tx.Exec(ctx,
+			`DELETE FROM `+table+` WHERE date_created >= $1 AND date_created < $2`, from, to); err != nil {
+			return err
+		}
+	}
+
+	tag, err := tx.Exec(ctx, `DELETE FROM orders WHERE date_created >= $1 AND date_created < $2`, from, to)
+	if err != nil {
+		return err
+	}
+	if got := tag.RowsAffected(); got != int64(expect) {
+		return fmt.Errorf("%w: deleted %d, archived %d", ErrDeleteMismatch, got, expect)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
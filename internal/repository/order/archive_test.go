@@ -0,0 +1,58 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_DeleteOrders_OK(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"items", "payments", "deliveries"} {
+		mock.ExpectExec("DELETE FROM "+table).WithArgs(from, to).
+			WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	}
+	mock.ExpectExec("DELETE FROM orders").WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("DELETE", 2))
+	mock.ExpectCommit()
+
+	require.NoError(t, New(mock).DeleteOrders(context.Background(), from, to, 2))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_DeleteOrders_MismatchRollsBack(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	mock.ExpectBegin()
+	for _, table := range []string{"items", "payments", "deliveries"} {
+		mock.ExpectExec("DELETE FROM "+table).WithArgs(from, to).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	}
+	mock.ExpectExec("DELETE FROM orders").WithArgs(from, to).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectRollback()
+
+	err = New(mock).DeleteOrders(context.Background(), from, to, 2)
+	require.True(t, errors.Is(err, ErrDeleteMismatch), "got %v", err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
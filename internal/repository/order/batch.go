@@ -0,0 +1,182 @@
+package order
+
+import (
+	"app/internal/logger"
+	service "app/internal/model"
+	"app/internal/repository/converter"
+	repo "app/internal/repository/model"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// MaxBatch — наибольшее число UID в одном GetOrders.
+const MaxBatch = 1000
+
+// GetOrders читает заказы пачкой: по одному запросу на таблицу вместо
+// четырёх на заказ. Отсутствующие UID пропускаются, порядок результата не
+// задан. Реплика выбирается так же, как в GetOrder: если хоть один заказ
+// только что записан, пачка читается с primary; не найденные на реплике
+// UID дочитываются с primary.
+func (o *OrderRepository) GetOrders(ctx context.Context, uids []string) ([]service.Order, error) {
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	if len(uids) > MaxBatch {
+		return nil, fmt.Errorf("get orders: %d uids, at most %d", len(uids), MaxBatch)
+	}
+
+	if r := o.replica; r != nil && !r.down.Load() && !slices.ContainsFunc(uids, r.recentlyWritten) {
+		orders, err := o.getOrders(ctx, r.pool, uids)
+		if err == nil && len(orders) < len(uids) {
+			return o.getMissing(ctx, uids, orders)
+		}
+		if err == nil || ctx.Err() != nil {
+			return orders, err
+		}
+		logger.Warn(ctx, "replica read failed, retrying on primary", zap.Error(err))
+	}
+	return o.getOrders(ctx, o.pool, uids)
+}
+
+// getMissing дочитывает с primary заказы uids, которых нет в found.
+func (o *OrderRepository) getMissing(ctx context.Context, uids []string, found []service.Order) ([]service.Order, error) {
+	have := make(map[string]bool, len(found))
+	for _, v := range found {
+		have[v.OrderUUID] = true
+	}
+	var missing []string
+	for _, uid := range uids {
+		if !have[uid] {
+			have[uid] = true
+			missing = append(missing, uid)
+		}
+	}
+	if len(missing) == 0 {
+		return found, nil
+	}
+
+	rest, err := o.getOrders(ctx, o.pool, missing)
+	if err != nil {
+		return nil, err
+	}
+	return append(found, rest...), nil
+}
+
+func (o *OrderRepository) getOrders(ctx context.Context, q Pool, uids []string) ([]service.Order, error) {
+	byUID := make(map[string]*service.Order, len(uids))
+	var found []string
+
+	err := queryRows(ctx, q, `
+SELECT order_uid, track_number, entry, locale, internal_signature,
+       customer_id, delivery_service, shardkey,
+       sm_id, date_created, oof_shard
+FROM orders
+WHERE order_uid = ANY($1)
+`, uids, func(rows pgx.Rows) error {
+		r, err := scanOrderRow(rows)
+		if err != nil {
+			return err
+		}
+		v := converter.ConvertRepoOrderToServiceOrder(r)
+		byUID[r.OrderUUID] = &v
+		found = append(found, r.OrderUUID)
+		return nil
+	})
+	if err != nil || len(found) == 0 {
+		return nil, err
+	}
+
+	seen := make(map[string]int, len(found))
+	err = queryRows(ctx, q, `
+SELECT order_uid, name, phone, zip, city,
+       address, region, email, key_id, wrapped_dek
+FROM deliveries
+WHERE order_uid = ANY($1)
+`, found, func(rows pgx.Rows) error {
+		r, err := scanDeliveryRow(rows)
+		if err != nil {
+			return err
+		}
+		if err := o.openDelivery(&r); err != nil {
+			return err
+		}
+		if v, ok := byUID[r.OrderUID]; ok {
+			v.Delivery = converter.ConvertRepoDeliveryToServiceDelivery(r)
+			seen[r.OrderUID] |= 1
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = queryRows(ctx, q, `
+SELECT order_uid, transaction, request_id, currency, provider,
+       amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+FROM payments
+WHERE order_uid = ANY($1)
+`, found, func(rows pgx.Rows) error {
+		r, err := scanPaymentRow(rows)
+		if err != nil {
+			return err
+		}
+		if v, ok := byUID[r.OrderUID]; ok {
+			v.Payment = converter.ConvertRepoPaymentToServicePayment(r)
+			seen[r.OrderUID] |= 2
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[string][]repo.ItemRow, len(found))
+	err = queryRows(ctx, q, `
+SELECT order_uid, chrt_id, track_number,
+       price, rid, name, sale, size, total_price,
+       nm_id, brand, status
+FROM items
+WHERE order_uid = ANY($1)
+`, found, func(rows pgx.Rows) error {
+		r, err := scanItemRow(rows)
+		if err != nil {
+			return err
+		}
+		items[r.OrderUID] = append(items[r.OrderUID], r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]service.Order, 0, len(found))
+	for _, uid := range found {
+		// Как и GetOrder, заказ без доставки или оплаты считаем ошибкой.
+		if seen[uid] != 3 {
+			return nil, fmt.Errorf("order %s: delivery or payment: %w", uid, pgx.ErrNoRows)
+		}
+		v := byUID[uid]
+		v.Items = converter.RowsToItems(items[uid])
+		out = append(out, *v)
+	}
+	return out, nil
+}
+
+func queryRows(ctx context.Context, q Pool, sql string, uids []string, fn func(pgx.Rows) error) error {
+	rows, err := q.Query(ctx, sql, uids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := fn(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
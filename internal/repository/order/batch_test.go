@@ -0,0 +1,117 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	orderCols = []string{
+		"order_uid", "track_number", "entry", "locale", "internal_signature",
+		"customer_id", "delivery_service", "shardkey",
+		"sm_id", "date_created", "oof_shard",
+	}
+	deliveryCols = []string{
+		"order_uid", "name", "phone", "zip", "city", "address", "region", "email", "key_id", "wrapped_dek",
+	}
+	paymentCols = []string{
+		"order_uid", "transaction", "request_id", "currency", "provider",
+		"amount", "payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee",
+	}
+	itemCols = []string{
+		"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size",
+		"total_price", "nm_id", "brand", "status",
+	}
+)
+
+func TestOrderRepository_GetOrders_OK(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	r := &OrderRepository{pool: mock}
+	now := time.Now().UTC()
+	uids := []string{"uid-1", "uid-2", "missing"}
+	found := []string{"uid-1", "uid-2"}
+
+	// По одному запросу на таблицу для всей пачки.
+	mock.ExpectQuery("FROM orders").
+		WithArgs(uids).
+		WillReturnRows(pgxmock.NewRows(orderCols).
+			AddRow("uid-1", "track-1", "entry", "ru", "sig", "cust-1", "dhl", "shard", int32(1), now, "off").
+			AddRow("uid-2", "track-2", "entry", "ru", "sig", "cust-2", "meest", "shard", int32(1), now, "off"))
+	mock.ExpectQuery("FROM deliveries").
+		WithArgs(found).
+		WillReturnRows(pgxmock.NewRows(deliveryCols).
+			AddRow("uid-2", "n2", "p", "z", "c", "a", "r", "e", nil, nil).
+			AddRow("uid-1", "n1", "p", "z", "c", "a", "r", "e", nil, nil))
+	mock.ExpectQuery("FROM payments").
+		WithArgs(found).
+		WillReturnRows(pgxmock.NewRows(paymentCols).
+			AddRow("uid-1", "t1", "r", "RUB", "prov", int32(10), int64(1), "b", int32(1), int32(2), int32(3)).
+			AddRow("uid-2", "t2", "r", "RUB", "prov", int32(20), int64(1), "b", int32(1), int32(2), int32(3)))
+	mock.ExpectQuery("FROM items").
+		WithArgs(found).
+		WillReturnRows(pgxmock.NewRows(itemCols).
+			AddRow("uid-1", int64(1), "track-1", int32(100), "rid", "a", int32(0), "0", int32(100), int64(10), "br", int32(1)).
+			AddRow("uid-1", int64(2), "track-1", int32(50), "rid", "b", int32(0), "0", int32(50), int64(11), "br", int32(1)))
+
+	got, err := r.GetOrders(ctx, uids)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+
+	require.Equal(t, "uid-1", got[0].OrderUUID)
+	require.Equal(t, "n1", got[0].Delivery.Name)
+	require.Equal(t, "t1", got[0].Payment.Transaction)
+	require.Len(t, got[0].Items, 2)
+
+	require.Equal(t, "uid-2", got[1].OrderUUID)
+	require.Equal(t, "n2", got[1].Delivery.Name)
+	require.Equal(t, 20, got[1].Payment.Amount)
+	require.Empty(t, got[1].Items)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_GetOrders_MissingPayment(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	r := &OrderRepository{pool: mock}
+	uids := []string{"uid-1"}
+
+	mock.ExpectQuery("FROM orders").
+		WithArgs(uids).
+		WillReturnRows(pgxmock.NewRows(orderCols).
+			AddRow("uid-1", "track-1", "entry", "ru", "sig", "cust-1", "dhl", "shard", int32(1), time.Now(), "off"))
+	mock.ExpectQuery("FROM deliveries").
+		WithArgs(uids).
+		WillReturnRows(pgxmock.NewRows(deliveryCols).
+			AddRow("uid-1", "n", "p", "z", "c", "a", "r", "e", nil, nil))
+	mock.ExpectQuery("FROM payments").
+		WithArgs(uids).
+		WillReturnRows(pgxmock.NewRows(paymentCols))
+	mock.ExpectQuery("FROM items").
+		WithArgs(uids).
+		WillReturnRows(pgxmock.NewRows(itemCols))
+
+	_, err = r.GetOrders(ctx, uids)
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+
+	_, err = r.GetOrders(ctx, make([]string, MaxBatch+1))
+	require.Error(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,217 @@
+package order
+
+import (
+	"app/internal/fieldcrypt"
+	repo "app/internal/repository/model"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Виды blind index: разделяют HMAC одинаковых значений в разных полях.
+const (
+	bidxEmail = "email"
+	bidxPhone = "phone"
+)
+
+var ErrEncryptionDisabled = errors.New("deliveries: row is encrypted but encryption is disabled")
+
+type piiField struct {
+	column string
+	value  *string
+}
+
+// deliveryPII — персональные поля доставки, которые хранятся зашифрованными.
+// zip, city и region остаются открытыми: по ним строятся отчёты.
+func deliveryPII(r *repo.DeliveryRow) []piiField {
+	return []piiField{
+		{"name", &r.Name},
+		{"phone", &r.Phone},
+		{"address", &r.Address},
+		{"email", &r.Email},
+	}
+}
+
+// aad привязывает шифротекст к заказу и колонке: значение, скопированное
+// в чужую строку или колонку, не расшифруется.
+func aad(orderUID, column string) string {
+	return orderUID + "/" + column
+}
+
+// sealDelivery шифрует персональные поля новым DEK и заполняет blind index.
+// Без шифра строка остаётся открытой.
+func (o *OrderRepository) sealDelivery(r *repo.DeliveryRow) error {
+	if o.cipher == nil {
+		return nil
+	}
+	dk, err := o.cipher.NewDataKey()
+	if err != nil {
+		return err
+	}
+	r.EmailBidx = o.emailIndex(r.Email)
+	r.PhoneBidx = o.phoneIndex(r.Phone)
+	for _, f := range deliveryPII(r) {
+		if *f.value, err = dk.Encrypt(*f.value, aad(r.OrderUID, f.column)); err != nil {
+			return err
+		}
+	}
+	r.KeyID, r.WrappedDEK = &dk.KeyID, dk.Wrapped
+	return nil
+}
+
+// openDelivery расшифровывает персональные поля строки, если они зашифрованы.
+func (o *OrderRepository) openDelivery(r *repo.DeliveryRow) error {
+	if r.KeyID == nil {
+		return nil
+	}
+	if o.cipher == nil {
+		return ErrEncryptionDisabled
+	}
+	dk, err := o.cipher.OpenDataKey(*r.KeyID, r.WrappedDEK)
+	if err != nil {
+		return fmt.Errorf("deliveries %s: %w", r.OrderUID, err)
+	}
+	for _, f := range deliveryPII(r) {
+		if *f.value, err = dk.Decrypt(*f.value, aad(r.OrderUID, f.column)); err != nil {
+			return fmt.Errorf("deliveries %s: %s: %w", r.OrderUID, f.column, err)
+		}
+	}
+	return nil
+}
+
+func (o *OrderRepository) emailIndex(email string) []byte {
+	if o.cipher == nil || fieldcrypt.NormalizeEmail(email) == "" {
+		return nil
+	}
+	return o.cipher.BlindIndex(bidxEmail, fieldcrypt.NormalizeEmail(email))
+}
+
+func (o *OrderRepository) phoneIndex(phone string) []byte {
+	if o.cipher == nil || fieldcrypt.NormalizePhone(phone) == "" {
+		return nil
+	}
+	return o.cipher.BlindIndex(bidxPhone, fieldcrypt.NormalizePhone(phone))
+}
+
+// OrderUIDsByEmail находит заказы по email доставки: зашифрованные строки —
+// по blind index, ещё не зашифрованные — по открытому значению.
+func (o *OrderRepository) OrderUIDsByEmail(ctx context.Context, email string) ([]string, error) {
+	norm := fieldcrypt.NormalizeEmail(email)
+	if norm == "" {
+		return nil, nil
+	}
+	return o.orderUIDs(ctx, `
+SELECT DISTINCT order_uid FROM deliveries
+WHERE email_bidx = $1 OR (key_id IS NULL AND lower(trim(email)) = $2)
+ORDER BY order_uid
+`, o.emailIndex(email), norm)
+}
+
+// OrderUIDsByPhone находит заказы по телефону доставки; сравниваются только
+// цифры номера.
+func (o *OrderRepository) OrderUIDsByPhone(ctx context.Context, phone string) ([]string, error) {
+	norm := fieldcrypt.NormalizePhone(phone)
+	if norm == "" {
+		return nil, nil
+	}
+	return o.orderUIDs(ctx, `
+SELECT DISTINCT order_uid FROM deliveries
+WHERE phone_bidx = $1 OR (key_id IS NULL AND regexp_replace(phone, '\D', '', 'g') = $2)
+ORDER BY order_uid
+`, o.phoneIndex(phone), norm)
+}
+
+func (o *OrderRepository) orderUIDs(ctx context.Context, query string, args ...any) ([]string, error) {
+	rows, err := o.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+const selectStaleDeliveriesQuery = `
+SELECT id, date_created, order_uid, name, phone, address, email, key_id, wrapped_dek
+FROM deliveries
+WHERE key_id IS DISTINCT FROM $1
+LIMIT $2
+FOR UPDATE SKIP LOCKED
+`
+
+const encryptDeliveryQuery = `
+UPDATE deliveries
+SET name = $3, phone = $4, address = $5, email = $6,
+    key_id = $7, wrapped_dek = $8, email_bidx = $9, phone_bidx = $10
+WHERE id = $1 AND date_created = $2
+`
+
+const rewrapDeliveryQuery = `
+UPDATE deliveries SET key_id = $3, wrapped_dek = $4
+WHERE id = $1 AND date_created = $2
+`
+
+type staleDelivery struct {
+	repo.DeliveryRow
+	dateCreated time.Time
+}
+
+// ReencryptDeliveries доводит до активного KEK не больше batch строк:
+// открытые строки шифрует, у зашифрованных старым KEK перешифровывает
+// только DEK. Строки, занятые другой репликой, пропускаются, поэтому
+// job может работать на нескольких репликах одновременно. Возвращает
+// число обработанных строк.
+func (o *OrderRepository) ReencryptDeliveries(ctx context.Context, batch int) (int, error) {
+	if o.cipher == nil {
+		return 0, errors.New("deliveries: reencrypt requires encryption to be enabled")
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, selectStaleDeliveriesQuery, o.cipher.ActiveKeyID(), batch)
+	if err != nil {
+		return 0, err
+	}
+	stale, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (staleDelivery, error) {
+		var r staleDelivery
+		err := row.Scan(&r.ID, &r.dateCreated, &r.OrderUID, &r.Name, &r.Phone,
+			&r.Address, &r.Email, &r.KeyID, &r.WrappedDEK)
+		return r, err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, r := range stale {
+		if r.KeyID != nil {
+			keyID, wrapped, err := o.cipher.Rewrap(*r.KeyID, r.WrappedDEK)
+			if err != nil {
+				return 0, fmt.Errorf("deliveries %s: %w", r.OrderUID, err)
+			}
+			if _, err := tx.Exec(ctx, rewrapDeliveryQuery, r.ID, r.dateCreated, keyID, wrapped); err != nil {
+				return 0, err
+			}
+			continue
+		}
+
+		if err := o.sealDelivery(&r.DeliveryRow); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(ctx, encryptDeliveryQuery, r.ID, r.dateCreated,
+			r.Name, r.Phone, r.Address, r.Email,
+			r.KeyID, r.WrappedDEK, r.EmailBidx, r.PhoneBidx,
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(stale), nil
+}
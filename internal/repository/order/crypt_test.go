@@ -0,0 +1,112 @@
+package order
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/fieldcrypt"
+	repo "app/internal/repository/model"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCipher(t *testing.T, active string) *fieldcrypt.Cipher {
+	t.Helper()
+
+	c, err := fieldcrypt.New(map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{2}, 32),
+	}, active, bytes.Repeat([]byte{9}, 32))
+	require.NoError(t, err)
+	return c
+}
+
+func TestOrderRepository_SealOpenDelivery(t *testing.T) {
+	t.Parallel()
+
+	r := New(nil, WithCipher(newTestCipher(t, "k1")))
+	plain := repo.DeliveryRow{OrderUID: "uid-1", Name: "Ivan", Phone: "+7 999 123-45-67",
+		City: "Moscow", Address: "Lenina 1", Email: "Ivan@Example.com"}
+
+	d := plain
+	require.NoError(t, r.sealDelivery(&d))
+	require.Equal(t, "k1", *d.KeyID)
+	require.NotEqual(t, plain.Name, d.Name)
+	require.NotEqual(t, plain.Email, d.Email)
+	require.Equal(t, plain.City, d.City)
+	require.Equal(t, r.emailIndex("ivan@example.com"), d.EmailBidx)
+	require.Equal(t, r.phoneIndex("79991234567"), d.PhoneBidx)
+
+	opened := d
+	require.NoError(t, r.openDelivery(&opened))
+	require.Equal(t, plain.Name, opened.Name)
+	require.Equal(t, plain.Phone, opened.Phone)
+	require.Equal(t, plain.Address, opened.Address)
+	require.Equal(t, plain.Email, opened.Email)
+
+	// Шифротекст привязан к заказу.
+	moved := d
+	moved.OrderUID = "uid-2"
+	require.ErrorIs(t, r.openDelivery(&moved), fieldcrypt.ErrDecrypt)
+
+	require.ErrorIs(t, New(nil).openDelivery(&d), ErrEncryptionDisabled)
+}
+
+func TestOrderRepository_OrderUIDsByEmail(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	r := New(mock, WithCipher(newTestCipher(t, "k1")))
+
+	mock.ExpectQuery("FROM deliveries").
+		WithArgs(r.emailIndex("a@b.c"), "a@b.c").
+		WillReturnRows(pgxmock.NewRows([]string{"order_uid"}).AddRow("uid-1").AddRow("uid-2"))
+
+	uids, err := r.OrderUIDsByEmail(context.Background(), " A@B.c ")
+	require.NoError(t, err)
+	require.Equal(t, []string{"uid-1", "uid-2"}, uids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_ReencryptDeliveries(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	old := New(nil, WithCipher(newTestCipher(t, "k1")))
+	sealed := repo.DeliveryRow{OrderUID: "uid-2", Name: "n", Phone: "p", Address: "a", Email: "e"}
+	require.NoError(t, old.sealDelivery(&sealed))
+
+	r := New(mock, WithCipher(newTestCipher(t, "k2")))
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("FOR UPDATE SKIP LOCKED").
+		WithArgs("k2", 10).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"id", "date_created", "order_uid", "name", "phone", "address", "email", "key_id", "wrapped_dek",
+		}).
+			AddRow(int64(1), now, "uid-1", "n", "p", "a", "e", nil, nil).
+			AddRow(int64(2), now, "uid-2", sealed.Name, sealed.Phone, sealed.Address, sealed.Email, sealed.KeyID, sealed.WrappedDEK))
+	mock.ExpectExec("SET name").
+		WithArgs(int64(1), now, pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(),
+			pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("SET key_id").
+		WithArgs(int64(2), now, "k2", pgxmock.AnyArg()).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	n, err := r.ReencryptDeliveries(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
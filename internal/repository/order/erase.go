@@ -0,0 +1,91 @@
+package order
+
+import (
+	"app/internal/fieldcrypt"
+	service "app/internal/model"
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Стирается всё, что указывает на человека; city и region остаются для
+// отчётов. Строка становится открытой: шифровать больше нечего.
+const eraseDeliveriesQuery = `
+UPDATE deliveries
+SET name = '', phone = '', zip = '', address = '', email = '',
+    key_id = NULL, wrapped_dek = NULL, email_bidx = NULL, phone_bidx = NULL
+WHERE ($1 <> '' AND order_uid IN (SELECT order_uid FROM orders WHERE customer_id = $1))
+   OR email_bidx = $2
+   OR ($3 <> '' AND key_id IS NULL AND lower(trim(email)) = $3)
+   OR phone_bidx = $4
+   OR ($5 <> '' AND key_id IS NULL AND regexp_replace(phone, '\D', '', 'g') = $5)
+RETURNING order_uid
+`
+
+const insertErasureQuery = `
+INSERT INTO erasures (requested_by, customer_id, email_hmac, phone_hmac, order_uids)
+VALUES ($1, NULLIF($2, ''), $3, $4, $5)
+RETURNING id, created_at
+`
+
+// Виды HMAC для аудита: отличаются от blind index доставок, чтобы запись
+// аудита нельзя было сопоставить с новыми заказами того же человека.
+const (
+	auditEmail = "erasure:email"
+	auditPhone = "erasure:phone"
+)
+
+// EraseCustomer обезличивает доставки всех заказов субъекта и в той же
+// транзакции пишет запись аудита. Заказы, оплаты и позиции не меняются.
+// Затрагиваются только строки в БД: архив обрабатывается отдельно.
+func (o *OrderRepository) EraseCustomer(ctx context.Context, req service.ErasureRequest) (service.ErasureReport, error) {
+	email := fieldcrypt.NormalizeEmail(req.Email)
+	phone := fieldcrypt.NormalizePhone(req.Phone)
+	if req.CustomerID == "" && email == "" && phone == "" {
+		return service.ErasureReport{}, service.ErrEmptyErasure
+	}
+
+	tx, err := o.pool.Begin(ctx)
+	if err != nil {
+		return service.ErasureReport{}, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, eraseDeliveriesQuery,
+		req.CustomerID, o.emailIndex(email), email, o.phoneIndex(phone), phone)
+	if err != nil {
+		return service.ErasureReport{}, err
+	}
+	uids, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return service.ErasureReport{}, err
+	}
+	if uids == nil {
+		uids = []string{}
+	}
+
+	rep := service.ErasureReport{RequestedBy: req.RequestedBy, OrderUIDs: uids}
+	if err := tx.QueryRow(ctx, insertErasureQuery,
+		req.RequestedBy, req.CustomerID, o.auditHMAC(auditEmail, email), o.auditHMAC(auditPhone, phone), uids,
+	).Scan(&rep.ID, &rep.ErasedAt); err != nil {
+		return service.ErasureReport{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return service.ErasureReport{}, err
+	}
+	for _, uid := range uids {
+		o.replica.markWritten(uid)
+	}
+	return rep, nil
+}
+
+// auditHMAC — HMAC значения под ключом blind index. Без шифрования ключа
+// нет, и в аудит ничего не пишется: несолёный хэш email или телефона
+// перебирается по словарю.
+func (o *OrderRepository) auditHMAC(kind, normalized string) []byte {
+	if o.cipher == nil || normalized == "" {
+		return nil
+	}
+	return o.cipher.BlindIndex(kind, normalized)
+}
@@ -0,0 +1,73 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_EraseCustomer(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	r := New(mock, WithCipher(newTestCipher(t, "k1")))
+	now := time.Now().UTC()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE deliveries").
+		WithArgs("cust-1", r.emailIndex("a@b.c"), "a@b.c", []byte(nil), "").
+		WillReturnRows(pgxmock.NewRows([]string{"order_uid"}).AddRow("uid-1").AddRow("uid-2"))
+	mock.ExpectQuery("INSERT INTO erasures").
+		WithArgs("admin", "cust-1", r.cipher.BlindIndex(auditEmail, "a@b.c"), []byte(nil), []string{"uid-1", "uid-2"}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), now))
+	mock.ExpectCommit()
+
+	rep, err := r.EraseCustomer(context.Background(), model.ErasureRequest{
+		CustomerID: "cust-1", Email: "A@b.c", RequestedBy: "admin",
+	})
+	require.NoError(t, err)
+	require.Equal(t, model.ErasureReport{
+		ID: 7, RequestedBy: "admin", OrderUIDs: []string{"uid-1", "uid-2"}, ErasedAt: now,
+	}, rep)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_EraseCustomer_AuditWithoutEncryption(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	now := time.Now().UTC()
+	mock.ExpectBegin()
+	mock.ExpectQuery("UPDATE deliveries").
+		WithArgs("", []byte(nil), "a@b.c", []byte(nil), "79991234567").
+		WillReturnRows(pgxmock.NewRows([]string{"order_uid"}).AddRow("uid-1"))
+	// Без ключа email и телефон в аудит не пишутся.
+	mock.ExpectQuery("INSERT INTO erasures").
+		WithArgs("admin", "", []byte(nil), []byte(nil), []string{"uid-1"}).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(8), now))
+	mock.ExpectCommit()
+
+	_, err = New(mock).EraseCustomer(context.Background(), model.ErasureRequest{
+		Email: "a@b.c", Phone: "+7 999 123-45-67", RequestedBy: "admin",
+	})
+	require.NoError(t, err)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOrderRepository_EraseCustomer_Empty(t *testing.T) {
+	t.Parallel()
+
+	_, err := New(nil).EraseCustomer(context.Background(), model.ErasureRequest{Phone: "+-"})
+	require.ErrorIs(t, err, model.ErrEmptyErasure)
+}
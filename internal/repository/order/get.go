@@ -6,27 +6,34 @@ import (
 	repo "app/internal/repository/model"
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/jackc/pgx/v5"
 )
 
 func (o *OrderRepository) GetOrder(ctx context.Context, uuid string) (service.Order, error) {
-	oRow, err := o.getOrderRow(ctx, uuid)
+	return o.read(ctx, uuid, func(q Pool) (service.Order, error) {
+		return o.getOrder(ctx, q, uuid)
+	})
+}
+
+func (o *OrderRepository) getOrder(ctx context.Context, q Pool, uuid string) (service.Order, error) {
+	oRow, err := o.getOrderRow(ctx, q, uuid)
 	if err != nil {
 		return service.Order{}, err
 	}
 
-	dRow, err := o.getDeliveryRow(ctx, uuid)
+	dRow, err := o.getDeliveryRow(ctx, q, uuid)
 	if err != nil {
 		return service.Order{}, err
 	}
 
-	pRow, err := o.getPaymentRow(ctx, uuid)
+	pRow, err := o.getPaymentRow(ctx, q, uuid)
 	if err != nil {
 		return service.Order{}, err
 	}
 
-	itRows, err := o.getItemsRow(ctx, uuid)
+	itRows, err := o.getItemsRow(ctx, q, uuid)
 	if err != nil {
 		return service.Order{}, err
 	}
@@ -39,8 +46,8 @@ func (o *OrderRepository) GetOrder(ctx context.Context, uuid string) (service.Or
 	return order, nil
 }
 
-func (o *OrderRepository) getOrderRow(ctx context.Context, uuid string) (repo.OrderRow, error) {
-	rows, err := o.pool.Query(ctx, `
+func (o *OrderRepository) getOrderRow(ctx context.Context, q Pool, uuid string) (repo.OrderRow, error) {
+	rows, err := q.Query(ctx, `
 SELECT order_uid, track_number, entry, locale, internal_signature,
        customer_id, delivery_service, shardkey,
        sm_id, date_created, oof_shard
@@ -52,27 +59,16 @@ WHERE order_uid = $1
 	}
 	defer rows.Close()
 
-	var oRow repo.OrderRow
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
 			return repo.OrderRow{}, err
 		}
-		return repo.OrderRow{}, pgx.ErrNoRows
-	}
-
-	if err := rows.Scan(
-		&oRow.OrderUUID,
-		&oRow.TrackNumber,
-		&oRow.Entry,
-		&oRow.Locale,
-		&oRow.InternalSignature,
-		&oRow.CustomerID,
-		&oRow.DeliveryService,
-		&oRow.ShardKey,
-		&oRow.SmID,
-		&oRow.DateCreated,
-		&oRow.OffShard,
-	); err != nil {
+		// Оставляем и pgx.ErrNoRows: по нему replica решает, идти ли на primary.
+		return repo.OrderRow{}, fmt.Errorf("order %s: %w: %w", uuid, service.ErrNotFound, pgx.ErrNoRows)
+	}
+
+	oRow, err := scanOrderRow(rows)
+	if err != nil {
 		return repo.OrderRow{}, err
 	}
 
@@ -87,10 +83,10 @@ WHERE order_uid = $1
 	return oRow, nil
 }
 
-func (o *OrderRepository) getDeliveryRow(ctx context.Context, uuid string) (repo.DeliveryRow, error) {
-	rows, err := o.pool.Query(ctx, `
+func (o *OrderRepository) getDeliveryRow(ctx context.Context, q Pool, uuid string) (repo.DeliveryRow, error) {
+	rows, err := q.Query(ctx, `
 SELECT order_uid, name, phone, zip, city,
-       address, region, email
+       address, region, email, key_id, wrapped_dek
 FROM deliveries
 WHERE order_uid = $1
 `, uuid)
@@ -99,7 +95,6 @@ WHERE order_uid = $1
 	}
 	defer rows.Close()
 
-	var dRow repo.DeliveryRow
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
 			return repo.DeliveryRow{}, err
@@ -107,16 +102,8 @@ WHERE order_uid = $1
 		return repo.DeliveryRow{}, pgx.ErrNoRows
 	}
 
-	if err := rows.Scan(
-		&dRow.OrderUID,
-		&dRow.Name,
-		&dRow.Phone,
-		&dRow.Zip,
-		&dRow.City,
-		&dRow.Address,
-		&dRow.Region,
-		&dRow.Email,
-	); err != nil {
+	dRow, err := scanDeliveryRow(rows)
+	if err != nil {
 		return repo.DeliveryRow{}, err
 	}
 
@@ -128,11 +115,15 @@ WHERE order_uid = $1
 		return repo.DeliveryRow{}, err
 	}
 
+	if err := o.openDelivery(&dRow); err != nil {
+		return repo.DeliveryRow{}, err
+	}
+
 	return dRow, nil
 }
 
-func (o *OrderRepository) getPaymentRow(ctx context.Context, uuid string) (repo.PaymentRow, error) {
-	rows, err := o.pool.Query(ctx, `
+func (o *OrderRepository) getPaymentRow(ctx context.Context, q Pool, uuid string) (repo.PaymentRow, error) {
+	rows, err := q.Query(ctx, `
 SELECT order_uid, transaction, request_id, currency, provider,
        amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
 FROM payments
@@ -143,7 +134,6 @@ WHERE order_uid = $1
 	}
 	defer rows.Close()
 
-	var pRow repo.PaymentRow
 	if !rows.Next() {
 		if err := rows.Err(); err != nil {
 			return repo.PaymentRow{}, err
@@ -151,19 +141,8 @@ WHERE order_uid = $1
 		return repo.PaymentRow{}, pgx.ErrNoRows
 	}
 
-	if err := rows.Scan(
-		&pRow.OrderUID,
-		&pRow.Transaction,
-		&pRow.RequestID,
-		&pRow.Currency,
-		&pRow.Provider,
-		&pRow.Amount,
-		&pRow.PaymentDT,
-		&pRow.Bank,
-		&pRow.DeliveryCost,
-		&pRow.GoodsTotal,
-		&pRow.CustomFee,
-	); err != nil {
+	pRow, err := scanPaymentRow(rows)
+	if err != nil {
 		return repo.PaymentRow{}, err
 	}
 
@@ -178,8 +157,8 @@ WHERE order_uid = $1
 	return pRow, nil
 }
 
-func (o *OrderRepository) getItemsRow(ctx context.Context, uuid string) ([]repo.ItemRow, error) {
-	rows, err := o.pool.Query(ctx, `
+func (o *OrderRepository) getItemsRow(ctx context.Context, q Pool, uuid string) ([]repo.ItemRow, error) {
+	rows, err := q.Query(ctx, `
 SELECT order_uid, chrt_id, track_number,
        price, rid, name, sale, size, total_price,
        nm_id, brand, status
@@ -193,21 +172,8 @@ WHERE order_uid = $1
 
 	items := make([]repo.ItemRow, 0, 8)
 	for rows.Next() {
-		var it repo.ItemRow
-		if err := rows.Scan(
-			&it.OrderUID,
-			&it.ChrtID,
-			&it.TrackNumber,
-			&it.Price,
-			&it.Rid,
-			&it.Name,
-			&it.Sale,
-			&it.Size,
-			&it.TotalPrice,
-			&it.NmId,
-			&it.Brand,
-			&it.Status,
-		); err != nil {
+		it, err := scanItemRow(rows)
+		if err != nil {
 			return nil, err
 		}
 		items = append(items, it)
@@ -219,3 +185,78 @@ WHERE order_uid = $1
 
 	return items, nil
 }
+
+// scan*Row читают строку в порядке колонок запросов выше; их же используют
+// пакетные запросы GetOrders.
+
+func scanOrderRow(rows pgx.Rows) (repo.OrderRow, error) {
+	var r repo.OrderRow
+	err := rows.Scan(
+		&r.OrderUUID,
+		&r.TrackNumber,
+		&r.Entry,
+		&r.Locale,
+		&r.InternalSignature,
+		&r.CustomerID,
+		&r.DeliveryService,
+		&r.ShardKey,
+		&r.SmID,
+		&r.DateCreated,
+		&r.OffShard,
+	)
+	return r, err
+}
+
+func scanDeliveryRow(rows pgx.Rows) (repo.DeliveryRow, error) {
+	var r repo.DeliveryRow
+	err := rows.Scan(
+		&r.OrderUID,
+		&r.Name,
+		&r.Phone,
+		&r.Zip,
+		&r.City,
+		&r.Address,
+		&r.Region,
+		&r.Email,
+		&r.KeyID,
+		&r.WrappedDEK,
+	)
+	return r, err
+}
+
+func scanPaymentRow(rows pgx.Rows) (repo.PaymentRow, error) {
+	var r repo.PaymentRow
+	err := rows.Scan(
+		&r.OrderUID,
+		&r.Transaction,
+		&r.RequestID,
+		&r.Currency,
+		&r.Provider,
+		&r.Amount,
+		&r.PaymentDT,
+		&r.Bank,
+		&r.DeliveryCost,
+		&r.GoodsTotal,
+		&r.CustomFee,
+	)
+	return r, err
+}
+
+func scanItemRow(rows pgx.Rows) (repo.ItemRow, error) {
+	var r repo.ItemRow
+	err := rows.Scan(
+		&r.OrderUID,
+		&r.ChrtID,
+		&r.TrackNumber,
+		&r.Price,
+		&r.Rid,
+		&r.Name,
+		&r.Sale,
+		&r.Size,
+		&r.TotalPrice,
+		&r.NmId,
+		&r.Brand,
+		&r.Status,
+	)
+	return r, err
+}
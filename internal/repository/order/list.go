@@ -0,0 +1,69 @@
+package order
+
+import (
+	service "app/internal/model"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Размер страницы ListOrderUIDs по умолчанию и наибольший.
+const (
+	DefaultListLimit = 100
+	MaxListLimit     = 1000
+)
+
+// Пустые параметры не фильтруют. Курсор сравнивается кортежем, чтобы
+// заказы с одинаковой date_created не терялись между страницами.
+const listOrderUIDsQuery = `
+SELECT o.order_uid
+FROM orders o
+WHERE ($1 = '' OR o.delivery_service = $1)
+  AND ($2 = '' OR o.customer_id = $2)
+  AND ($3 = '' OR EXISTS (
+        SELECT 1 FROM deliveries d
+        WHERE d.order_uid = o.order_uid AND d.date_created = o.date_created AND d.region = $3))
+  AND ($4::timestamptz IS NULL OR o.date_created >= $4)
+  AND ($5::timestamptz IS NULL OR o.date_created < $5)
+  AND ($6::timestamptz IS NULL OR (o.date_created, o.order_uid) < ($6, $7))
+ORDER BY o.date_created DESC, o.order_uid DESC
+LIMIT $8
+`
+
+// ListOrderUIDs возвращает UID заказов, подходящих под f, от новых к
+// старым. Списки читаются с реплики, если она есть и здорова: отставание
+// на несколько секунд для них допустимо.
+func (o *OrderRepository) ListOrderUIDs(ctx context.Context, f service.OrderFilter) ([]string, error) {
+	limit := f.Limit
+	if limit <= 0 {
+		limit = DefaultListLimit
+	}
+	limit = min(limit, MaxListLimit)
+
+	var afterTime *time.Time
+	var afterUID string
+	if f.After != nil {
+		afterTime, afterUID = &f.After.DateCreated, f.After.OrderUID
+	}
+
+	q := o.pool
+	if r := o.replica; r != nil && !r.down.Load() {
+		q = r.pool
+	}
+	rows, err := q.Query(ctx, listOrderUIDsQuery,
+		f.DeliveryService, f.CustomerID, f.Region,
+		timeOrNil(f.CreatedFrom), timeOrNil(f.CreatedTo),
+		afterTime, afterUID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,46 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"app/internal/model"
+
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderRepository_ListOrderUIDs(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	after := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectQuery("FROM orders o").
+		WithArgs("meest", "", "Kraiot", &from, (*time.Time)(nil), &after, "uid-9", MaxListLimit).
+		WillReturnRows(pgxmock.NewRows([]string{"order_uid"}).AddRow("uid-8").AddRow("uid-7"))
+
+	uids, err := New(mock).ListOrderUIDs(context.Background(), model.OrderFilter{
+		DeliveryService: "meest",
+		Region:          "Kraiot",
+		CreatedFrom:     from,
+		After:           &model.OrderCursor{DateCreated: after, OrderUID: "uid-9"},
+		Limit:           5000,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"uid-8", "uid-7"}, uids)
+
+	mock.ExpectQuery("FROM orders o").
+		WithArgs("", "", "", (*time.Time)(nil), (*time.Time)(nil), (*time.Time)(nil), "", DefaultListLimit).
+		WillReturnRows(pgxmock.NewRows([]string{"order_uid"}))
+
+	uids, err = New(mock).ListOrderUIDs(context.Background(), model.OrderFilter{})
+	require.NoError(t, err)
+	require.Empty(t, uids)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,109 @@
+package order
+
+import (
+	"app/internal/logger"
+	service "app/internal/model"
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// replica — пул реплики и состояние маршрутизации чтений. Нулевой
+// *replica означает, что реплики нет и всё читается с primary.
+type replica struct {
+	pool      Pool
+	lagWindow time.Duration
+	down      atomic.Bool
+
+	mu        sync.Mutex
+	written   map[string]time.Time
+	lastPrune time.Time
+}
+
+func newReplica(pool Pool, lagWindow time.Duration) *replica {
+	return &replica{
+		pool:      pool,
+		lagWindow: lagWindow,
+		written:   make(map[string]time.Time),
+	}
+}
+
+// SetReplicaHealthy переключает чтения на primary, пока реплика недоступна,
+// и обратно, когда она восстановилась. Без реплики ничего не делает.
+func (o *OrderRepository) SetReplicaHealthy(healthy bool) {
+	if o.replica == nil {
+		return
+	}
+	if o.replica.down.Swap(!healthy) == healthy {
+		if healthy {
+			logger.Info(context.Background(), "replica is back, routing reads to replica")
+		} else {
+			logger.Warn(context.Background(), "replica is unhealthy, routing reads to primary")
+		}
+	}
+}
+
+// read выполняет fn на реплике, если для uuid это безопасно, иначе на
+// primary. Любая ошибка реплики повторяется на primary, в том числе
+// отсутствие строк: заказ, записанный другим процессом, мог ещё не доехать
+// до реплики, а окно lagWindow знает только о своих записях.
+func (o *OrderRepository) read(ctx context.Context, uuid string, fn func(q Pool) (service.Order, error)) (service.Order, error) {
+	r := o.replica
+	if r == nil || r.down.Load() || r.recentlyWritten(uuid) {
+		return fn(o.pool)
+	}
+
+	v, err := fn(r.pool)
+	if err == nil || ctx.Err() != nil {
+		return v, err
+	}
+
+	if !errors.Is(err, pgx.ErrNoRows) {
+		logger.Warn(ctx, "replica read failed, retrying on primary", zap.Error(err))
+	}
+	return fn(o.pool)
+}
+
+// MarkWritten направляет чтения uuid на primary на lagWindow, как после
+// собственной записи. Нужен, когда заказ изменил другой процесс: иначе
+// отстающая реплика БД вернула бы прежнюю версию, и та попала бы в кэш.
+func (o *OrderRepository) MarkWritten(uuid string) {
+	o.replica.markWritten(uuid)
+}
+
+func (r *replica) markWritten(uuid string) {
+	if r == nil || r.lagWindow <= 0 {
+		return
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.written[uuid] = now
+	if now.Sub(r.lastPrune) < r.lagWindow {
+		return
+	}
+	for k, at := range r.written {
+		if now.Sub(at) >= r.lagWindow {
+			delete(r.written, k)
+		}
+	}
+	r.lastPrune = now
+}
+
+func (r *replica) recentlyWritten(uuid string) bool {
+	if r.lagWindow <= 0 || uuid == "" {
+		return false
+	}
+
+	r.mu.Lock()
+	at, ok := r.written[uuid]
+	r.mu.Unlock()
+	return ok && time.Since(at) < r.lagWindow
+}
@@ -0,0 +1,183 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"app/internal/logger"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pashagolub/pgxmock/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func newReplicaRepo(t *testing.T, lagWindow time.Duration) (*OrderRepository, pgxmock.PgxPoolIface, pgxmock.PgxPoolIface) {
+	t.Helper()
+	_ = logger.Init("error", false, nil)
+
+	primary, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(primary.Close)
+
+	rep, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	t.Cleanup(rep.Close)
+
+	return New(primary, WithReplica(rep, lagWindow)), primary, rep
+}
+
+func expectGetOrder(mock pgxmock.PgxPoolIface, uid string) {
+	mock.ExpectQuery("FROM orders").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"order_uid", "track_number", "entry", "locale", "internal_signature",
+			"customer_id", "delivery_service", "shardkey",
+			"sm_id", "date_created", "oof_shard",
+		}).AddRow(uid, "track-1", "entry", "ru", "sig", "cust-1", "dhl", "shard", int32(1), time.Now().UTC(), "off"))
+
+	mock.ExpectQuery("FROM deliveries").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"order_uid", "name", "phone", "zip", "city", "address", "region", "email", "key_id", "wrapped_dek",
+		}).AddRow(uid, "n", "p", "z", "c", "a", "r", "e", nil, nil))
+
+	mock.ExpectQuery("FROM payments").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"order_uid", "transaction", "request_id", "currency", "provider",
+			"amount", "payment_dt", "bank", "delivery_cost", "goods_total", "custom_fee",
+		}).AddRow(uid, "t", "r", "RUB", "prov", int32(10), int64(1), "b", int32(1), int32(2), int32(3)))
+
+	mock.ExpectQuery("FROM items").
+		WithArgs(uid).
+		WillReturnRows(pgxmock.NewRows([]string{
+			"order_uid", "chrt_id", "track_number", "price", "rid", "name", "sale", "size",
+			"total_price", "nm_id", "brand", "status",
+		}))
+}
+
+func TestOrderRepository_Replica_ReadsFromReplica(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	expectGetOrder(rep, "uid-1")
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+	require.NoError(t, rep.ExpectationsWereMet())
+	require.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Replica_RecentWriteReadsPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	r.replica.markWritten("uid-1")
+	expectGetOrder(primary, "uid-1")
+	expectGetOrder(rep, "uid-2")
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+	_, err = r.GetOrder(context.Background(), "uid-2")
+	require.NoError(t, err)
+
+	require.NoError(t, primary.ExpectationsWereMet())
+	require.NoError(t, rep.ExpectationsWereMet())
+}
+
+// Заказ, изменённый другим процессом (событие инвалидации), тоже читается
+// с primary, пока реплика может отставать.
+func TestOrderRepository_Replica_MarkWrittenReadsPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	r.MarkWritten("uid-1")
+	expectGetOrder(primary, "uid-1")
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+	require.NoError(t, primary.ExpectationsWereMet())
+	require.NoError(t, rep.ExpectationsWereMet())
+
+	// Без реплики отмечать нечего.
+	New(primary).MarkWritten("uid-1")
+}
+
+func TestOrderRepository_Replica_ErrorFallsBackToPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	rep.ExpectQuery("FROM orders").WithArgs("uid-1").WillReturnError(errors.New("connection refused"))
+	expectGetOrder(primary, "uid-1")
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+	require.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Replica_NoRowsRetriedOnPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	rep.ExpectQuery("FROM orders").WithArgs("uid-1").WillReturnRows(pgxmock.NewRows([]string{"order_uid"}))
+	expectGetOrder(primary, "uid-1")
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+	require.NoError(t, rep.ExpectationsWereMet())
+	require.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Replica_NotFoundOnBoth(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	rep.ExpectQuery("FROM orders").WithArgs("uid-1").WillReturnRows(pgxmock.NewRows([]string{"order_uid"}))
+	primary.ExpectQuery("FROM orders").WithArgs("uid-1").WillReturnRows(pgxmock.NewRows([]string{"order_uid"}))
+
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.NoError(t, primary.ExpectationsWereMet())
+}
+
+func TestOrderRepository_Replica_GetOrdersReadsMissingFromPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+	now := time.Now().UTC()
+
+	rep.ExpectQuery("FROM orders").
+		WithArgs([]string{"uid-1", "uid-2"}).
+		WillReturnRows(pgxmock.NewRows(orderCols).
+			AddRow("uid-1", "track-1", "entry", "ru", "sig", "cust-1", "dhl", "shard", int32(1), now, "off"))
+	expectBatchTail(rep, "uid-1")
+	primary.ExpectQuery("FROM orders").
+		WithArgs([]string{"uid-2"}).
+		WillReturnRows(pgxmock.NewRows(orderCols).
+			AddRow("uid-2", "track-2", "entry", "ru", "sig", "cust-2", "dhl", "shard", int32(1), now, "off"))
+	expectBatchTail(primary, "uid-2")
+
+	got, err := r.GetOrders(context.Background(), []string{"uid-1", "uid-2"})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.NoError(t, rep.ExpectationsWereMet())
+	require.NoError(t, primary.ExpectationsWereMet())
+}
+
+func expectBatchTail(mock pgxmock.PgxPoolIface, uid string) {
+	mock.ExpectQuery("FROM deliveries").
+		WithArgs([]string{uid}).
+		WillReturnRows(pgxmock.NewRows(deliveryCols).AddRow(uid, "n", "p", "z", "c", "a", "r", "e", nil, nil))
+	mock.ExpectQuery("FROM payments").
+		WithArgs([]string{uid}).
+		WillReturnRows(pgxmock.NewRows(paymentCols).
+			AddRow(uid, "t", "r", "RUB", "prov", int32(10), int64(1), "b", int32(1), int32(2), int32(3)))
+	mock.ExpectQuery("FROM items").
+		WithArgs([]string{uid}).
+		WillReturnRows(pgxmock.NewRows(itemCols))
+}
+
+func TestOrderRepository_Replica_UnhealthyReadsPrimary(t *testing.T) {
+	r, primary, rep := newReplicaRepo(t, time.Minute)
+
+	r.SetReplicaHealthy(false)
+	expectGetOrder(primary, "uid-1")
+	_, err := r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+
+	r.SetReplicaHealthy(true)
+	expectGetOrder(rep, "uid-1")
+	_, err = r.GetOrder(context.Background(), "uid-1")
+	require.NoError(t, err)
+
+	require.NoError(t, primary.ExpectationsWereMet())
+	require.NoError(t, rep.ExpectationsWereMet())
+}
@@ -1,7 +1,9 @@
 package order
 
 import (
+	"app/internal/fieldcrypt"
 	"context"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -12,9 +14,34 @@ type Pool interface {
 }
 
 type OrderRepository struct {
-	pool Pool
+	pool    Pool
+	replica *replica
+	cipher  *fieldcrypt.Cipher
 }
 
-func New(pool Pool) *OrderRepository {
-	return &OrderRepository{pool: pool}
+type Option func(*OrderRepository)
+
+// WithReplica направляет чтения в реплику. Заказы, записанные этим
+// экземпляром за последние lagWindow, читаются с primary, чтобы не
+// упереться в отставание репликации.
+func WithReplica(pool Pool, lagWindow time.Duration) Option {
+	return func(o *OrderRepository) {
+		o.replica = newReplica(pool, lagWindow)
+	}
+}
+
+// WithCipher включает шифрование персональных полей доставки. Без него
+// новые строки пишутся открытыми, а чтение зашифрованных возвращает ошибку.
+func WithCipher(c *fieldcrypt.Cipher) Option {
+	return func(o *OrderRepository) {
+		o.cipher = c
+	}
+}
+
+func New(pool Pool, opts ...Option) *OrderRepository {
+	o := &OrderRepository{pool: pool}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
 }
@@ -9,6 +9,7 @@ import (
 	"app/internal/model"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/pashagolub/pgxmock/v4"
 	"github.com/stretchr/testify/require"
 )
@@ -42,6 +43,10 @@ func TestOrderRepository_SetOrder_OK(t *testing.T) {
 
 	mock.ExpectBegin()
 
+	mock.ExpectExec("INSERT INTO order_keys").
+		WithArgs(order.OrderUUID, order.DateCreated).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+
 	mock.ExpectExec("INSERT INTO orders").
 		WithArgs(
 			order.OrderUUID,
@@ -61,6 +66,7 @@ func TestOrderRepository_SetOrder_OK(t *testing.T) {
 	mock.ExpectExec("INSERT INTO deliveries").
 		WithArgs(
 			order.OrderUUID,
+			order.DateCreated,
 			order.Delivery.Name,
 			order.Delivery.Phone,
 			order.Delivery.Zip,
@@ -68,12 +74,17 @@ func TestOrderRepository_SetOrder_OK(t *testing.T) {
 			order.Delivery.Address,
 			order.Delivery.Region,
 			order.Delivery.Email,
+			(*string)(nil),
+			[]byte(nil),
+			[]byte(nil),
+			[]byte(nil),
 		).
 		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 
 	mock.ExpectExec("INSERT INTO payments").
 		WithArgs(
 			order.OrderUUID,
+			order.DateCreated,
 			order.Payment.Transaction,
 			order.Payment.RequestID,
 			order.Payment.Currency,
@@ -90,6 +101,7 @@ func TestOrderRepository_SetOrder_OK(t *testing.T) {
 	mock.ExpectExec("INSERT INTO items").
 		WithArgs(
 			order.OrderUUID,
+			order.DateCreated,
 			order.Items[0].ChrtID,
 			order.Items[0].TrackNumber,
 			order.Items[0].Price,
@@ -137,6 +149,9 @@ func TestOrderRepository_SetOrder_OrderInsertError_Rollback(t *testing.T) {
 	}
 
 	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO order_keys").
+		WithArgs(order.OrderUUID, order.DateCreated).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
 	mock.ExpectExec("INSERT INTO orders").
 		WithArgs(
 			order.OrderUUID,
@@ -160,6 +175,65 @@ func TestOrderRepository_SetOrder_OrderInsertError_Rollback(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestOrderRepository_SetOrder_Duplicate(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO order_keys").
+		WithArgs(anyArgs(2)...).
+		WillReturnError(&pgconn.PgError{Code: "23505", Message: "duplicate key value"})
+	mock.ExpectRollback()
+
+	err = New(mock).SetOrder(context.Background(), model.Order{OrderUUID: "uid-1"})
+	require.ErrorIs(t, err, model.ErrAlreadyExists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+// Тот же UID с другой датой попал бы в другую секцию orders, где (order_uid,
+// date_created) свободен; дубль ловит order_keys, до вставки в orders.
+func TestOrderRepository_SetOrder_DuplicateWithOtherDate(t *testing.T) {
+	t.Parallel()
+
+	mock, err := pgxmock.NewPool()
+	require.NoError(t, err)
+	defer mock.Close()
+
+	first := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO order_keys").
+		WithArgs("uid-1", first).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO orders").
+		WithArgs(anyArgs(11)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO deliveries").
+		WithArgs(anyArgs(13)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectExec("INSERT INTO payments").
+		WithArgs(anyArgs(12)...).
+		WillReturnResult(pgxmock.NewResult("INSERT", 1))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO order_keys").
+		WithArgs("uid-1", second).
+		WillReturnError(&pgconn.PgError{Code: "23505", Message: `duplicate key value violates unique constraint "order_keys_pkey"`})
+	mock.ExpectRollback()
+
+	r := New(mock)
+	require.NoError(t, r.SetOrder(context.Background(), model.Order{OrderUUID: "uid-1", DateCreated: first}))
+
+	err = r.SetOrder(context.Background(), model.Order{OrderUUID: "uid-1", DateCreated: second})
+	require.ErrorIs(t, err, model.ErrAlreadyExists)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestOrderRepository_GetOrder_NoRows(t *testing.T) {
 	t.Parallel()
 
@@ -180,6 +254,7 @@ func TestOrderRepository_GetOrder_NoRows(t *testing.T) {
 
 	_, err = r.GetOrder(ctx, "uid-1")
 	require.ErrorIs(t, err, pgx.ErrNoRows)
+	require.ErrorIs(t, err, model.ErrNotFound)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
@@ -211,9 +286,9 @@ func TestOrderRepository_GetOrder_OK(t *testing.T) {
 	mock.ExpectQuery("FROM deliveries").
 		WithArgs("uid-1").
 		WillReturnRows(pgxmock.NewRows([]string{
-			"order_uid", "name", "phone", "zip", "city", "address", "region", "email",
+			"order_uid", "name", "phone", "zip", "city", "address", "region", "email", "key_id", "wrapped_dek",
 		}).AddRow(
-			"uid-1", "n", "p", "z", "c", "a", "r", "e",
+			"uid-1", "n", "p", "z", "c", "a", "r", "e", nil, nil,
 		))
 
 	mock.ExpectQuery("FROM payments").
@@ -242,3 +317,11 @@ func TestOrderRepository_GetOrder_OK(t *testing.T) {
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
+
+func anyArgs(n int) []any {
+	args := make([]any, n)
+	for i := range args {
+		args[i] = pgxmock.AnyArg()
+	}
+	return args
+}
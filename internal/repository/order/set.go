@@ -2,9 +2,23 @@ package order
 
 import (
 	service "app/internal/model"
+	"app/internal/repository/converter"
 	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// uniqueViolation — SQLSTATE нарушения уникальности.
+const uniqueViolation = "23505"
+
+// insertOrderKeyQuery занимает order_uid: в секционированной orders он
+// уникален только вместе с date_created.
+const insertOrderKeyQuery = `
+INSERT INTO order_keys (order_uid, date_created) VALUES ($1, $2)
+`
+
 const insertOrderQuery = `
 INSERT INTO orders (
     order_uid, track_number, entry,
@@ -18,31 +32,38 @@ INSERT INTO orders (
 
 const insertDeliveryQuery = `
 INSERT INTO deliveries (
-    order_uid, name, phone, zip, city, address, region, email
+    order_uid, date_created, name, phone, zip, city, address, region, email,
+    key_id, wrapped_dek, email_bidx, phone_bidx
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 `
 
 const insertPaymentQuery = `
 INSERT INTO payments (
-    order_uid, transaction, request_id, currency, provider,
+    order_uid, date_created, transaction, request_id, currency, provider,
     amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 `
 
 const insertItemQuery = `
 INSERT INTO items (
-    order_uid, chrt_id, track_number, price,
+    order_uid, date_created, chrt_id, track_number, price,
     rid, name, sale, size, total_price, nm_id, brand, status
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 `
 
 func (o *OrderRepository) SetOrder(ctx context.Context, order service.Order) error {
+	// Шифруем до начала транзакции: на ошибке нечего откатывать.
+	d := converter.ConvertServiceDeliveryToRepoDelivery(order.OrderUUID, order.Delivery)
+	if err := o.sealDelivery(&d); err != nil {
+		return err
+	}
+
 	tx, err := o.pool.Begin(ctx)
 	if err != nil {
 		return err
@@ -57,6 +78,14 @@ func (o *OrderRepository) SetOrder(ctx context.Context, order service.Order) err
 		_ = rbErr
 	}()
 
+	if _, err := tx.Exec(ctx, insertOrderKeyQuery, order.OrderUUID, order.DateCreated); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return fmt.Errorf("order %s: %w: %w", order.OrderUUID, service.ErrAlreadyExists, err)
+		}
+		return err
+	}
+
 	if _, err := tx.Exec(ctx, insertOrderQuery,
 		order.OrderUUID,
 		order.TrackNumber,
@@ -70,24 +99,35 @@ func (o *OrderRepository) SetOrder(ctx context.Context, order service.Order) err
 		order.DateCreated,
 		order.OffShard,
 	); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return fmt.Errorf("order %s: %w: %w", order.OrderUUID, service.ErrAlreadyExists, err)
+		}
 		return err
 	}
 
+	// date_created дублируется в дочерние таблицы: это ключ секционирования.
 	if _, err := tx.Exec(ctx, insertDeliveryQuery,
 		order.OrderUUID,
-		order.Delivery.Name,
-		order.Delivery.Phone,
-		order.Delivery.Zip,
-		order.Delivery.City,
-		order.Delivery.Address,
-		order.Delivery.Region,
-		order.Delivery.Email,
+		order.DateCreated,
+		d.Name,
+		d.Phone,
+		d.Zip,
+		d.City,
+		d.Address,
+		d.Region,
+		d.Email,
+		d.KeyID,
+		d.WrappedDEK,
+		d.EmailBidx,
+		d.PhoneBidx,
 	); err != nil {
 		return err
 	}
 
 	if _, err := tx.Exec(ctx, insertPaymentQuery,
 		order.OrderUUID,
+		order.DateCreated,
 		order.Payment.Transaction,
 		order.Payment.RequestID,
 		order.Payment.Currency,
@@ -105,6 +145,7 @@ func (o *OrderRepository) SetOrder(ctx context.Context, order service.Order) err
 	for _, it := range order.Items {
 		if _, err := tx.Exec(ctx, insertItemQuery,
 			order.OrderUUID,
+			order.DateCreated,
 			it.ChrtID,
 			it.TrackNumber,
 			it.Price,
@@ -125,5 +166,6 @@ func (o *OrderRepository) SetOrder(ctx context.Context, order service.Order) err
 		return err
 	}
 	committed = true
+	o.replica.markWritten(order.OrderUUID)
 	return nil
 }
@@ -8,4 +8,6 @@ import (
 type Repository interface {
 	SetOrder(ctx context.Context, order service.Order) error
 	GetOrder(ctx context.Context, uuid string) (service.Order, error)
+	GetOrders(ctx context.Context, uuids []string) ([]service.Order, error)
+	ListOrderUIDs(ctx context.Context, f service.OrderFilter) ([]string, error)
 }
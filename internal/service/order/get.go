@@ -3,20 +3,84 @@ package order
 import (
 	service "app/internal/model"
 	"context"
+	"errors"
+	"slices"
 )
 
+// getManyBatch — размер пачки GetOrders, не больше лимита репозитория.
+const getManyBatch = 500
+
 func (s *Service) Get(ctx context.Context, uuid string) (service.Order, error) {
 	key := "order:" + uuid
 
-	if order, err := s.cache.Get(key); err == nil {
-		return order, nil
+	// Записи, сохранённые до появления ETag, получают его при чтении.
+	order, err := s.cache.Get(key)
+	if err == nil {
+		return order.WithETag(), nil
+	}
+	if errors.Is(err, service.ErrStale) {
+		s.refreshAsync(ctx, key, uuid)
+		return order.WithETag(), nil
 	}
 
-	order, err := s.repo.GetOrder(ctx, uuid)
+	// Поколение берётся до чтения: удаление ключа во время чтения (например,
+	// после удаления данных субъекта) не перезаписывается прочитанным.
+	gen := s.cache.Generation(key)
+	order, err = s.repo.GetOrder(ctx, uuid)
 	if err != nil {
 		return service.Order{}, err
 	}
 
-	_ = s.cache.Set(key, order)
+	order = order.WithETag()
+	s.cache.Fill(key, order, gen)
 	return order, nil
 }
+
+// GetMany возвращает заказы в порядке uuids, пропуская отсутствующие;
+// повторяющийся UID отдаётся один раз. Промахи кэша читаются из БД пачками
+// по getManyBatch, а не по заказу.
+func (s *Service) GetMany(ctx context.Context, uuids []string) ([]service.Order, error) {
+	found := make(map[string]service.Order, len(uuids))
+	seen := make(map[string]struct{}, len(uuids))
+	var misses []string
+	gens := make(map[string]uint64)
+	for _, uuid := range uuids {
+		if _, ok := seen[uuid]; ok {
+			continue
+		}
+		seen[uuid] = struct{}{}
+		key := "order:" + uuid
+		order, err := s.cache.Get(key)
+		switch {
+		case err == nil:
+			found[uuid] = order.WithETag()
+		case errors.Is(err, service.ErrStale):
+			s.refreshAsync(ctx, key, uuid)
+			found[uuid] = order.WithETag()
+		default:
+			misses = append(misses, uuid)
+			gens[uuid] = s.cache.Generation(key)
+		}
+	}
+
+	for chunk := range slices.Chunk(misses, getManyBatch) {
+		loaded, err := s.repo.GetOrders(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range loaded {
+			o = o.WithETag()
+			s.cache.Fill("order:"+o.OrderUUID, o, gens[o.OrderUUID])
+			found[o.OrderUUID] = o
+		}
+	}
+
+	orders := make([]service.Order, 0, len(found))
+	for _, uuid := range uuids {
+		if o, ok := found[uuid]; ok {
+			orders = append(orders, o)
+			delete(found, uuid)
+		}
+	}
+	return orders, nil
+}
@@ -0,0 +1,17 @@
+package order
+
+import (
+	service "app/internal/model"
+	"context"
+)
+
+// List возвращает страницу заказов по фильтру. Из БД берутся только UID,
+// сами заказы читаются через кэш и догружаются пачкой (GetMany). Заказ,
+// удалённый между выборкой и чтением (архивация), пропускается.
+func (s *Service) List(ctx context.Context, f service.OrderFilter) ([]service.Order, error) {
+	uids, err := s.repo.ListOrderUIDs(ctx, f)
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMany(ctx, uids)
+}
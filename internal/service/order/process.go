@@ -1,14 +1,33 @@
 package order
 
 import (
+	"app/internal/logger"
 	service "app/internal/model"
 	"context"
+
+	"go.uber.org/zap"
 )
 
 func (s *Service) ProcessOrder(ctx context.Context, order service.Order) error {
 	if err := s.repo.SetOrder(ctx, order); err != nil {
 		return err
 	}
-	_ = s.cache.Set("order:"+order.OrderUUID, order)
+
+	order = order.WithETag()
+	key := "order:" + order.OrderUUID
+	_ = s.cache.Set(key, order)
+	s.invalidate(ctx, key)
+	if s.publisher != nil {
+		s.publisher.Publish(ctx, order)
+	}
 	return nil
 }
+
+func (s *Service) invalidate(ctx context.Context, key string) {
+	if s.invalidator == nil {
+		return
+	}
+	if err := s.invalidator.Invalidate(ctx, key); err != nil {
+		logger.Warn(ctx, "cache invalidation publish failed", zap.String("key", key), zap.Error(err))
+	}
+}
@@ -0,0 +1,41 @@
+package order
+
+import (
+	"app/internal/logger"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// refreshAsync перечитывает заказ из репозитория в фоне. Для каждого ключа
+// одновременно выполняется не больше одного обновления. Контекст запроса
+// не отменяет обновление, но передаёт ему трейс и поля логгера.
+func (s *Service) refreshAsync(ctx context.Context, key, uuid string) {
+	s.refreshMu.Lock()
+	if _, ok := s.refreshing[key]; ok {
+		s.refreshMu.Unlock()
+		return
+	}
+	s.refreshing[key] = struct{}{}
+	s.refreshMu.Unlock()
+
+	gen := s.cache.Generation(key)
+	go func() {
+		defer func() {
+			s.refreshMu.Lock()
+			delete(s.refreshing, key)
+			s.refreshMu.Unlock()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.refreshTimeout)
+		defer cancel()
+
+		order, err := s.repo.GetOrder(ctx, uuid)
+		if err != nil {
+			logger.Warn(ctx, "cache refresh failed", zap.String("order_uid", uuid), zap.Error(err))
+			return
+		}
+		// Удаление или запись во время чтения новее прочитанного.
+		s.cache.Fill(key, order.WithETag(), gen)
+	}()
+}
@@ -3,16 +3,58 @@ package order
 import (
 	"app/internal/cache"
 	"app/internal/repository"
+	"app/internal/service"
+	"sync"
+	"time"
 )
 
+const defaultRefreshTimeout = 5 * time.Second
+
 type Service struct {
-	repo  repository.Repository
-	cache cache.Cache
+	repo        repository.Repository
+	cache       cache.OrderCache
+	invalidator cache.Invalidator
+	publisher   service.Publisher
+
+	refreshTimeout time.Duration
+	refreshMu      sync.Mutex
+	refreshing     map[string]struct{}
 }
 
-func New(repo repository.Repository, cache cache.Cache) *Service {
-	return &Service{
-		repo:  repo,
-		cache: cache,
+type Option func(*Service)
+
+// WithRefreshTimeout задаёт таймаут фонового обновления устаревшей записи кэша.
+func WithRefreshTimeout(d time.Duration) Option {
+	return func(s *Service) {
+		if d > 0 {
+			s.refreshTimeout = d
+		}
+	}
+}
+
+// WithInvalidator включает рассылку инвалидаций кэша другим репликам после записи.
+func WithInvalidator(inv cache.Invalidator) Option {
+	return func(s *Service) {
+		s.invalidator = inv
+	}
+}
+
+// WithPublisher отдаёт записанные заказы в ленту новых заказов.
+func WithPublisher(p service.Publisher) Option {
+	return func(s *Service) {
+		s.publisher = p
+	}
+}
+
+func New(repo repository.Repository, cache cache.OrderCache, opts ...Option) *Service {
+	s := &Service{
+		repo:           repo,
+		cache:          cache,
+		refreshTimeout: defaultRefreshTimeout,
+		refreshing:     make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
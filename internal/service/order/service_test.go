@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
+	"app/internal/cache/ttl"
 	"app/internal/mocks"
 	"app/internal/model"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -15,11 +18,11 @@ func newTestService() (
 	ctx context.Context,
 	svc *Service,
 	repo *mocks.MockRepository,
-	cache *mocks.MockCache,
+	cache *mocks.MockCache[string, model.Order],
 ) {
 	ctx = context.Background()
 	repo = new(mocks.MockRepository)
-	cache = new(mocks.MockCache)
+	cache = new(mocks.MockCache[string, model.Order])
 	svc = New(repo, cache)
 	return
 }
@@ -30,7 +33,7 @@ func Test_ProcessOrder_OK(t *testing.T) {
 	order := model.Order{OrderUUID: "uid-1"}
 
 	repo.On("SetOrder", ctx, order).Return(nil).Once()
-	cache.On("Set", "order:"+order.OrderUUID, order).Return(nil).Once()
+	cache.On("Set", "order:"+order.OrderUUID, order.WithETag()).Return(nil).Once()
 
 	err := svc.ProcessOrder(ctx, order)
 	require.NoError(t, err)
@@ -39,6 +42,60 @@ func Test_ProcessOrder_OK(t *testing.T) {
 	cache.AssertExpectations(t)
 }
 
+type fakeInvalidator struct {
+	keys []string
+}
+
+func (f *fakeInvalidator) Invalidate(ctx context.Context, key string) error {
+	f.keys = append(f.keys, key)
+	return nil
+}
+
+func Test_ProcessOrder_PublishesInvalidation(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockRepository)
+	cache := new(mocks.MockCache[string, model.Order])
+	inv := &fakeInvalidator{}
+	svc := New(repo, cache, WithInvalidator(inv))
+
+	order := model.Order{OrderUUID: "uid-1"}
+
+	repo.On("SetOrder", ctx, order).Return(nil).Once()
+	cache.On("Set", "order:uid-1", order.WithETag()).Return(nil).Once()
+
+	require.NoError(t, svc.ProcessOrder(ctx, order))
+	require.Equal(t, []string{"order:uid-1"}, inv.keys)
+}
+
+type fakePublisher struct {
+	orders []model.Order
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, o model.Order) {
+	f.orders = append(f.orders, o)
+}
+
+func Test_ProcessOrder_PublishesToFeed(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockRepository)
+	cache := new(mocks.MockCache[string, model.Order])
+	pub := &fakePublisher{}
+	svc := New(repo, cache, WithPublisher(pub))
+
+	order := model.Order{OrderUUID: "uid-1"}
+
+	repo.On("SetOrder", ctx, order).Return(nil).Once()
+	cache.On("Set", "order:uid-1", order.WithETag()).Return(nil).Once()
+
+	require.NoError(t, svc.ProcessOrder(ctx, order))
+	require.Equal(t, []model.Order{order.WithETag()}, pub.orders)
+
+	// Незаписанный заказ в ленту не попадает.
+	repo.On("SetOrder", ctx, model.Order{OrderUUID: "uid-2"}).Return(errors.New("repo error")).Once()
+	require.Error(t, svc.ProcessOrder(ctx, model.Order{OrderUUID: "uid-2"}))
+	require.Len(t, pub.orders, 1)
+}
+
 func Test_ProcessOrder_RepoError(t *testing.T) {
 	ctx, svc, repo, cache := newTestService()
 
@@ -58,7 +115,7 @@ func Test_Get_CacheHit(t *testing.T) {
 	ctx, svc, repo, cache := newTestService()
 
 	key := "order:uid-1"
-	want := model.Order{OrderUUID: "uid-1"}
+	want := model.Order{OrderUUID: "uid-1", ETag: "cached"}
 
 	cache.On("Get", key).Return(want, nil).Once()
 
@@ -77,17 +134,40 @@ func Test_Get_CacheMiss_RepoOK_CacheSetOK(t *testing.T) {
 	want := model.Order{OrderUUID: "uid-1"}
 
 	cache.On("Get", key).Return(model.Order{}, errors.New("cache miss")).Once()
+	cache.On("Generation", key).Return(uint64(3)).Once()
 	repo.On("GetOrder", ctx, "uid-1").Return(want, nil).Once()
-	cache.On("Set", key, want).Return(nil).Once()
+	cache.On("Fill", key, want.WithETag(), uint64(3)).Return(true).Once()
 
 	got, err := svc.Get(ctx, "uid-1")
 	require.NoError(t, err)
-	require.Equal(t, want, got)
+	require.Equal(t, want.WithETag(), got)
 
 	repo.AssertExpectations(t)
 	cache.AssertExpectations(t)
 }
 
+// Удаление ключа во время чтения из БД (например, инвалидация после
+// удаления данных субъекта) не перезаписывается прочитанной версией.
+func Test_Get_DeleteDuringReadIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	repo := new(mocks.MockRepository)
+	c := ttl.New[string, model.Order](time.Minute)
+	svc := New(repo, c)
+
+	old := model.Order{OrderUUID: "uid-1", CustomerID: "before-erasure"}
+	repo.On("GetOrder", ctx, "uid-1").
+		Run(func(mock.Arguments) { c.Delete("order:uid-1") }).
+		Return(old, nil).Once()
+
+	got, err := svc.Get(ctx, "uid-1")
+	require.NoError(t, err)
+	require.Equal(t, old.WithETag(), got)
+
+	_, err = c.Get("order:uid-1")
+	require.ErrorIs(t, err, model.ErrNotFound)
+	repo.AssertExpectations(t)
+}
+
 func Test_Get_CacheMiss_RepoError(t *testing.T) {
 	ctx, svc, repo, cache := newTestService()
 
@@ -95,11 +175,115 @@ func Test_Get_CacheMiss_RepoError(t *testing.T) {
 	errRepo := errors.New("repo error")
 
 	cache.On("Get", key).Return(model.Order{}, errors.New("cache miss")).Once()
+	cache.On("Generation", key).Return(uint64(0)).Once()
 	repo.On("GetOrder", ctx, "uid-1").Return(model.Order{}, errRepo).Once()
 
 	_, err := svc.Get(ctx, "uid-1")
 	require.ErrorIs(t, err, errRepo)
 
-	cache.AssertNotCalled(t, "Set")
+	cache.AssertNotCalled(t, "Fill")
 	repo.AssertExpectations(t)
 }
+
+func Test_Get_StaleHit_ServesStaleAndRefreshesOnce(t *testing.T) {
+	ctx, svc, repo, cache := newTestService()
+
+	key := "order:uid-1"
+	stale := model.Order{OrderUUID: "uid-1", TrackNumber: "old"}
+	fresh := model.Order{OrderUUID: "uid-1", TrackNumber: "new"}
+
+	release := make(chan struct{})
+	refreshed := make(chan struct{})
+
+	type ctxKey struct{}
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, ctxKey{}, "req"))
+
+	cache.On("Get", key).Return(stale, model.ErrStale).Twice()
+	cache.On("Generation", key).Return(uint64(7)).Once()
+	var refreshCtx context.Context
+	repo.On("GetOrder", mock.Anything, "uid-1").
+		Run(func(args mock.Arguments) {
+			<-release
+			refreshCtx = args.Get(0).(context.Context)
+		}).
+		Return(fresh, nil).Once()
+	cache.On("Fill", key, fresh.WithETag(), uint64(7)).
+		Run(func(mock.Arguments) { close(refreshed) }).
+		Return(true).Once()
+
+	got, err := svc.Get(ctx, "uid-1")
+	require.NoError(t, err)
+	require.Equal(t, stale.WithETag(), got)
+
+	got, err = svc.Get(ctx, "uid-1")
+	require.NoError(t, err)
+	require.Equal(t, stale.WithETag(), got)
+
+	cancel()
+	close(release)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("background refresh did not happen")
+	}
+
+	// Обновление наследует значения запроса, но не его отмену.
+	require.Equal(t, "req", refreshCtx.Value(ctxKey{}))
+	_, hasDeadline := refreshCtx.Deadline()
+	require.True(t, hasDeadline)
+
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func Test_List_ReadsThroughCache(t *testing.T) {
+	ctx, svc, repo, cache := newTestService()
+
+	f := model.OrderFilter{Region: "Kraiot", Limit: 3}
+	cached := model.Order{OrderUUID: "uid-1", ETag: "e1"}
+	loaded := model.Order{OrderUUID: "uid-2"}
+
+	repo.EXPECT().ListOrderUIDs(ctx, f).Return([]string{"uid-1", "uid-2", "uid-gone"}, nil).Once()
+	cache.On("Get", "order:uid-1").Return(cached, nil).Once()
+	cache.On("Get", "order:uid-2").Return(model.Order{}, model.ErrNotFound).Once()
+	// Заказ пропал между выборкой UID и чтением — пропускается.
+	cache.On("Get", "order:uid-gone").Return(model.Order{}, model.ErrNotFound).Once()
+	cache.On("Generation", "order:uid-2").Return(uint64(1)).Once()
+	cache.On("Generation", "order:uid-gone").Return(uint64(0)).Once()
+	// Промахи кэша читаются одним запросом.
+	repo.EXPECT().GetOrders(ctx, []string{"uid-2", "uid-gone"}).Return([]model.Order{loaded}, nil).Once()
+	cache.On("Fill", "order:uid-2", loaded.WithETag(), uint64(1)).Return(true).Once()
+
+	got, err := svc.List(ctx, f)
+	require.NoError(t, err)
+	require.Equal(t, []model.Order{cached, loaded.WithETag()}, got)
+
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
+
+func Test_GetMany_BatchesMissesInRequestOrder(t *testing.T) {
+	ctx, svc, repo, cache := newTestService()
+
+	a := model.Order{OrderUUID: "a"}
+	b := model.Order{OrderUUID: "b", ETag: "eb"}
+	c := model.Order{OrderUUID: "c"}
+
+	cache.On("Get", "order:a").Return(model.Order{}, model.ErrNotFound).Once()
+	cache.On("Get", "order:b").Return(b, nil).Once()
+	cache.On("Get", "order:c").Return(model.Order{}, model.ErrNotFound).Once()
+	cache.On("Generation", "order:a").Return(uint64(1)).Once()
+	cache.On("Generation", "order:c").Return(uint64(2)).Once()
+	// БД отдаёт пачку в своём порядке; повторный "a" не запрашивается.
+	repo.EXPECT().GetOrders(ctx, []string{"a", "c"}).Return([]model.Order{c, a}, nil).Once()
+	cache.On("Fill", "order:a", a.WithETag(), uint64(1)).Return(true).Once()
+	cache.On("Fill", "order:c", c.WithETag(), uint64(2)).Return(true).Once()
+
+	got, err := svc.GetMany(ctx, []string{"a", "b", "a", "c"})
+	require.NoError(t, err)
+	require.Equal(t, []model.Order{a.WithETag(), b, c.WithETag()}, got)
+
+	repo.AssertExpectations(t)
+	cache.AssertExpectations(t)
+}
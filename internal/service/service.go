@@ -8,4 +8,11 @@ import (
 type Service interface {
 	ProcessOrder(ctx context.Context, order service.Order) error
 	Get(ctx context.Context, uuid string) (service.Order, error)
+	GetMany(ctx context.Context, uuids []string) ([]service.Order, error)
+	List(ctx context.Context, f service.OrderFilter) ([]service.Order, error)
+}
+
+// Publisher получает заказы после успешной записи (лента новых заказов).
+type Publisher interface {
+	Publish(ctx context.Context, order service.Order)
 }
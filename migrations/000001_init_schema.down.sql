@@ -1,4 +1,4 @@
-DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS items;
 DROP TABLE IF EXISTS payments;
 DROP TABLE IF EXISTS deliveries;
-DROP TABLE IF EXISTS orders;
\ No newline at end of file
+DROP TABLE IF EXISTS orders;
@@ -1,4 +1,4 @@
-CREATE TABLE orders (
+CREATE TABLE IF NOT EXISTS orders (
     order_uid TEXT PRIMARY KEY,
     track_number TEXT NOT NULL,
     entry TEXT NOT NULL,
@@ -12,7 +12,7 @@ CREATE TABLE orders (
     oof_shard TEXT
 );
 
-CREATE TABLE deliveries (
+CREATE TABLE IF NOT EXISTS deliveries (
                             id BIGSERIAL PRIMARY KEY,
                             order_uid TEXT NOT NULL
                                 REFERENCES orders(order_uid) ON DELETE CASCADE,
@@ -26,7 +26,7 @@ CREATE TABLE deliveries (
                             email TEXT
 );
 
-CREATE TABLE payments (
+CREATE TABLE IF NOT EXISTS payments (
                           id BIGSERIAL PRIMARY KEY,
                           order_uid TEXT NOT NULL
                               REFERENCES orders(order_uid) ON DELETE CASCADE,
@@ -43,7 +43,7 @@ CREATE TABLE payments (
                           custom_fee INTEGER
 );
 
-CREATE TABLE items (
+CREATE TABLE IF NOT EXISTS items (
                        id BIGSERIAL PRIMARY KEY,
                        order_uid TEXT NOT NULL
                            REFERENCES orders(order_uid) ON DELETE CASCADE,
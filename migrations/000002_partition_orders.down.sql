@@ -0,0 +1,118 @@
+-- Возвращает несекционированные таблицы. Заказы, уже вынесенные в архив
+-- (см. partitions.retention_months), не восстанавливаются.
+
+DROP TABLE IF EXISTS order_keys;
+
+ALTER TABLE items RENAME TO items_partitioned;
+ALTER TABLE payments RENAME TO payments_partitioned;
+ALTER TABLE deliveries RENAME TO deliveries_partitioned;
+ALTER TABLE orders RENAME TO orders_partitioned;
+
+ALTER INDEX orders_pkey RENAME TO orders_partitioned_pkey;
+ALTER INDEX deliveries_pkey RENAME TO deliveries_partitioned_pkey;
+ALTER INDEX payments_pkey RENAME TO payments_partitioned_pkey;
+ALTER INDEX items_pkey RENAME TO items_partitioned_pkey;
+ALTER SEQUENCE deliveries_id_seq RENAME TO deliveries_partitioned_id_seq;
+ALTER SEQUENCE payments_id_seq RENAME TO payments_partitioned_id_seq;
+ALTER SEQUENCE items_id_seq RENAME TO items_partitioned_id_seq;
+
+CREATE TABLE orders (
+    order_uid TEXT PRIMARY KEY,
+    track_number TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    locale TEXT,
+    internal_signature TEXT,
+    customer_id TEXT,
+    delivery_service TEXT,
+    shardkey TEXT,
+    sm_id INTEGER,
+    date_created TIMESTAMP NOT NULL,
+    oof_shard TEXT
+);
+
+CREATE TABLE deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    order_uid TEXT NOT NULL
+        REFERENCES orders(order_uid) ON DELETE CASCADE,
+
+    name TEXT NOT NULL,
+    phone TEXT,
+    zip TEXT,
+    city TEXT,
+    address TEXT,
+    region TEXT,
+    email TEXT
+);
+
+CREATE TABLE payments (
+    id BIGSERIAL PRIMARY KEY,
+    order_uid TEXT NOT NULL
+        REFERENCES orders(order_uid) ON DELETE CASCADE,
+
+    transaction TEXT,
+    request_id TEXT,
+    currency TEXT,
+    provider TEXT,
+    amount INTEGER,
+    payment_dt BIGINT,
+    bank TEXT,
+    delivery_cost INTEGER,
+    goods_total INTEGER,
+    custom_fee INTEGER
+);
+
+CREATE TABLE items (
+    id BIGSERIAL PRIMARY KEY,
+    order_uid TEXT NOT NULL
+        REFERENCES orders(order_uid) ON DELETE CASCADE,
+
+    chrt_id BIGINT,
+    track_number TEXT,
+    price INTEGER,
+    rid TEXT,
+    name TEXT,
+    sale INTEGER,
+    size TEXT,
+    total_price INTEGER,
+    nm_id BIGINT,
+    brand TEXT,
+    status INTEGER
+);
+
+INSERT INTO orders (
+    order_uid, track_number, entry, locale, internal_signature, customer_id,
+    delivery_service, shardkey, sm_id, date_created, oof_shard
+)
+SELECT DISTINCT ON (order_uid)
+       order_uid, track_number, entry, locale, internal_signature, customer_id,
+       delivery_service, shardkey, sm_id, date_created, oof_shard
+FROM orders_partitioned
+ORDER BY order_uid, date_created;
+
+INSERT INTO deliveries (order_uid, name, phone, zip, city, address, region, email)
+SELECT d.order_uid, d.name, d.phone, d.zip, d.city, d.address, d.region, d.email
+FROM deliveries_partitioned d
+JOIN orders o ON o.order_uid = d.order_uid AND o.date_created = d.date_created;
+
+INSERT INTO payments (
+    order_uid, transaction, request_id, currency, provider,
+    amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+)
+SELECT p.order_uid, p.transaction, p.request_id, p.currency, p.provider,
+       p.amount, p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+FROM payments_partitioned p
+JOIN orders o ON o.order_uid = p.order_uid AND o.date_created = p.date_created;
+
+INSERT INTO items (
+    order_uid, chrt_id, track_number, price, rid, name,
+    sale, size, total_price, nm_id, brand, status
+)
+SELECT i.order_uid, i.chrt_id, i.track_number, i.price, i.rid, i.name,
+       i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
+FROM items_partitioned i
+JOIN orders o ON o.order_uid = i.order_uid AND o.date_created = i.date_created;
+
+DROP TABLE items_partitioned;
+DROP TABLE payments_partitioned;
+DROP TABLE deliveries_partitioned;
+DROP TABLE orders_partitioned;
@@ -0,0 +1,169 @@
+-- Таблицы заказов секционируются по месяцам date_created. Ключ секционирования
+-- обязан входить в первичный ключ, поэтому date_created копируется в дочерние
+-- таблицы. Внешние ключи между секционированными таблицами мешают удалять
+-- старые секции, поэтому их нет: заказ пишется целиком в одной транзакции.
+-- Секции называются <table>_pYYYY_MM; DEFAULT-секция страхует запись, если
+-- maintenance job не успел создать секцию заранее.
+
+ALTER TABLE items RENAME TO items_legacy;
+ALTER TABLE payments RENAME TO payments_legacy;
+ALTER TABLE deliveries RENAME TO deliveries_legacy;
+ALTER TABLE orders RENAME TO orders_legacy;
+
+-- Освобождаем имена ключей и последовательностей для новых таблиц.
+ALTER INDEX orders_pkey RENAME TO orders_legacy_pkey;
+ALTER INDEX deliveries_pkey RENAME TO deliveries_legacy_pkey;
+ALTER INDEX payments_pkey RENAME TO payments_legacy_pkey;
+ALTER INDEX items_pkey RENAME TO items_legacy_pkey;
+ALTER SEQUENCE deliveries_id_seq RENAME TO deliveries_legacy_id_seq;
+ALTER SEQUENCE payments_id_seq RENAME TO payments_legacy_id_seq;
+ALTER SEQUENCE items_id_seq RENAME TO items_legacy_id_seq;
+
+CREATE TABLE orders (
+    order_uid TEXT NOT NULL,
+    track_number TEXT NOT NULL,
+    entry TEXT NOT NULL,
+    locale TEXT,
+    internal_signature TEXT,
+    customer_id TEXT,
+    delivery_service TEXT,
+    shardkey TEXT,
+    sm_id INTEGER,
+    date_created TIMESTAMP NOT NULL,
+    oof_shard TEXT,
+    PRIMARY KEY (order_uid, date_created)
+) PARTITION BY RANGE (date_created);
+
+CREATE TABLE deliveries (
+    id BIGSERIAL,
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    name TEXT NOT NULL,
+    phone TEXT,
+    zip TEXT,
+    city TEXT,
+    address TEXT,
+    region TEXT,
+    email TEXT,
+    PRIMARY KEY (id, date_created)
+) PARTITION BY RANGE (date_created);
+
+CREATE TABLE payments (
+    id BIGSERIAL,
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    transaction TEXT,
+    request_id TEXT,
+    currency TEXT,
+    provider TEXT,
+    amount INTEGER,
+    payment_dt BIGINT,
+    bank TEXT,
+    delivery_cost INTEGER,
+    goods_total INTEGER,
+    custom_fee INTEGER,
+    PRIMARY KEY (id, date_created)
+) PARTITION BY RANGE (date_created);
+
+CREATE TABLE items (
+    id BIGSERIAL,
+    order_uid TEXT NOT NULL,
+    date_created TIMESTAMP NOT NULL,
+
+    chrt_id BIGINT,
+    track_number TEXT,
+    price INTEGER,
+    rid TEXT,
+    name TEXT,
+    sale INTEGER,
+    size TEXT,
+    total_price INTEGER,
+    nm_id BIGINT,
+    brand TEXT,
+    status INTEGER,
+    PRIMARY KEY (id, date_created)
+) PARTITION BY RANGE (date_created);
+
+CREATE INDEX deliveries_order_uid_idx ON deliveries (order_uid);
+CREATE INDEX payments_order_uid_idx ON payments (order_uid);
+CREATE INDEX items_order_uid_idx ON items (order_uid);
+
+CREATE TABLE orders_default PARTITION OF orders DEFAULT;
+CREATE TABLE deliveries_default PARTITION OF deliveries DEFAULT;
+CREATE TABLE payments_default PARTITION OF payments DEFAULT;
+CREATE TABLE items_default PARTITION OF items DEFAULT;
+
+-- Секции от самого старого заказа до трёх месяцев вперёд.
+DO $$
+DECLARE
+    m   DATE;
+    t   TEXT;
+    upto DATE := date_trunc('month', now())::date + INTERVAL '3 months';
+BEGIN
+    m := COALESCE(
+        (SELECT date_trunc('month', min(date_created))::date FROM orders_legacy),
+        date_trunc('month', now())::date
+    );
+    WHILE m <= upto LOOP
+        FOREACH t IN ARRAY ARRAY['orders', 'deliveries', 'payments', 'items'] LOOP
+            EXECUTE format(
+                'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                t || '_p' || to_char(m, 'YYYY_MM'), t, m, (m + INTERVAL '1 month')::date
+            );
+        END LOOP;
+        m := (m + INTERVAL '1 month')::date;
+    END LOOP;
+END
+$$;
+
+INSERT INTO orders (
+    order_uid, track_number, entry, locale, internal_signature, customer_id,
+    delivery_service, shardkey, sm_id, date_created, oof_shard
+)
+SELECT order_uid, track_number, entry, locale, internal_signature, customer_id,
+       delivery_service, shardkey, sm_id, date_created, oof_shard
+FROM orders_legacy;
+
+INSERT INTO deliveries (order_uid, date_created, name, phone, zip, city, address, region, email)
+SELECT d.order_uid, o.date_created, d.name, d.phone, d.zip, d.city, d.address, d.region, d.email
+FROM deliveries_legacy d
+JOIN orders_legacy o USING (order_uid);
+
+INSERT INTO payments (
+    order_uid, date_created, transaction, request_id, currency, provider,
+    amount, payment_dt, bank, delivery_cost, goods_total, custom_fee
+)
+SELECT p.order_uid, o.date_created, p.transaction, p.request_id, p.currency, p.provider,
+       p.amount, p.payment_dt, p.bank, p.delivery_cost, p.goods_total, p.custom_fee
+FROM payments_legacy p
+JOIN orders_legacy o USING (order_uid);
+
+INSERT INTO items (
+    order_uid, date_created, chrt_id, track_number, price, rid, name,
+    sale, size, total_price, nm_id, brand, status
+)
+SELECT i.order_uid, o.date_created, i.chrt_id, i.track_number, i.price, i.rid, i.name,
+       i.sale, i.size, i.total_price, i.nm_id, i.brand, i.status
+FROM items_legacy i
+JOIN orders_legacy o USING (order_uid);
+
+-- order_uid в секционированной orders уникален только в пределах даты.
+-- order_keys — обычная таблица, по строке на UID: SetOrder вставляет в неё
+-- в той же транзакции, и повторный UID с другой датой получает нарушение
+-- уникальности. Строки не удаляются при архивации: UID остаётся занятым,
+-- и заказ не появится в БД второй раз рядом со своим архивом.
+CREATE TABLE order_keys (
+    order_uid TEXT PRIMARY KEY,
+    date_created TIMESTAMP NOT NULL
+);
+
+INSERT INTO order_keys (order_uid, date_created)
+SELECT order_uid, date_created
+FROM orders_legacy;
+
+DROP TABLE items_legacy;
+DROP TABLE payments_legacy;
+DROP TABLE deliveries_legacy;
+DROP TABLE orders_legacy;
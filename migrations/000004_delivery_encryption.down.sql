@@ -0,0 +1,17 @@
+-- Без ключей зашифрованные строки станут нечитаемыми: откат возможен,
+-- только когда все строки в открытом виде.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM deliveries WHERE key_id IS NOT NULL) THEN
+        RAISE EXCEPTION 'deliveries contain encrypted rows; rolling back would leave them unreadable';
+    END IF;
+END $$;
+
+DROP INDEX IF EXISTS deliveries_phone_bidx_idx;
+DROP INDEX IF EXISTS deliveries_email_bidx_idx;
+
+ALTER TABLE deliveries
+    DROP COLUMN phone_bidx,
+    DROP COLUMN email_bidx,
+    DROP COLUMN wrapped_dek,
+    DROP COLUMN key_id;
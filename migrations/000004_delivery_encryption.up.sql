@@ -0,0 +1,10 @@
+-- Шифрование персональных данных доставки (см. internal/fieldcrypt).
+-- key_id IS NULL — строка ещё в открытом виде; её зашифрует job перешифровки.
+ALTER TABLE deliveries
+    ADD COLUMN key_id TEXT,
+    ADD COLUMN wrapped_dek BYTEA,
+    ADD COLUMN email_bidx BYTEA,
+    ADD COLUMN phone_bidx BYTEA;
+
+CREATE INDEX deliveries_email_bidx_idx ON deliveries (email_bidx);
+CREATE INDEX deliveries_phone_bidx_idx ON deliveries (phone_bidx);
@@ -0,0 +1,18 @@
+-- Аудит удаления персональных данных по запросу субъекта. Несолёный хэш
+-- email или телефона перебирается по словарю за минуты, поэтому аудит
+-- хранит HMAC-SHA256 под ключом blind index (encryption.blind_index_key):
+-- по нему можно подтвердить, что запрос выполнен, но без ключа нельзя
+-- восстановить сами данные. Без шифрования email и телефон в аудит не
+-- пишутся вовсе.
+CREATE TABLE erasures (
+    id BIGSERIAL PRIMARY KEY,
+    requested_by TEXT NOT NULL,
+    customer_id TEXT,
+    email_hmac BYTEA,
+    phone_hmac BYTEA,
+    order_uids TEXT[] NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN erasures.email_hmac IS 'HMAC-SHA256 нормализованного email под ключом blind index; NULL без шифрования';
+COMMENT ON COLUMN erasures.phone_hmac IS 'HMAC-SHA256 нормализованного телефона под ключом blind index; NULL без шифрования';
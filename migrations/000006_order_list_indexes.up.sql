@@ -0,0 +1,4 @@
+-- Индексы для выборки заказов по фильтру (gRPC ListOrders): порядок выдачи
+-- от новых к старым с курсором (date_created, order_uid) и фильтр по клиенту.
+CREATE INDEX orders_date_created_uid_idx ON orders (date_created DESC, order_uid DESC);
+CREATE INDEX orders_customer_id_idx ON orders (customer_id);